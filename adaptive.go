@@ -0,0 +1,102 @@
+package powerset
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// BestEffort is an optional heuristic pruner, which skips subtrees it merely expects to be unpromising, so the members
+// of the family inside them are lost.  Prune is called at internal nodes of the powerset tree with the indices
+// included so far, the next index to decide, and a strength from 0, where it should prune nothing, to 1, where it
+// should prune as hard as it can, and returns true to skip the node's subtree
+type BestEffort struct {
+	Name  string
+	Prune func(included []int, next int, strength float64) bool
+}
+
+// WithAdaptivePruning makes Start run the pruners with a strength that it tunes as the search goes to emit about
+// target solutions per second, for sampling style exploration of spaces too large to enumerate.  the strength starts
+// at 0, and every 100ms it's raised a step while the search is emitting faster than target, and lowered a step while
+// it's emitting slower.  the current strength is reported in Stats.PruningStrength, and the subtrees each pruner skips
+// in Stats.PrunedBy, under its Name.  the pruners only run when the family's tree is walked, so the bitmask engines
+// are never picked with WithAutoEngine
+func WithAdaptivePruning(target float64, pruners ...BestEffort) Option {
+	return func(o *options) {
+		o.adaptive = &adaptivePruning{target: target, pruners: pruners}
+	}
+}
+
+type adaptivePruning struct {
+	target  float64
+	pruners []BestEffort
+}
+
+const (
+	// how long the emission rate is measured over before the strength is adjusted
+	adaptiveWindow = 100 * time.Millisecond
+
+	// how many adjustments take the strength from 0 to 1
+	adaptiveSteps = 10
+
+	// how many prune calls go by between looking at the clock
+	adaptiveCheckEvery = 256
+)
+
+// adaptiveController is the state of WithAdaptivePruning for a single search
+type adaptiveController struct {
+	*adaptivePruning
+
+	// the current strength in steps from 0 to adaptiveSteps, counted as an integer so that stepping up and back down
+	// returns to exactly 0, and atomic so Stats can read it while the search runs
+	steps atomic.Int32
+
+	calls         int
+	windowStart   time.Time
+	windowEmitted uint64
+}
+
+func newAdaptiveController(a *adaptivePruning) *adaptiveController {
+	return &adaptiveController{adaptivePruning: a, windowStart: time.Now()}
+}
+
+func (a *adaptiveController) currentStrength() float64 {
+	return float64(a.steps.Load()) / adaptiveSteps
+}
+
+// prune runs the pruners at an internal node, returning the name of the one that skips its subtree, if any.  emitted
+// is how many solutions the search has emitted so far, which the strength is tuned from
+func (a *adaptiveController) prune(included []int, next int, emitted uint64) (string, bool) {
+	a.calls++
+	if a.calls%adaptiveCheckEvery == 0 {
+		a.adjust(time.Now(), emitted)
+	}
+
+	strength := a.currentStrength()
+	if strength == 0 {
+		return "", false
+	}
+	for _, p := range a.pruners {
+		if p.Prune(included, next, strength) {
+			return p.Name, true
+		}
+	}
+	return "", false
+}
+
+// adjust moves the strength a step towards the target rate, once a window has passed
+func (a *adaptiveController) adjust(now time.Time, emitted uint64) {
+	elapsed := now.Sub(a.windowStart)
+	if elapsed < adaptiveWindow {
+		return
+	}
+	rate := float64(emitted-a.windowEmitted) / elapsed.Seconds()
+	steps := a.steps.Load()
+	switch {
+	case rate > a.target:
+		steps = min(adaptiveSteps, steps+1)
+	case rate < a.target:
+		steps = max(0, steps-1)
+	}
+	a.steps.Store(steps)
+	a.windowStart, a.windowEmitted = now, emitted
+}
@@ -0,0 +1,86 @@
+package powerset
+
+import (
+	"testing"
+	"time"
+)
+
+// a pruner that skips everything once it's switched on at all
+var pruneAll = BestEffort{
+	Name:  "everything",
+	Prune: func([]int, int, float64) bool { return true },
+}
+
+func TestAdaptivePruningRelaxed(t *testing.T) {
+	cfg := Config{LenItems: 8, MinSize: 2}
+	correct := collectFamily(t, cfg)
+
+	got, stats := collectStart(t, cfg, WithAdaptivePruning(1e12, pruneAll))
+	if len(got) != len(correct) {
+		t.Fatalf("expected the pruner to stay off below the target, got %d of %d subsets", len(got), len(correct))
+	}
+	if stats.PruningStrength != 0 || stats.PrunedBy["everything"] != 0 {
+		t.Fatalf("unexpected pruning %v, %v", stats.PruningStrength, stats.PrunedBy)
+	}
+}
+
+// a search emitting far faster than its target tightens its pruners until they cut it short
+func TestAdaptivePruningTightens(t *testing.T) {
+	out, ctl, err := Start(Config{LenItems: 40}, WithAdaptivePruning(1, pruneAll), WithAutoEngine())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	deadline := time.After(10 * time.Second)
+	for open := true; open; {
+		select {
+		case _, open = <-out:
+		case <-deadline:
+			ctl.Stop()
+			t.Fatalf("expected the pruner to end the search early")
+		}
+	}
+
+	stats := ctl.Stats()
+	if stats.PruningStrength == 0 || stats.PrunedBy["everything"] == 0 {
+		t.Fatalf("expected the pruner to have been switched on, got %v, %v", stats.PruningStrength, stats.PrunedBy)
+	}
+	if stats.Engine != EngineBranchAndBound {
+		t.Fatalf("expected the tree to be walked, got %v", stats.Engine)
+	}
+}
+
+func TestAdaptiveAdjust(t *testing.T) {
+	a := newAdaptiveController(&adaptivePruning{target: 100})
+	start := a.windowStart
+
+	a.adjust(start.Add(adaptiveWindow/2), 1000)
+	if a.currentStrength() != 0 {
+		t.Fatalf("expected no adjustment before a window has passed")
+	}
+	a.adjust(start.Add(time.Second), 1000)
+	if a.currentStrength() != 1.0/adaptiveSteps {
+		t.Fatalf("expected a step up, got %v", a.currentStrength())
+	}
+	a.adjust(start.Add(2*time.Second), 1010)
+	if a.currentStrength() != 0 {
+		t.Fatalf("expected a step down, got %v", a.currentStrength())
+	}
+	a.adjust(start.Add(3*time.Second), 1010)
+	if a.currentStrength() != 0 {
+		t.Fatalf("expected the strength to stay at 0, got %v", a.currentStrength())
+	}
+
+	// going up and back down several steps lands on exactly 0 again
+	now := start.Add(3 * time.Second)
+	for i := 0; i < 3; i++ {
+		now = now.Add(time.Second)
+		a.adjust(now, a.windowEmitted+1000)
+	}
+	for i := 0; i < 3; i++ {
+		now = now.Add(time.Second)
+		a.adjust(now, a.windowEmitted)
+	}
+	if a.currentStrength() != 0 {
+		t.Fatalf("expected the strength to be back at 0, got %v", a.currentStrength())
+	}
+}
@@ -0,0 +1,258 @@
+package powerset
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+	"sort"
+	"strings"
+)
+
+// Analysis is what Analyze can tell about a Config without generating its family
+type Analysis struct {
+	// the family described by the size bounds and the required and forbidden indices alone
+	Base Bounds
+
+	// one step for each of the Config's Constraints, in order, and then one named "Shard" for its Shard, if it has one
+	Steps []Step
+
+	// sets of constraint names that no member of the base family satisfies together.  a constraint that can't be
+	// satisfied by itself is a set of one, and pairs are only reported when both of their constraints can be satisfied
+	Conflicts [][]string
+
+	// the constraint names in the order that should reject non-members soonest: the most selective parsed
+	// constraints first, then the ones that couldn't be counted, in their original order
+	Order []string
+
+	// why the Config is invalid, in which case nothing else is filled in
+	Err error
+}
+
+// Step is the size of a family after one of its constraints is applied
+type Step struct {
+	Name string
+
+	// the members of the family that satisfy this constraint and every constraint before it
+	Size Bounds
+
+	// the members of the base family that satisfy this constraint by itself
+	Alone Bounds
+}
+
+// Bounds are the smallest and largest number of subsets a family could have, inclusive
+type Bounds struct {
+	Lo, Hi *big.Int
+}
+
+// Exact reports whether the bounds pin down the size of the family
+func (b Bounds) Exact() bool {
+	return b.Lo.Cmp(b.Hi) == 0
+}
+
+func (b Bounds) String() string {
+	if b.Exact() {
+		return b.Lo.String()
+	}
+	return fmt.Sprintf("%v..%v", b.Lo, b.Hi)
+}
+
+// the most free indices that the constraints being counted may refer to between them, since counting tries every
+// combination of them
+const analyzeMaxRefs = 16
+
+// Analyze sizes up the family described by cfg without generating it, so a configuration can be sanity checked before
+// an expensive run.  constraints made by ParseConstraint are counted exactly from the indices they refer to, as long as
+// there aren't more than 16 of them at once, and the rest, like constraints with only an Allow function and the shard,
+// can only bound the family from above
+func Analyze(cfg Config) Analysis {
+	fam, err := cfg.compile()
+	if err != nil {
+		return Analysis{Err: err}
+	}
+
+	base, _ := fam.countExprs(nil)
+	an := Analysis{
+		Base:  Bounds{Lo: base, Hi: base},
+		Steps: make([]Step, len(fam.constraints)),
+	}
+
+	// once a constraint couldn't be counted, every step after it is only bounded
+	exprs := []exprNode{}
+	bounded := false
+	prev := base
+	for i, c := range fam.constraints {
+		step := Step{
+			Name:  c.Name,
+			Size:  Bounds{Lo: new(big.Int), Hi: prev},
+			Alone: Bounds{Lo: new(big.Int), Hi: base},
+		}
+		if c.expr != nil {
+			if alone, ok := fam.countExprs([]exprNode{c.expr}); ok {
+				step.Alone = Bounds{Lo: alone, Hi: alone}
+			}
+			exprs = append(exprs, c.expr)
+		}
+
+		size, ok := fam.countExprs(exprs)
+		if c.expr == nil || !ok {
+			bounded = true
+		}
+		if ok {
+			step.Size.Hi = size
+			if !bounded {
+				step.Size.Lo = size
+			}
+		}
+		prev = step.Size.Hi
+		an.Steps[i] = step
+	}
+
+	if base.Sign() > 0 {
+		an.Conflicts = fam.conflicts(fam.constraints, an.Steps)
+	}
+	an.Order = suggestOrder(an.Steps[:len(cfg.Constraints)])
+	return an
+}
+
+// conflicts finds the constraints that can't be satisfied by themselves, and the pairs of the others that can't be
+// satisfied together
+func (fam *family) conflicts(constraints []Constraint, steps []Step) [][]string {
+	found := [][]string{}
+	satisfiable := []int{}
+	for i, c := range constraints {
+		if c.expr == nil || !steps[i].Alone.Exact() {
+			continue
+		}
+		if steps[i].Alone.Lo.Sign() == 0 {
+			found = append(found, []string{c.Name})
+		} else {
+			satisfiable = append(satisfiable, i)
+		}
+	}
+
+	for a := 0; a < len(satisfiable); a++ {
+		for b := a + 1; b < len(satisfiable); b++ {
+			x, y := constraints[satisfiable[a]], constraints[satisfiable[b]]
+			if both, ok := fam.countExprs([]exprNode{x.expr, y.expr}); ok && both.Sign() == 0 {
+				found = append(found, []string{x.Name, y.Name})
+			}
+		}
+	}
+	if len(found) == 0 {
+		return nil
+	}
+	return found
+}
+
+// suggestOrder puts the constraints whose sizes are known in order of how few subsets they let through
+func suggestOrder(steps []Step) []string {
+	sorted := make([]Step, len(steps))
+	copy(sorted, steps)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i].Alone, sorted[j].Alone
+		if a.Exact() != b.Exact() {
+			return a.Exact()
+		}
+		return a.Exact() && a.Lo.Cmp(b.Lo) < 0
+	})
+
+	names := make([]string, len(sorted))
+	for i, step := range sorted {
+		names[i] = step.Name
+	}
+	return names
+}
+
+// countExprs counts the members of the family that satisfy every one of exprs.  the indices the expressions don't
+// refer to are interchangeable, so it's enough to try every combination of the free indices they do refer to, and count
+// the ways of filling in the rest for each subset size.  ok is false when they refer to too many free indices
+func (fam *family) countExprs(exprs []exprNode) (count *big.Int, ok bool) {
+	count = new(big.Int)
+	if fam.empty {
+		return count, true
+	}
+
+	seen := make([]bool, fam.lenItems)
+	for _, n := range exprs {
+		exprRefs(n, seen)
+	}
+	refs := []int{}
+	for idx, m := range fam.members {
+		if seen[idx] && m == free {
+			refs = append(refs, idx)
+		}
+	}
+	if len(refs) > analyzeMaxRefs {
+		return nil, false
+	}
+
+	st := &exprState{in: make([]bool, fam.lenItems), next: fam.lenItems}
+	for idx, m := range fam.members {
+		st.in[idx] = m == required
+	}
+
+	rest := fam.numFree - len(refs)
+	lo, hi := fam.sizeRange()
+	for mask := uint64(0); mask < 1<<len(refs); mask++ {
+		for i, idx := range refs {
+			st.in[idx] = mask&(1<<i) != 0
+		}
+		included := fam.numRequired + bits.OnesCount64(mask)
+
+		for k := lo; k <= hi; k++ {
+			ways := binomial(rest, k-included)
+			if ways.Sign() == 0 {
+				continue
+			}
+			st.count = k
+			if allTrue(exprs, st) {
+				count.Add(count, ways)
+			}
+		}
+	}
+	return count, true
+}
+
+func allTrue(exprs []exprNode, st *exprState) bool {
+	for _, n := range exprs {
+		if n.eval(st) != triTrue {
+			return false
+		}
+	}
+	return true
+}
+
+// exprRefs marks the indices that an expression refers to
+func exprRefs(n exprNode, seen []bool) {
+	switch n := n.(type) {
+	case exprItem:
+		if int(n) < len(seen) {
+			seen[n] = true
+		}
+	case exprNot:
+		exprRefs(n.x, seen)
+	case exprBinary:
+		exprRefs(n.x, seen)
+		exprRefs(n.y, seen)
+	}
+}
+
+// String lays the analysis out for a terminal
+func (an Analysis) String() string {
+	if an.Err != nil {
+		return fmt.Sprintf("invalid config: %v\n", an.Err)
+	}
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "base family: %v\n", an.Base)
+	for _, step := range an.Steps {
+		fmt.Fprintf(b, "+ %s: %v (alone %v)\n", step.Name, step.Size, step.Alone)
+	}
+	for _, names := range an.Conflicts {
+		fmt.Fprintf(b, "conflict: %s\n", strings.Join(names, ", "))
+	}
+	if len(an.Order) > 1 {
+		fmt.Fprintf(b, "suggested order: %s\n", strings.Join(an.Order, ", "))
+	}
+	return b.String()
+}
@@ -0,0 +1,177 @@
+package powerset
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func mustParse(t *testing.T, expr string, items *Items) Constraint {
+	c, err := ParseConstraint(expr, items)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return c
+}
+
+func TestAnalyzeExact(t *testing.T) {
+	items, _ := NewItems("a", "b", "c", "d", "e", "f")
+	cfg := Config{
+		LenItems: 6,
+		MaxSize:  4,
+		Required: []int{5},
+		Constraints: []Constraint{
+			mustParse(t, "a -> b", items),
+			mustParse(t, "count(..3) | c", items),
+			mustParse(t, "!(d & e)", items),
+		},
+	}
+
+	an := Analyze(cfg)
+	if an.Err != nil {
+		t.Fatalf("unexpected error: %v", an.Err)
+	}
+	base := len(collectFamily(t, Config{LenItems: 6, MaxSize: 4, Required: []int{5}}))
+	if !an.Base.Exact() || an.Base.Lo.Int64() != int64(base) {
+		t.Fatalf("base is %v, expected %d", an.Base, base)
+	}
+
+	for i, step := range an.Steps {
+		prefix := cfg
+		prefix.Constraints = cfg.Constraints[:i+1]
+		correct := int64(len(collectFamily(t, prefix)))
+		if !step.Size.Exact() || step.Size.Lo.Int64() != correct {
+			t.Fatalf("step %d is %v, expected %d", i, step.Size, correct)
+		}
+
+		alone := cfg
+		alone.Constraints = cfg.Constraints[i : i+1]
+		correct = int64(len(collectFamily(t, alone)))
+		if !step.Alone.Exact() || step.Alone.Lo.Int64() != correct {
+			t.Fatalf("step %d alone is %v, expected %d", i, step.Alone, correct)
+		}
+	}
+	if an.Conflicts != nil {
+		t.Fatalf("unexpected conflicts %v", an.Conflicts)
+	}
+}
+
+func TestAnalyzeBounded(t *testing.T) {
+	items, _ := NewItems("a", "b", "c", "d")
+	even := Constraint{Name: "even", Allow: func(indices []int) bool { return len(indices)%2 == 0 }}
+	cfg := Config{
+		LenItems:    4,
+		Constraints: []Constraint{mustParse(t, "a | b", items), even, mustParse(t, "c", items)},
+	}
+
+	an := Analyze(cfg)
+	sizes := []Bounds{}
+	for _, step := range an.Steps {
+		sizes = append(sizes, step.Size)
+	}
+	correct := []Bounds{
+		{big.NewInt(12), big.NewInt(12)},
+		{big.NewInt(0), big.NewInt(12)},
+		{big.NewInt(0), big.NewInt(6)},
+	}
+	if !reflect.DeepEqual(sizes, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", sizes, correct)
+	}
+	if an.Steps[1].Alone.Exact() {
+		t.Fatalf("an Allow function can't be counted, got %v", an.Steps[1].Alone)
+	}
+}
+
+func TestAnalyzeConflicts(t *testing.T) {
+	items, _ := NewItems("a", "b", "c")
+	cfg := Config{
+		LenItems: 3,
+		MaxSize:  2,
+		Constraints: []Constraint{
+			mustParse(t, "a", items),
+			mustParse(t, "count(3)", items),
+			mustParse(t, "!a", items),
+			mustParse(t, "b | c", items),
+		},
+	}
+
+	an := Analyze(cfg)
+	correct := [][]string{{"count(3)"}, {"a", "!a"}}
+	if !reflect.DeepEqual(an.Conflicts, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", an.Conflicts, correct)
+	}
+	if an.Steps[3].Size.Lo.Sign() != 0 {
+		t.Fatalf("expected an empty family, got %v", an.Steps[3].Size)
+	}
+}
+
+func TestAnalyzeOrder(t *testing.T) {
+	items, _ := NewItems("a", "b", "c", "d")
+	anything := Constraint{Name: "anything", Allow: func([]int) bool { return true }}
+	cfg := Config{
+		LenItems: 4,
+		Constraints: []Constraint{
+			anything,
+			mustParse(t, "a | b", items),
+			mustParse(t, "a & b & c", items),
+			mustParse(t, "a", items),
+		},
+	}
+
+	an := Analyze(cfg)
+	correct := []string{"a & b & c", "a", "a | b", "anything"}
+	if !reflect.DeepEqual(an.Order, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", an.Order, correct)
+	}
+}
+
+func TestAnalyzeTooManyRefs(t *testing.T) {
+	expr := "0"
+	for i := 1; i < 20; i++ {
+		expr += " | " + string(rune('a'+i))
+	}
+	names := make([]string, 20)
+	for i := range names {
+		names[i] = string(rune('a' + i))
+	}
+	items, _ := NewItems(names...)
+	cfg := Config{LenItems: 20, Constraints: []Constraint{mustParse(t, expr, items)}}
+
+	an := Analyze(cfg)
+	step := an.Steps[0]
+	if step.Size.Exact() || step.Size.Hi.Cmp(an.Base.Hi) != 0 {
+		t.Fatalf("expected the size to be bounded by the base family, got %v", step.Size)
+	}
+}
+
+func TestAnalyzeInvalid(t *testing.T) {
+	an := Analyze(Config{LenItems: 2, Required: []int{2}})
+	if an.Err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestAnalyzeShard(t *testing.T) {
+	items, _ := NewItems("a", "b", "c", "d")
+	cfg := Config{
+		LenItems:    4,
+		Constraints: []Constraint{mustParse(t, "c", items)},
+		Shard:       &ShardSpec{Count: 4, Index: 1},
+		Order:       OrderGray,
+	}
+
+	an := Analyze(cfg)
+	if an.Err != nil {
+		t.Fatalf("unexpected error: %v", an.Err)
+	}
+	if len(an.Steps) != 2 || an.Steps[1].Name != "Shard" {
+		t.Fatalf("expected a Shard step after the constraint, got %+v", an.Steps)
+	}
+	correct := Bounds{big.NewInt(0), big.NewInt(8)}
+	if !reflect.DeepEqual(an.Steps[1].Size, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", an.Steps[1].Size, correct)
+	}
+	if !reflect.DeepEqual(an.Order, []string{"c"}) {
+		t.Fatalf("the shard isn't a constraint to reorder, got %v", an.Order)
+	}
+}
@@ -0,0 +1,40 @@
+package powerset
+
+import (
+	"runtime"
+	"time"
+)
+
+const (
+	// how many nodes a background search processes between yields to the scheduler
+	backgroundInterval = 256
+
+	// a yield that takes longer than this means other goroutines were waiting to run
+	backgroundContended = 20 * time.Microsecond
+
+	// the longest a background search sleeps after a contended yield
+	backgroundMaxSleep = 2 * time.Millisecond
+)
+
+// backgroundThrottle slows a search down while the rest of the program is busy.  every so often it yields the
+// processor, and if the yield took a while, other goroutines were runnable and got to run, so the search backs off with
+// a sleep of the same length before continuing.  an idle program sees almost no slowdown
+type backgroundThrottle struct {
+	nodes int
+}
+
+func (bt *backgroundThrottle) tick() {
+	bt.nodes++
+	if bt.nodes%backgroundInterval != 0 {
+		return
+	}
+
+	start := time.Now()
+	runtime.Gosched()
+	if yielded := time.Since(start); yielded > backgroundContended {
+		if yielded > backgroundMaxSleep {
+			yielded = backgroundMaxSleep
+		}
+		time.Sleep(yielded)
+	}
+}
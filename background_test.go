@@ -0,0 +1,25 @@
+package powerset
+
+import "testing"
+
+func TestBackground(t *testing.T) {
+	out, _, _ := Family(Config{LenItems: 12}, WithBackground())
+
+	count := 0
+	for range out {
+		count++
+	}
+	if count != 1<<12 {
+		t.Fatalf("background search emitted %d subsets, expected %d", count, 1<<12)
+	}
+}
+
+func TestBackgroundThrottleYields(t *testing.T) {
+	bt := &backgroundThrottle{}
+	for i := 0; i < backgroundInterval*4; i++ {
+		bt.tick()
+	}
+	if bt.nodes != backgroundInterval*4 {
+		t.Fatalf("throttle counted %d nodes", bt.nodes)
+	}
+}
@@ -0,0 +1,59 @@
+package powerset
+
+import (
+	"math/big"
+	"sync"
+)
+
+// the rows of Pascal's triangle that are cached, which covers every binomial of up to 256 items in about 2MB.  larger
+// ones are computed each time, since their rows would take far more memory than they'd save
+const binomialCacheRows = 257
+
+// binomials is Pascal's triangle, shared by every search and grown lazily a row at a time, so rank, unrank and shard
+// heavy workloads compute each coefficient once per process.  nothing is precomputed or persisted between runs: a row
+// of 256 items takes microseconds to add, which is less than reading it back from disk would
+var binomials struct {
+	mu   sync.RWMutex
+	rows [][]*big.Int
+}
+
+var bigZero = new(big.Int)
+
+// binomial returns n choose k as a big.Int, or zero when k is out of range.  the result may be shared, so it must not
+// be modified
+func binomial(n, k int) *big.Int {
+	if k < 0 || n < 0 || k > n {
+		return bigZero
+	}
+	if n >= binomialCacheRows {
+		return new(big.Int).Binomial(int64(n), int64(k))
+	}
+	return binomialRow(n)[k]
+}
+
+// binomialRow returns row n of Pascal's triangle, adding the rows up to it if they haven't been yet
+func binomialRow(n int) []*big.Int {
+	binomials.mu.RLock()
+	if n < len(binomials.rows) {
+		row := binomials.rows[n]
+		binomials.mu.RUnlock()
+		return row
+	}
+	binomials.mu.RUnlock()
+
+	binomials.mu.Lock()
+	defer binomials.mu.Unlock()
+	for len(binomials.rows) <= n {
+		prev := []*big.Int(nil)
+		if m := len(binomials.rows); m > 0 {
+			prev = binomials.rows[m-1]
+		}
+		row := make([]*big.Int, len(prev)+1)
+		row[0], row[len(prev)] = bigOne, bigOne
+		for k := 1; k < len(prev); k++ {
+			row[k] = new(big.Int).Add(prev[k-1], prev[k])
+		}
+		binomials.rows = append(binomials.rows, row)
+	}
+	return binomials.rows[n]
+}
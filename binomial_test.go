@@ -0,0 +1,39 @@
+package powerset
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+)
+
+func TestBinomial(t *testing.T) {
+	for _, n := range []int{0, 1, 7, 64, 256, 300} {
+		for k := -1; k <= n+1; k++ {
+			correct := new(big.Int)
+			if k >= 0 && k <= n {
+				correct.Binomial(int64(n), int64(k))
+			}
+			if got := binomial(n, k); got.Cmp(correct) != 0 {
+				t.Fatalf("C(%d, %d) = %v, expected %v", n, k, got, correct)
+			}
+		}
+	}
+}
+
+// the table is grown safely by concurrent callers
+func TestBinomialConcurrent(t *testing.T) {
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for n := 200 - i; n >= 0; n -= 8 {
+				correct := new(big.Int).Binomial(int64(n), int64(n/2))
+				if got := binomial(n, n/2); got.Cmp(correct) != 0 {
+					t.Errorf("C(%d, %d) = %v, expected %v", n, n/2, got, correct)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
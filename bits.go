@@ -0,0 +1,40 @@
+package powerset
+
+import "math/bits"
+
+// helpers for subsets stored as bit masks, where bit i of words[i/w] is set when index i is included, for words of w
+// bits.  Masks and the conversions in convert.go use these, so the bit order is defined in one place.  they're plain
+// math/bits without architecture specific paths: the compiler already turns bits.TrailingZeros64 and
+// bits.OnesCount64 into single instructions, and assembly only wins when it counts long runs of words at once, which
+// nothing in the package does
+
+// forEachSet calls fn with each included index of a mask in increasing order, until fn returns false.  it only visits
+// the set bits, clearing the lowest one at a time, so sparse masks are cheap.  the words may be uint64s, or the
+// big.Words of a big.Int
+func forEachSet[W ~uint | ~uint64](words []W, fn func(idx int) bool) bool {
+	// the number of bits in a word, from the highest bit of the all ones word
+	size := bits.Len64(uint64(^W(0)))
+	for i, w := range words {
+		for w != 0 {
+			if !fn(i*size + bits.TrailingZeros64(uint64(w))) {
+				return false
+			}
+			w &= w - 1
+		}
+	}
+	return true
+}
+
+// maskRank returns the rank of a subset of lenItems <= 64 items given as a mask.  ranks make index 0 the most
+// significant bit while masks make it the least, so the rank is the mask reversed
+func maskRank(mask uint64, lenItems int) uint64 {
+	if lenItems == 0 {
+		return 0
+	}
+	return bits.Reverse64(mask) >> (64 - lenItems)
+}
+
+// rankMask is the inverse of maskRank
+func rankMask(rank uint64, lenItems int) uint64 {
+	return maskRank(rank, lenItems)
+}
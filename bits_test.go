@@ -0,0 +1,48 @@
+package powerset
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestForEachSet(t *testing.T) {
+	words := []uint64{1<<0 | 1<<5 | 1<<63, 0, 1 << 2}
+	seen := []int{}
+	forEachSet(words, func(idx int) bool {
+		seen = append(seen, idx)
+		return true
+	})
+	if correct := []int{0, 5, 63, 130}; !reflect.DeepEqual(seen, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", seen, correct)
+	}
+
+	count := 0
+	if forEachSet(words, func(int) bool { count++; return count < 2 }) || count != 2 {
+		t.Fatalf("expected to stop after 2 indices, got %d", count)
+	}
+
+	// the words of a big.Int are walked the same way
+	x := new(big.Int).SetBit(new(big.Int), 130, 1)
+	x.SetBit(x, 3, 1)
+	seen = []int{}
+	forEachSet(x.Bits(), func(idx int) bool {
+		seen = append(seen, idx)
+		return true
+	})
+	if correct := []int{3, 130}; !reflect.DeepEqual(seen, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", seen, correct)
+	}
+}
+
+func TestMaskRank(t *testing.T) {
+	// index 0 is the most significant bit of a rank
+	if rank := maskRank(1<<0|1<<3, 5); rank != 0b10010 {
+		t.Fatalf("rank %b, expected 10010", rank)
+	}
+	for rank := uint64(0); rank < 32; rank++ {
+		if maskRank(rankMask(rank, 5), 5) != rank {
+			t.Fatalf("rank %d doesn't round trip", rank)
+		}
+	}
+}
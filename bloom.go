@@ -0,0 +1,52 @@
+package powerset
+
+import "math"
+
+// bloomFilter is a fixed size Bloom filter over subset hashes
+type bloomFilter struct {
+	bits      []uint64
+	numBits   uint64
+	numHashes int
+}
+
+// newBloomFilter sizes a filter to hold expected items with a false positive rate of fpRate, using the standard
+// m = -n*ln(p)/ln(2)^2 bits and k = m/n*ln(2) hash functions
+func newBloomFilter(expected uint64, fpRate float64) *bloomFilter {
+	if expected == 0 {
+		expected = 1
+	}
+	if fpRate <= 0 || fpRate >= 1 {
+		fpRate = 0.01
+	}
+
+	numBits := uint64(math.Ceil(-float64(expected) * math.Log(fpRate) / (math.Ln2 * math.Ln2)))
+	if numBits < 64 {
+		numBits = 64
+	}
+	numHashes := int(math.Round(float64(numBits) / float64(expected) * math.Ln2))
+	if numHashes < 1 {
+		numHashes = 1
+	}
+
+	return &bloomFilter{
+		bits:      make([]uint64, (numBits+63)/64),
+		numBits:   numBits,
+		numHashes: numHashes,
+	}
+}
+
+// testAndAdd adds the hash to the filter, reporting whether it was probably already there
+func (bf *bloomFilter) testAndAdd(h uint64) bool {
+	// double hashing derives all k bit positions from two independent hashes
+	h1, h2 := h, mix64(h)|1
+	present := true
+	for i := 0; i < bf.numHashes; i++ {
+		bit := (h1 + uint64(i)*h2) % bf.numBits
+		word, mask := bit/64, uint64(1)<<(bit%64)
+		if bf.bits[word]&mask == 0 {
+			present = false
+			bf.bits[word] |= mask
+		}
+	}
+	return present
+}
@@ -0,0 +1,51 @@
+package powerset
+
+import "testing"
+
+func TestBloomFilter(t *testing.T) {
+	bf := newBloomFilter(1000, 0.01)
+	for i := 0; i < 1000; i++ {
+		bf.testAndAdd(HashSubset([]int{i}))
+	}
+	for i := 0; i < 1000; i++ {
+		if !bf.testAndAdd(HashSubset([]int{i})) {
+			t.Fatalf("%d was added but isn't present", i)
+		}
+	}
+
+	// test fresh subsets against copies of the filter, so the filter doesn't fill up as we go
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		clone := *bf
+		clone.bits = append([]uint64{}, bf.bits...)
+		if clone.testAndAdd(HashSubset([]int{i, i + 1})) {
+			falsePositives++
+		}
+	}
+	if falsePositives > 200 {
+		t.Fatalf("%d false positives out of 10000 is well above the 1%% target", falsePositives)
+	}
+}
+
+func TestFamilyBloomDedup(t *testing.T) {
+	cfg := Config{LenItems: 10}
+	out, _, _ := Family(cfg, WithBloomDedup(1<<10, 1e-9))
+
+	seen := map[uint64]bool{}
+	for indices := range out {
+		seen[HashSubset(indices)] = true
+	}
+	if len(seen) != 1<<10 {
+		t.Fatalf("expected every distinct subset, got %d", len(seen))
+	}
+}
+
+func TestEmitterBloomDedup(t *testing.T) {
+	e := newEmitter(buildOptions([]Option{WithBloomDedup(100, 0.001)}))
+	if _, ok := e.accept([]int{0, 2}); !ok {
+		t.Fatalf("first emission was suppressed")
+	}
+	if _, ok := e.accept([]int{0, 2}); ok {
+		t.Fatalf("duplicate emission wasn't suppressed")
+	}
+}
@@ -0,0 +1,67 @@
+package powerset
+
+import "sort"
+
+// JaccardDistance returns 1 - |a∩b|/|a∪b|, which is 0 for identical subsets and 1 for disjoint ones.  two empty
+// subsets are identical.  a and b must be sorted
+func JaccardDistance(a, b []int) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+
+	common := 0
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			common++
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	union := len(a) + len(b) - common
+	return 1 - float64(common)/float64(union)
+}
+
+// Cluster is a group of similar subsets, summarized by the first subset that was added to it
+type Cluster struct {
+	Representative []int
+	Size           uint64
+}
+
+// Clusters is a Sink that groups similar subsets together, for summarizing thousands of near identical solutions.  it
+// uses streaming threshold clustering: each subset joins the first cluster whose representative is within the
+// threshold Jaccard distance, and otherwise starts a new cluster that it represents.  the clusters depend on the order
+// subsets arrive in, but every subset is within the threshold of its cluster's representative
+type Clusters struct {
+	threshold float64
+	clusters  []Cluster
+}
+
+// NewClusters creates an empty set of clusters, where subsets within threshold Jaccard distance of a representative
+// belong to its cluster
+func NewClusters(threshold float64) *Clusters {
+	return &Clusters{threshold: threshold}
+}
+
+// Add assigns a subset to a cluster
+func (c *Clusters) Add(subset []int) {
+	sorted := append([]int{}, subset...)
+	sort.Ints(sorted)
+
+	for i := range c.clusters {
+		if JaccardDistance(c.clusters[i].Representative, sorted) <= c.threshold {
+			c.clusters[i].Size++
+			return
+		}
+	}
+	c.clusters = append(c.clusters, Cluster{Representative: sorted, Size: 1})
+}
+
+// Clusters returns the clusters found so far, in the order they were started
+func (c *Clusters) Clusters() []Cluster {
+	return append([]Cluster{}, c.clusters...)
+}
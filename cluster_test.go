@@ -0,0 +1,57 @@
+package powerset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJaccardDistance(t *testing.T) {
+	checks := []struct {
+		a, b     []int
+		distance float64
+	}{
+		{[]int{}, []int{}, 0},
+		{[]int{0, 1}, []int{0, 1}, 0},
+		{[]int{0, 1}, []int{2, 3}, 1},
+		{[]int{0, 1, 2}, []int{1, 2, 3}, 0.5},
+		{[]int{}, []int{4}, 1},
+	}
+	for _, check := range checks {
+		if d := JaccardDistance(check.a, check.b); d != check.distance {
+			t.Fatalf("%v, %v: distance %v != %v", check.a, check.b, d, check.distance)
+		}
+	}
+}
+
+func TestClusters(t *testing.T) {
+	c := NewClusters(0.5)
+	for _, subset := range [][]int{
+		{0, 1, 2},
+		{5, 6},
+		{2, 1, 3},
+		{0, 1},
+		{5, 6, 7},
+		{9},
+	} {
+		c.Add(subset)
+	}
+
+	correct := []Cluster{
+		{Representative: []int{0, 1, 2}, Size: 3},
+		{Representative: []int{5, 6}, Size: 2},
+		{Representative: []int{9}, Size: 1},
+	}
+	if !reflect.DeepEqual(correct, c.Clusters()) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", c.Clusters(), correct)
+	}
+}
+
+func TestClustersZeroThreshold(t *testing.T) {
+	// a threshold of zero only groups identical subsets, so every subset of a powerset is its own cluster
+	c := NewClusters(0)
+	out, _ := VariableSize(4)
+	Drain(out, c)
+	if len(c.Clusters()) != 16 {
+		t.Fatalf("expected 16 clusters, got %d", len(c.Clusters()))
+	}
+}
@@ -0,0 +1,81 @@
+package powerset
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+)
+
+// ErrTooLarge is wrapped by the error Collect returns for a family that might not fit within the collect limit
+var ErrTooLarge = errors.New("powerset: family is too large to collect")
+
+// CollectLimitEnv is the environment variable that sets the most subsets Collect will return, so a CI machine can
+// be protected from a test that accidentally asks for 2^64 subsets.  it defaults to DefaultCollectLimit
+const CollectLimitEnv = "POWERSET_COLLECT_LIMIT"
+
+// DefaultCollectLimit is the collect limit when CollectLimitEnv isn't set
+const DefaultCollectLimit = 1 << 20
+
+// MustSmall returns n, and panics if the powerset of n items has more than limit subsets.  it's a guard for tests,
+// like FixedSize(MustSmall(n, 1000)), that fails loudly when n is much larger than intended instead of hanging
+func MustSmall(n int, limit int) int {
+	if n < 0 || (n < 63 && 1<<n <= limit) {
+		return n
+	}
+	panic(fmt.Sprintf("powerset: the powerset of %d items has %v subsets, more than the limit of %d", n,
+		new(big.Int).Lsh(bigOne, uint(n)), limit))
+}
+
+// Collect returns every member of the family described by cfg, in the order of Family.  before it generates anything,
+// it bounds the size of the family, and returns an error wrapping ErrTooLarge if the family could have more subsets
+// than the limit set by CollectLimitEnv.  the bound is exact for the size bounds, required and forbidden indices and
+// parsed constraints, but constraints with only an Allow function can't be counted, so they don't lower it.  Collect
+// understands the same options as Family
+func Collect(cfg Config, opts ...Option) ([][]int, error) {
+	limit, err := collectLimit()
+	if err != nil {
+		return nil, err
+	}
+	fam, err := cfg.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	exprs := make([]exprNode, len(fam.exprs))
+	for i, c := range fam.exprs {
+		exprs[i] = c.expr
+	}
+	bound, ok := fam.countExprs(exprs)
+	if !ok {
+		bound, _ = fam.countExprs(nil)
+	}
+	if bound.Cmp(new(big.Int).SetUint64(limit)) > 0 {
+		return nil, fmt.Errorf("%w: it could have %v subsets, more than the limit of %d, which %s raises", ErrTooLarge,
+			bound, limit, CollectLimitEnv)
+	}
+
+	out, _, err := Family(cfg, opts...)
+	if err != nil {
+		return nil, err
+	}
+	all := [][]int{}
+	for subset := range out {
+		all = append(all, subset)
+	}
+	return all, nil
+}
+
+// collectLimit reads the collect limit from the environment
+func collectLimit() (uint64, error) {
+	value, ok := os.LookupEnv(CollectLimitEnv)
+	if !ok || value == "" {
+		return DefaultCollectLimit, nil
+	}
+	limit, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("powerset: %s must be a number of subsets, got %q", CollectLimitEnv, value)
+	}
+	return limit, nil
+}
@@ -0,0 +1,56 @@
+package powerset
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMustSmall(t *testing.T) {
+	if n := MustSmall(6, 64); n != 6 {
+		t.Fatalf("expected 6, got %d", n)
+	}
+	for _, n := range []int{7, 64, 100} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("expected a panic for %d items", n)
+				}
+			}()
+			MustSmall(n, 64)
+		}()
+	}
+}
+
+func TestCollect(t *testing.T) {
+	cfg := Config{LenItems: 4, MaxSize: 2, Forbidden: []int{0}}
+	all, err := Collect(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if correct := collectFamily(t, cfg); !reflect.DeepEqual(all, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", all, correct)
+	}
+}
+
+func TestCollectLimit(t *testing.T) {
+	if _, err := Collect(Config{LenItems: 64}); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge, got %v", err)
+	}
+
+	t.Setenv(CollectLimitEnv, "10")
+	if _, err := Collect(Config{LenItems: 4}); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("expected ErrTooLarge, got %v", err)
+	}
+	// a parsed constraint brings the bound within the limit
+	parsed, _ := ParseConstraint("0 & 1", nil)
+	all, err := Collect(Config{LenItems: 4, Constraints: []Constraint{parsed}})
+	if err != nil || len(all) != 4 {
+		t.Fatalf("expected 4 subsets, got %v, %v", all, err)
+	}
+
+	t.Setenv(CollectLimitEnv, "lots")
+	if _, err := Collect(Config{LenItems: 1}); err == nil {
+		t.Fatalf("expected an error for a malformed limit")
+	}
+}
@@ -0,0 +1,60 @@
+package powerset
+
+import "sync"
+
+// Combinations generates every subset of exactly k of lenItems items, as a sorted slice of its indices, without
+// visiting any subset of another size, so it takes C(lenItems, k) steps instead of 2^lenItems.  the subsets come out in
+// the same relative order as FixedSize, and stop works the same way.  Combinations understands WithOrder(OrderColex)
+func Combinations(lenItems int, k int, opts ...Option) (<-chan []int, func()) {
+	colex := buildOptions(opts).order == OrderColex
+	out := make(chan []int)
+	stopIn := make(chan bool)
+
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		defer close(out)
+		defer wg.Done()
+		if k < 0 || k > lenItems {
+			return
+		}
+
+		subset := make([]int, 0, k)
+		var recurse func(idx int) bool
+		recurse = func(idx int) bool {
+			if len(subset) == k {
+				select {
+				case <-stopIn:
+					return false
+				case out <- combination(subset, lenItems, colex):
+					return true
+				}
+			}
+
+			// excluding idx comes first, as long as enough items are left after it to fill the subset
+			if lenItems-idx-1 >= k-len(subset) && !recurse(idx+1) {
+				return false
+			}
+			subset = append(subset, idx)
+			cont := recurse(idx + 1)
+			subset = subset[:len(subset)-1]
+			return cont
+		}
+		recurse(0)
+	}()
+
+	return out, makeStopper(stopIn, wg)
+}
+
+// combination copies subset to be sent, reversing its indices for the colexicographic order, since that's the order of
+// the reversed indices
+func combination(subset []int, lenItems int, colex bool) []int {
+	if !colex {
+		return append([]int{}, subset...)
+	}
+	reversed := make([]int, len(subset))
+	for i, idx := range subset {
+		reversed[len(subset)-1-i] = lenItems - 1 - idx
+	}
+	return reversed
+}
@@ -0,0 +1,62 @@
+package powerset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCombinations(t *testing.T) {
+	for n := 0; n <= 6; n++ {
+		for k := -1; k <= n+1; k++ {
+			correct := [][]int{}
+			for _, subset := range collectFamily(t, Config{LenItems: n}) {
+				if len(subset) == k {
+					correct = append(correct, subset)
+				}
+			}
+
+			out, _ := Combinations(n, k)
+			got := [][]int{}
+			for subset := range out {
+				got = append(got, subset)
+			}
+			if !reflect.DeepEqual(got, correct) {
+				t.Fatalf("n=%d, k=%d: \n%v\n\n!=\n\n%v", n, k, got, correct)
+			}
+		}
+	}
+}
+
+func TestCombinationsLarge(t *testing.T) {
+	out, _ := Combinations(40, 3)
+	count := 0
+	var last []int
+	for subset := range out {
+		count++
+		last = subset
+	}
+	if count != 9880 || !reflect.DeepEqual(last, []int{0, 1, 2}) {
+		t.Fatalf("expected 9880 subsets ending with [0 1 2], got %d ending with %v", count, last)
+	}
+}
+
+func TestCombinationsStop(t *testing.T) {
+	out, stop := Combinations(40, 20)
+	<-out
+	stop()
+}
+
+func TestCombinationsColex(t *testing.T) {
+	out, _ := Combinations(5, 3, WithOrder(OrderColex))
+	got := [][]int{}
+	for subset := range out {
+		got = append(got, subset)
+	}
+	correct := [][]int{
+		{0, 1, 2}, {0, 1, 3}, {0, 2, 3}, {1, 2, 3}, {0, 1, 4},
+		{0, 2, 4}, {1, 2, 4}, {0, 3, 4}, {1, 3, 4}, {2, 3, 4},
+	}
+	if !reflect.DeepEqual(got, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", got, correct)
+	}
+}
@@ -0,0 +1,105 @@
+package powerset
+
+import "sync"
+
+// FamilyComplement generates every subset of cfg.LenItems items that is not a member of the family described by cfg, in
+// the same order as FixedSize, which is useful for testing constraints and for auditing what a configuration excludes.
+// cfg's Order must be OrderCanonical.  the powerset tree is walked with the family's rules negated: a subtree that
+// can't contain a member of the family, by the size bounds, the required and forbidden indices or a constraint built by
+// ParseConstraint, is entirely in the complement and is emitted without checking any further, and only the leaves of
+// the other subtrees are checked against the family
+func FamilyComplement(cfg Config) (<-chan []int, func(), error) {
+	if err := cfg.canonicalOnly("FamilyComplement"); err != nil {
+		return nil, nil, err
+	}
+	fam, err := cfg.compile()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := make(chan []int)
+	stopIn := make(chan bool)
+
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		defer close(out)
+		defer wg.Done()
+
+		emit := func(indices []int) bool {
+			select {
+			case <-stopIn:
+				return false
+			case out <- append([]int{}, indices...):
+				return true
+			}
+		}
+
+		n := fam.lenItems
+		included := make([]int, 0, n)
+		var partial *exprState
+		if len(fam.exprs) > 0 {
+			partial = &exprState{in: make([]bool, n), members: fam.members}
+		}
+
+		// every completes every subset below a node that has no members
+		var every func(next int) bool
+		every = func(next int) bool {
+			if next == n {
+				return emit(included)
+			}
+			count := len(included)
+			if !every(next + 1) {
+				return false
+			}
+			included = append(included, next)
+			cont := every(next + 1)
+			included = included[:count]
+			return cont
+		}
+
+		// recurse walks a node that may have members, where broken is whether a required index has been excluded or a
+		// forbidden index included on the way to it
+		var recurse func(next int, broken bool) bool
+		recurse = func(next int, broken bool) bool {
+			count := len(included)
+			dead := broken || fam.empty || count+fam.availFrom[next] < fam.minSize ||
+				count+fam.requiredFrom[next] > fam.maxSize
+			if !dead && partial != nil && next < n {
+				ok, _ := fam.satisfiable(partial, count, next)
+				dead = !ok
+			}
+			if dead {
+				return every(next)
+			}
+
+			if next == n {
+				if member, _ := fam.contains(included); member {
+					return true
+				}
+				return emit(included)
+			}
+
+			if !recurse(next+1, fam.members[next] == required) {
+				return false
+			}
+			included = append(included, next)
+			if partial != nil {
+				partial.in[next] = true
+			}
+			cont := recurse(next+1, fam.members[next] == forbidden)
+			if partial != nil {
+				partial.in[next] = false
+			}
+			included = included[:count]
+			return cont
+		}
+		recurse(0, false)
+	}()
+
+	stop := func() {
+		close(stopIn)
+		wg.Wait()
+	}
+	return out, stop, nil
+}
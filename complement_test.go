@@ -0,0 +1,83 @@
+package powerset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func collectComplement(t *testing.T, cfg Config) [][]int {
+	out, stop, err := FamilyComplement(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer stop()
+
+	subsets := [][]int{}
+	for subset := range out {
+		subsets = append(subsets, subset)
+	}
+	return subsets
+}
+
+func TestFamilyComplement(t *testing.T) {
+	items, _ := NewItems("a", "b", "c", "d", "e")
+	even := Constraint{Name: "even", Allow: func(indices []int) bool { return len(indices)%2 == 0 }}
+	cfgs := []Config{
+		{LenItems: 0},
+		{LenItems: 4},
+		{LenItems: 5, MinSize: 2, MaxSize: 3},
+		{LenItems: 5, Required: []int{1}, Forbidden: []int{3}},
+		{LenItems: 5, Constraints: []Constraint{mustParse(t, "a -> (b | c)", items), even}},
+		{LenItems: 5, MaxSize: 1, Required: []int{0, 2}},
+	}
+	for _, cfg := range cfgs {
+		fam, _ := cfg.compile()
+		correct := [][]int{}
+		for _, subset := range collectFamily(t, Config{LenItems: cfg.LenItems}) {
+			if member, _ := fam.contains(subset); !member {
+				correct = append(correct, subset)
+			}
+		}
+		if got := collectComplement(t, cfg); !reflect.DeepEqual(got, correct) {
+			t.Fatalf("%+v: \n%v\n\n!=\n\n%v", cfg, got, correct)
+		}
+	}
+}
+
+func TestFamilyComplementStop(t *testing.T) {
+	out, stop, err := FamilyComplement(Config{LenItems: 40, MinSize: 40})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-out
+	stop()
+}
+
+func TestFamilyComplementInvalid(t *testing.T) {
+	if _, _, err := FamilyComplement(Config{LenItems: 2, Forbidden: []int{5}}); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestFamilyComplementShardAndOrder(t *testing.T) {
+	cfg := Config{LenItems: 4, Shard: &ShardSpec{Count: 4, Index: 1}}
+	out, _, err := FamilyComplement(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	count := 0
+	for indices := range out {
+		if len(indices) > 0 && indices[0] == 1 {
+			t.Fatalf("%v is a member of the shard", indices)
+		}
+		count++
+	}
+	if count != 12 {
+		t.Fatalf("\n%v\n\n!=\n\n%v", count, 12)
+	}
+
+	cfg.Order = OrderGray
+	if _, _, err := FamilyComplement(cfg); err == nil {
+		t.Fatalf("expected an error for OrderGray")
+	}
+}
@@ -0,0 +1,255 @@
+package powerset
+
+import "fmt"
+
+// Constraint is a named predicate that a subset must satisfy to be a member of a family.  the subset is passed as a
+// sorted slice of the included indices.  constraints built by ParseConstraint also keep their source in Expr, which
+// lets them be serialized, and can prune the powerset tree before reaching its leaves
+type Constraint struct {
+	Name  string
+	Allow func(indices []int) bool
+	Expr  string
+
+	// the compiled Expr, if any
+	expr exprNode
+}
+
+// Config describes a family of subsets of the indices [0, LenItems).  the zero value of every field except LenItems
+// means "unconstrained", so a MaxSize of zero means there is no upper bound on the subset size
+type Config struct {
+	LenItems    int
+	MinSize     int
+	MaxSize     int
+	Required    []int
+	Forbidden   []int
+	Constraints []Constraint
+
+	// the order Start and Family emit the family in when they aren't given WithOrder.  the functions that only ask
+	// which subsets are members, like Verify and ElementFrequencies, don't depend on it, and the ones that can only
+	// walk a family in canonical order, like NewIterator and Optimize, return an error unless it's OrderCanonical
+	Order Order
+
+	// restricts the family to its members whose ranks are in one shard of the powerset, as split by the Shard function,
+	// for every function that takes a Config.  nil is the whole family
+	Shard *ShardSpec
+}
+
+// ShardSpec is shard Index of Count contiguous shards of the ranks of a powerset
+type ShardSpec struct {
+	Count int `json:"count"`
+	Index int `json:"index"`
+}
+
+// the state of a single index in a normalized family
+type membership int
+
+const (
+	free membership = iota
+	required
+	forbidden
+)
+
+// family is the normalized, validated form of a Config that the generators work from
+type family struct {
+	lenItems    int
+	minSize     int
+	maxSize     int
+	members     []membership
+	numRequired int
+	numFree     int
+	constraints []Constraint
+
+	// the constraints that can be evaluated on partial subsets
+	exprs []Constraint
+
+	// the ranks of Config.Shard, if any.  its constraint is also one of constraints, so the leaves outside of it are
+	// rejected by every walk, and the walks that can prune skip the subtrees outside of it too
+	shard *rankShard
+
+	// availFrom[i] is how many indices in [i, lenItems) could still be included, and requiredFrom[i] is how many of
+	// those must be.  together they let a walk prune a branch as soon as its size bounds become unreachable
+	availFrom    []int
+	requiredFrom []int
+
+	// true when the required and forbidden indices contradict each other, or the size bounds can't be met
+	empty bool
+}
+
+// Validate checks that the Config describes a well formed family, meaning all of its indices are in range and its size
+// bounds are sensible.  a valid Config may still describe an empty family
+func (cfg Config) Validate() error {
+	if cfg.LenItems < 0 {
+		return fmt.Errorf("powerset: LenItems must not be negative, got %d", cfg.LenItems)
+	}
+	if cfg.MinSize < 0 || cfg.MaxSize < 0 {
+		return fmt.Errorf("powerset: size bounds must not be negative, got [%d, %d]", cfg.MinSize, cfg.MaxSize)
+	}
+	if cfg.MaxSize > 0 && cfg.MinSize > cfg.MaxSize {
+		return fmt.Errorf("powerset: MinSize %d is larger than MaxSize %d", cfg.MinSize, cfg.MaxSize)
+	}
+	for _, idx := range cfg.Required {
+		if idx < 0 || idx >= cfg.LenItems {
+			return fmt.Errorf("powerset: required index %d out of range [0, %d)", idx, cfg.LenItems)
+		}
+	}
+	for _, idx := range cfg.Forbidden {
+		if idx < 0 || idx >= cfg.LenItems {
+			return fmt.Errorf("powerset: forbidden index %d out of range [0, %d)", idx, cfg.LenItems)
+		}
+	}
+	if s := cfg.Shard; s != nil && (s.Count < 1 || s.Index < 0 || s.Index >= s.Count) {
+		return fmt.Errorf("powerset: shard %d of %d doesn't exist", s.Index, s.Count)
+	}
+	for i, c := range cfg.Constraints {
+		if c.Allow == nil {
+			return fmt.Errorf("powerset: constraint %d (%q) has no Allow function", i, c.Name)
+		}
+		if c.expr != nil && c.expr.maxIndex() >= cfg.LenItems {
+			return fmt.Errorf("powerset: constraint %d (%q) refers to index %d out of range [0, %d)", i, c.Name,
+				c.expr.maxIndex(), cfg.LenItems)
+		}
+	}
+	return nil
+}
+
+// canonicalOnly returns an error naming fn when cfg asks for an order other than OrderCanonical, for the functions that
+// can only walk a family in canonical order
+func (cfg Config) canonicalOnly(fn string) error {
+	if cfg.Order != OrderCanonical {
+		return fmt.Errorf("powerset: %s only supports OrderCanonical, got Config.Order %d", fn, cfg.Order)
+	}
+	return nil
+}
+
+// compile validates the Config and converts it into its normalized form
+func (cfg Config) compile() (*family, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	fam := &family{
+		lenItems:    cfg.LenItems,
+		minSize:     cfg.MinSize,
+		maxSize:     cfg.MaxSize,
+		members:     make([]membership, cfg.LenItems),
+		constraints: cfg.Constraints,
+	}
+	if fam.maxSize == 0 || fam.maxSize > fam.lenItems {
+		fam.maxSize = fam.lenItems
+	}
+
+	for _, idx := range cfg.Required {
+		fam.members[idx] = required
+	}
+	for _, idx := range cfg.Forbidden {
+		if fam.members[idx] == required {
+			fam.empty = true
+		}
+		fam.members[idx] = forbidden
+	}
+
+	for _, m := range fam.members {
+		switch m {
+		case free:
+			fam.numFree++
+		case required:
+			fam.numRequired++
+		}
+	}
+
+	if fam.numRequired > fam.maxSize || fam.numRequired+fam.numFree < fam.minSize {
+		fam.empty = true
+	}
+	for _, c := range cfg.Constraints {
+		if c.expr != nil {
+			fam.exprs = append(fam.exprs, c)
+		}
+	}
+	if cfg.Shard != nil {
+		fam.shard = newRankShard(fam.lenItems, *cfg.Shard)
+		fam.constraints = append(append([]Constraint{}, fam.constraints...), fam.shard.constraint())
+		if fam.shard.lo.Cmp(fam.shard.hi) > 0 {
+			fam.empty = true
+		}
+	}
+	fam.countAhead()
+	return fam, nil
+}
+
+// countAhead fills in availFrom and requiredFrom from the family's members
+func (fam *family) countAhead() {
+	fam.availFrom = make([]int, fam.lenItems+1)
+	fam.requiredFrom = make([]int, fam.lenItems+1)
+	for i := fam.lenItems - 1; i >= 0; i-- {
+		fam.availFrom[i] = fam.availFrom[i+1]
+		fam.requiredFrom[i] = fam.requiredFrom[i+1]
+		switch fam.members[i] {
+		case free:
+			fam.availFrom[i]++
+		case required:
+			fam.availFrom[i]++
+			fam.requiredFrom[i]++
+		}
+	}
+}
+
+// reachableSizes returns the smallest and largest size of a member of the family below a node where numIncluded
+// indices have been included and next is the next index to decide
+func (fam *family) reachableSizes(numIncluded int, next int) (int, int) {
+	lo := numIncluded + fam.requiredFrom[next]
+	if lo < fam.minSize {
+		lo = fam.minSize
+	}
+	hi := numIncluded + fam.availFrom[next]
+	if hi > fam.maxSize {
+		hi = fam.maxSize
+	}
+	return lo, hi
+}
+
+// the smallest and largest subset size that the family can contain
+func (fam *family) sizeRange() (int, int) {
+	lo := fam.minSize
+	if lo < fam.numRequired {
+		lo = fam.numRequired
+	}
+	hi := fam.maxSize
+	if hi > fam.numRequired+fam.numFree {
+		hi = fam.numRequired + fam.numFree
+	}
+	return lo, hi
+}
+
+// contains reports whether the sorted subset is a member of the family, returning the name of the first rule it
+// breaks if it isn't
+func (fam *family) contains(indices []int) (bool, string) {
+	if fam.empty {
+		return false, "empty family"
+	}
+	if len(indices) < fam.minSize {
+		return false, "MinSize"
+	}
+	if len(indices) > fam.maxSize {
+		return false, "MaxSize"
+	}
+
+	included := make([]bool, fam.lenItems)
+	for _, idx := range indices {
+		included[idx] = true
+	}
+	for idx, m := range fam.members {
+		if m == required && !included[idx] {
+			return false, "Required"
+		}
+		if m == forbidden && included[idx] {
+			return false, "Forbidden"
+		}
+	}
+
+	for _, c := range fam.constraints {
+		if !c.Allow(indices) {
+			return false, c.Name
+		}
+	}
+	return true, ""
+}
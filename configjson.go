@@ -0,0 +1,157 @@
+package powerset
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// the JSON form of a Config
+type configJSON struct {
+	LenItems  int   `json:"lenItems"`
+	MinSize   int   `json:"minSize,omitempty"`
+	MaxSize   int   `json:"maxSize,omitempty"`
+	Required  []int `json:"required,omitempty"`
+	Forbidden []int `json:"forbidden,omitempty"`
+
+	Constraints []constraintJSON `json:"constraints,omitempty"`
+
+	Order string     `json:"order,omitempty"`
+	Shard *ShardSpec `json:"shard,omitempty"`
+}
+
+// the names of the orders in JSON
+var orderNames = map[Order]string{
+	OrderCanonical:     "canonical",
+	OrderZigZag:        "zigzag",
+	OrderGray:          "gray",
+	OrderLocality:      "locality",
+	OrderLexicographic: "lexicographic",
+	OrderBySize:        "bySize",
+	OrderColex:         "colex",
+}
+
+// the JSON form of a Constraint, which only exists for constraints built by ParseConstraint
+type constraintJSON struct {
+	Name string `json:"name,omitempty"`
+	Expr string `json:"expr"`
+}
+
+// config compiles the decoded constraint expressions, resolving item names with items, which may be nil
+func (cj configJSON) config(items *Items) (Config, error) {
+	cfg := Config{
+		LenItems:  cj.LenItems,
+		MinSize:   cj.MinSize,
+		MaxSize:   cj.MaxSize,
+		Required:  cj.Required,
+		Forbidden: cj.Forbidden,
+		Shard:     cj.Shard,
+	}
+	if cj.Order != "" {
+		found := false
+		for order, name := range orderNames {
+			if name == cj.Order {
+				cfg.Order, found = order, true
+			}
+		}
+		if !found {
+			return Config{}, fmt.Errorf("powerset: unknown order %q", cj.Order)
+		}
+	}
+	for _, c := range cj.Constraints {
+		constraint, err := ParseConstraint(c.Expr, items)
+		if err != nil {
+			return Config{}, err
+		}
+		if c.Name != "" {
+			constraint.Name = c.Name
+		}
+		cfg.Constraints = append(cfg.Constraints, constraint)
+	}
+	return cfg, nil
+}
+
+// MarshalJSON encodes the Config so an enumeration can be described declaratively, saved alongside a batch job, and
+// reproduced later with LoadConfig, in the same order and shard.  constraints are encoded by their Expr, so only
+// constraints built by ParseConstraint can be, and any other constraint is an error
+func (cfg Config) MarshalJSON() ([]byte, error) {
+	cj := configJSON{
+		LenItems:  cfg.LenItems,
+		MinSize:   cfg.MinSize,
+		MaxSize:   cfg.MaxSize,
+		Required:  cfg.Required,
+		Forbidden: cfg.Forbidden,
+		Shard:     cfg.Shard,
+	}
+	if cfg.Order != OrderCanonical {
+		name, ok := orderNames[cfg.Order]
+		if !ok {
+			return nil, fmt.Errorf("powerset: order %d can't be serialized", cfg.Order)
+		}
+		cj.Order = name
+	}
+	for _, c := range cfg.Constraints {
+		if c.Expr == "" {
+			return nil, fmt.Errorf("powerset: constraint %q can't be serialized", c.Name)
+		}
+		cj.Constraints = append(cj.Constraints, constraintJSON{Name: c.Name, Expr: c.Expr})
+		if c.Name == c.Expr {
+			cj.Constraints[len(cj.Constraints)-1].Name = ""
+		}
+	}
+	return json.Marshal(cj)
+}
+
+// UnmarshalJSON decodes a Config encoded by MarshalJSON.  unknown fields are an error, so that a typo in a hand written
+// configuration isn't silently ignored.  constraint expressions can only name items by index here, use Items.LoadConfig
+// for expressions that name items
+func (cfg *Config) UnmarshalJSON(data []byte) error {
+	var cj configJSON
+	if err := strictUnmarshal(data, &cj); err != nil {
+		return err
+	}
+	decoded, err := cj.config(nil)
+	if err != nil {
+		return err
+	}
+	*cfg = decoded
+	return nil
+}
+
+// LoadConfig reads a JSON encoded Config from r and validates it
+func LoadConfig(r io.Reader) (Config, error) {
+	var cfg Config
+	if err := json.NewDecoder(r).Decode(&cfg); err != nil {
+		return Config{}, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// LoadConfig reads a JSON encoded Config from r and validates it like the package level LoadConfig, resolving the item
+// names in its constraint expressions with the registry
+func (it *Items) LoadConfig(r io.Reader) (Config, error) {
+	var cj configJSON
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&cj); err != nil {
+		return Config{}, err
+	}
+	cfg, err := cj.config(it)
+	if err != nil {
+		return Config{}, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+func strictUnmarshal(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
@@ -0,0 +1,79 @@
+package powerset
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConfigJSONRoundTrip(t *testing.T) {
+	cfg := Config{LenItems: 6, MinSize: 1, MaxSize: 4, Required: []int{2}, Forbidden: []int{0, 5}}
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	correct := `{"lenItems":6,"minSize":1,"maxSize":4,"required":[2],"forbidden":[0,5]}`
+	if string(data) != correct {
+		t.Fatalf("\n%s\n!=\n%s", data, correct)
+	}
+
+	loaded, err := LoadConfig(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(cfg, loaded) {
+		t.Fatalf("\n%+v\n\n!=\n\n%+v", loaded, cfg)
+	}
+}
+
+// a saved enumeration comes back in the same order and shard, and generates the same subsets
+func TestConfigJSONOrderAndShard(t *testing.T) {
+	cfg := Config{LenItems: 5, MaxSize: 3, Order: OrderBySize, Shard: &ShardSpec{Count: 3, Index: 1}}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	correct := `{"lenItems":5,"maxSize":3,"order":"bySize","shard":{"count":3,"index":1}}`
+	if string(data) != correct {
+		t.Fatalf("\n%s\n!=\n%s", data, correct)
+	}
+	loaded, err := LoadConfig(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(cfg, loaded) {
+		t.Fatalf("\n%+v\n\n!=\n\n%+v", loaded, cfg)
+	}
+	if got, want := collectFamily(t, loaded), collectFamily(t, cfg); !reflect.DeepEqual(got, want) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", got, want)
+	}
+
+	for _, data := range []string{`{"lenItems":3,"order":"sideways"}`, `{"lenItems":3,"shard":{"count":2,"index":2}}`} {
+		if _, err := LoadConfig(strings.NewReader(data)); err == nil {
+			t.Fatalf("expected %s to be rejected", data)
+		}
+	}
+}
+
+func TestConfigJSONConstraints(t *testing.T) {
+	cfg := Config{LenItems: 2, Constraints: []Constraint{{Name: "func", Allow: func([]int) bool { return true }}}}
+	if _, err := json.Marshal(cfg); err == nil {
+		t.Fatalf("expected a function constraint to be unserializable")
+	}
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	invalid := []string{
+		`{"lenItems":3,"minSize":2,"maxSize":1}`,
+		`{"lenItems":3,"required":[3]}`,
+		`{"lenItems":3,"maxSise":2}`,
+		`{"lenItems":`,
+	}
+	for _, data := range invalid {
+		if _, err := LoadConfig(strings.NewReader(data)); err == nil {
+			t.Fatalf("expected %s to be rejected", data)
+		}
+	}
+}
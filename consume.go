@@ -0,0 +1,111 @@
+package powerset
+
+import (
+	"context"
+	"time"
+)
+
+// Generator starts a generator, returning its output channel and the function that stops it, like VariableSize does.
+// a Family can be wrapped in a closure that drops its error once the Config is known to be valid
+type Generator func() (<-chan []int, func())
+
+// Poison is a subset that a consumer gave up on, with the error of its last attempt
+type Poison struct {
+	Subset []int
+	Err    error
+}
+
+// ConsumeResult is what Consume got through
+type ConsumeResult struct {
+	// the subsets fn succeeded on
+	Consumed uint64
+
+	// the subsets that fn failed on too many times, in the order they were given up on
+	Poisoned []Poison
+}
+
+// the defaults of WithRetries and WithBackoff
+const (
+	defaultRetries    = 5
+	defaultBackoff    = 10 * time.Millisecond
+	defaultMaxBackoff = time.Second
+)
+
+// WithRetries makes Consume give up on a subset once fn has failed on it n times, and record it as poison.  zero or
+// less retries every subset until it succeeds or the context is done.  the default is 5
+func WithRetries(n int) Option {
+	return func(o *options) {
+		if n <= 0 {
+			n = -1
+		}
+		o.retries = n
+	}
+}
+
+// WithBackoff makes Consume wait initial after the first failure on a subset, doubling the wait after every further
+// failure up to max.  the defaults are 10ms and 1s
+func WithBackoff(initial time.Duration, max time.Duration) Option {
+	return func(o *options) {
+		o.backoff = initial
+		o.maxBackoff = max
+	}
+}
+
+// Consume starts gen and calls fn with each subset it generates, retrying fn with an exponential backoff when it fails,
+// which is the wrapper that consumers of a long enumeration need around transient errors like a flaky network.  a
+// poison subset that fn keeps failing on is recorded and skipped, so one bad subset doesn't hold up the rest.  the
+// generator is always stopped before Consume returns, which is when every subset has been consumed or poisoned, or
+// when ctx is done, in which case the context's error is returned along with what was consumed until then.  Consume
+// understands WithRetries and WithBackoff
+func Consume(ctx context.Context, gen Generator, fn func(ctx context.Context, subset []int) error,
+	opts ...Option) (ConsumeResult, error) {
+
+	o := &options{retries: defaultRetries, backoff: defaultBackoff, maxBackoff: defaultMaxBackoff}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	out, stop := gen()
+	defer stop()
+
+	result := ConsumeResult{}
+	for {
+		var subset []int
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case next, ok := <-out:
+			if !ok {
+				return result, nil
+			}
+			subset = next
+		}
+
+		wait := o.backoff
+		for failures := 1; ; failures++ {
+			err := fn(ctx, subset)
+			if err == nil {
+				result.Consumed++
+				break
+			}
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			if failures == o.retries {
+				result.Poisoned = append(result.Poisoned, Poison{Subset: subset, Err: err})
+				break
+			}
+
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return result, ctx.Err()
+			case <-timer.C:
+			}
+			if wait *= 2; wait > o.maxBackoff {
+				wait = o.maxBackoff
+			}
+		}
+	}
+}
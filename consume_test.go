@@ -0,0 +1,87 @@
+package powerset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConsume(t *testing.T) {
+	gen := func() (<-chan []int, func()) { return VariableSize(3) }
+
+	// every subset fails once before succeeding, and the full subset never does
+	attempts := map[string]int{}
+	result, err := Consume(context.Background(), gen, func(ctx context.Context, subset []int) error {
+		if len(subset) == 3 {
+			return errors.New("poison")
+		}
+		key := fmt.Sprint(subset)
+		if attempts[key]++; attempts[key] == 1 {
+			return errors.New("transient")
+		}
+		return nil
+	}, WithRetries(3), WithBackoff(time.Microsecond, time.Millisecond))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if result.Consumed != 7 || len(attempts) != 7 {
+		t.Fatalf("expected 7 subsets consumed, got %d", result.Consumed)
+	}
+	if len(result.Poisoned) != 1 || len(result.Poisoned[0].Subset) != 3 || result.Poisoned[0].Err.Error() != "poison" {
+		t.Fatalf("unexpected poison %v", result.Poisoned)
+	}
+}
+
+func TestConsumeCancel(t *testing.T) {
+	stopped := false
+	gen := func() (<-chan []int, func()) {
+		out, stop := VariableSize(20)
+		return out, func() {
+			stopped = true
+			stop()
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	seen := [][]int{}
+	result, err := Consume(ctx, gen, func(ctx context.Context, subset []int) error {
+		seen = append(seen, subset)
+		if len(seen) == 3 {
+			cancel()
+			return ctx.Err()
+		}
+		return nil
+	}, WithRetries(0))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if result.Consumed != 2 || len(result.Poisoned) != 0 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if !stopped {
+		t.Fatalf("expected the generator to be stopped")
+	}
+}
+
+func TestConsumeRetryForever(t *testing.T) {
+	gen := func() (<-chan []int, func()) { return VariableSize(1) }
+	failures := 0
+	result, err := Consume(context.Background(), gen, func(ctx context.Context, subset []int) error {
+		if failures < 20 {
+			failures++
+			return errors.New("transient")
+		}
+		return nil
+	}, WithRetries(0), WithBackoff(time.Microsecond, time.Microsecond))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	correct := ConsumeResult{Consumed: 2}
+	if !reflect.DeepEqual(result, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", result, correct)
+	}
+}
@@ -0,0 +1,110 @@
+package powerset
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Control is a handle on a running search, returned by Start.  its methods are safe to call from any goroutine
+type Control struct {
+	stopIn   chan bool
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+
+	paused atomic.Bool
+	mu     sync.Mutex
+
+	// closed when the search is resumed.  nil while the search isn't paused
+	gate chan bool
+
+	stats *searchStats
+
+	// why the search ended and the error that ended it early, guarded by mu
+	reason Reason
+	err    error
+
+	// replaces the output channel when the search was started with WithRingBuffer
+	ring *spscRing
+
+	recorder *recorder
+}
+
+func newControl(stats *searchStats) *Control {
+	return &Control{stopIn: make(chan bool), stats: stats}
+}
+
+// Pause suspends the search the next time it visits a node, without losing its place.  pausing a paused search does
+// nothing
+func (ctl *Control) Pause() {
+	ctl.mu.Lock()
+	defer ctl.mu.Unlock()
+
+	if ctl.gate == nil {
+		ctl.gate = make(chan bool)
+		ctl.paused.Store(true)
+	}
+}
+
+// Resume continues a paused search from where it left off.  resuming a search that isn't paused does nothing
+func (ctl *Control) Resume() {
+	ctl.mu.Lock()
+	defer ctl.mu.Unlock()
+
+	if ctl.gate != nil {
+		ctl.paused.Store(false)
+		close(ctl.gate)
+		ctl.gate = nil
+	}
+}
+
+// Paused reports whether the search is currently paused
+func (ctl *Control) Paused() bool {
+	return ctl.paused.Load()
+}
+
+// Stop terminates the search, even if it's paused, and waits for its goroutine to finish.  it is safe to call Stop
+// more than once
+func (ctl *Control) Stop() {
+	ctl.stopOnce.Do(func() {
+		close(ctl.stopIn)
+	})
+	ctl.wg.Wait()
+}
+
+// Err returns the error that ended the search early, like ErrSolutionDeadline or the context's error, or nil if it
+// hasn't ended, ran to completion, or was stopped with Stop or by WithMaxSolutions.  Reason tells those apart
+func (ctl *Control) Err() error {
+	ctl.mu.Lock()
+	defer ctl.mu.Unlock()
+	return ctl.err
+}
+
+// checkpoint is called by the search at every node.  it blocks for as long as the search is paused, and returns false
+// if the search has been stopped.  waited reports whether the search was paused
+func (ctl *Control) checkpoint() (ok bool, waited bool) {
+	if ctl.paused.Load() {
+		ctl.mu.Lock()
+		gate := ctl.gate
+		ctl.mu.Unlock()
+
+		if gate != nil {
+			waited = true
+			if ctl.recorder != nil {
+				defer ctl.recorder.waited(time.Now())
+			}
+			select {
+			case <-ctl.stopIn:
+				return false, waited
+			case <-gate:
+			}
+		}
+	}
+
+	select {
+	case <-ctl.stopIn:
+		return false, waited
+	default:
+		return true, waited
+	}
+}
@@ -0,0 +1,67 @@
+package powerset
+
+import (
+	"testing"
+	"time"
+)
+
+func TestControlPauseResume(t *testing.T) {
+	out, ctl, err := Start(Config{LenItems: 4})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	<-out
+	ctl.Pause()
+	if !ctl.Paused() {
+		t.Fatalf("expected the search to be paused")
+	}
+
+	// the producer may have already been blocked sending the next subset when we paused, but nothing after that
+	received := 0
+	timeout := time.After(50 * time.Millisecond)
+drain:
+	for {
+		select {
+		case <-out:
+			received++
+		case <-timeout:
+			break drain
+		}
+	}
+	if received > 1 {
+		t.Fatalf("received %d subsets while paused", received)
+	}
+
+	ctl.Resume()
+	if ctl.Paused() {
+		t.Fatalf("expected the search to be resumed")
+	}
+	for range out {
+		received++
+	}
+	if received != 15 {
+		t.Fatalf("expected the rest of the 16 subsets after resuming, got %d", received)
+	}
+}
+
+func TestControlStopWhilePaused(t *testing.T) {
+	out, ctl, _ := Start(Config{LenItems: 20})
+	<-out
+	ctl.Pause()
+
+	done := make(chan bool)
+	go func() {
+		ctl.Stop()
+		ctl.Stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("stop didn't terminate a paused search")
+	}
+
+	for range out {
+	}
+}
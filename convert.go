@@ -0,0 +1,63 @@
+package powerset
+
+import "math/big"
+
+// conversions between the sorted slices of included indices that the generators use and the bit set representations
+// of other codebases.  in all of them, bit i is set when index i is included, so index 0 is the least significant bit,
+// which is the opposite of a subset's rank
+
+// ToBigInt returns the subset as a *big.Int with bit idx set for each included idx
+func ToBigInt(subset []int) *big.Int {
+	x := new(big.Int)
+	for _, idx := range subset {
+		x.SetBit(x, idx, 1)
+	}
+	return x
+}
+
+// FromBigInt returns the sorted indices of the set bits of a non-negative x
+func FromBigInt(x *big.Int) []int {
+	subset := []int{}
+	forEachSet(x.Bits(), func(idx int) bool {
+		subset = append(subset, idx)
+		return true
+	})
+	return subset
+}
+
+// ToWords returns a subset of lenItems items as a slice of (lenItems+63)/64 words, with bit idx%64 of word idx/64 set
+// for each included idx.  this is the layout of bits.OnesCount64 style code, and of bitset.From from
+// github.com/bits-and-blooms/bitset, which takes these words directly
+func ToWords(lenItems int, subset []int) []uint64 {
+	words := make([]uint64, (lenItems+63)/64)
+	for _, idx := range subset {
+		words[idx/64] |= 1 << (idx % 64)
+	}
+	return words
+}
+
+// FromWords returns the sorted indices of the set bits of words, in the layout of ToWords
+func FromWords(words []uint64) []int {
+	subset := []int{}
+	forEachSet(words, func(idx int) bool {
+		subset = append(subset, idx)
+		return true
+	})
+	return subset
+}
+
+// NextSetter is a bit set that can be walked from one set bit to the next, like *bitset.BitSet from
+// github.com/bits-and-blooms/bitset, without this package depending on it.  NextSet returns the first set bit at or
+// after i, and false if there isn't one
+type NextSetter interface {
+	NextSet(i uint) (uint, bool)
+}
+
+// FromBitSet returns the sorted indices of the set bits of b
+func FromBitSet(b NextSetter) []int {
+	subset := []int{}
+	for i, ok := b.NextSet(0); ok; i, ok = b.NextSet(i + 1) {
+		subset = append(subset, int(i))
+	}
+	return subset
+}
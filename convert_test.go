@@ -0,0 +1,60 @@
+package powerset
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestBigIntConversion(t *testing.T) {
+	subset := []int{0, 3, 64, 130}
+	x := ToBigInt(subset)
+
+	correct := new(big.Int).Lsh(bigOne, 130)
+	correct.SetBit(correct, 64, 1).SetBit(correct, 3, 1).SetBit(correct, 0, 1)
+	if x.Cmp(correct) != 0 {
+		t.Fatalf("\n%v\n\n!=\n\n%v", x, correct)
+	}
+	if back := FromBigInt(x); !reflect.DeepEqual(back, subset) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", back, subset)
+	}
+	if empty := FromBigInt(new(big.Int)); len(empty) != 0 {
+		t.Fatalf("expected no indices, got %v", empty)
+	}
+}
+
+func TestWordsConversion(t *testing.T) {
+	subset := []int{1, 63, 64, 99}
+	words := ToWords(100, subset)
+
+	correct := []uint64{1<<1 | 1<<63, 1<<0 | 1<<35}
+	if !reflect.DeepEqual(words, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", words, correct)
+	}
+	if back := FromWords(words); !reflect.DeepEqual(back, subset) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", back, subset)
+	}
+	if len(ToWords(0, nil)) != 0 {
+		t.Fatalf("expected no words for no items")
+	}
+}
+
+// a stand in for bitset.BitSet, with the same NextSet
+type testBitSet []uint64
+
+func (b testBitSet) NextSet(i uint) (uint, bool) {
+	for ; int(i) < len(b)*64; i++ {
+		if b[i/64]&(1<<(i%64)) != 0 {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func TestFromBitSet(t *testing.T) {
+	subset := []int{0, 5, 70, 127}
+	b := testBitSet(ToWords(128, subset))
+	if back := FromBitSet(b); !reflect.DeepEqual(back, subset) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", back, subset)
+	}
+}
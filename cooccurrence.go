@@ -0,0 +1,96 @@
+package powerset
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// CoOccurrence is a Sink that accumulates how often each pair of indices appears together across a family of subsets.
+// Count(i, j) is the number of subsets containing both i and j, so the diagonal Count(i, i) is how many subsets contain
+// i at all.  the matrix is symmetric, and exporting it as CSV or JSON gives a heatmap of the structure of a large
+// solution family
+type CoOccurrence struct {
+	lenItems int
+	subsets  uint64
+	counts   []uint64
+}
+
+// NewCoOccurrence creates an empty co-occurrence matrix over lenItems items
+func NewCoOccurrence(lenItems int) *CoOccurrence {
+	return &CoOccurrence{
+		lenItems: lenItems,
+		counts:   make([]uint64, lenItems*lenItems),
+	}
+}
+
+// Add counts every pair of indices in subset
+func (co *CoOccurrence) Add(subset []int) {
+	co.subsets++
+	for _, i := range subset {
+		row := co.counts[i*co.lenItems:]
+		for _, j := range subset {
+			row[j]++
+		}
+	}
+}
+
+// Count returns the number of subsets added so far that contain both i and j
+func (co *CoOccurrence) Count(i, j int) uint64 {
+	return co.counts[i*co.lenItems+j]
+}
+
+// Subsets returns the number of subsets added so far
+func (co *CoOccurrence) Subsets() uint64 {
+	return co.subsets
+}
+
+// Matrix returns a copy of the counts as an n×n matrix, indexed by [i][j]
+func (co *CoOccurrence) Matrix() [][]uint64 {
+	matrix := make([][]uint64, co.lenItems)
+	for i := range matrix {
+		matrix[i] = append([]uint64{}, co.counts[i*co.lenItems:(i+1)*co.lenItems]...)
+	}
+	return matrix
+}
+
+// WriteCSV writes the matrix as CSV, with a header row and a leading column of labels.  labels names each index, and
+// may be nil to label indices by number
+func (co *CoOccurrence) WriteCSV(w io.Writer, labels []string) error {
+	label := func(i int) string {
+		if i < len(labels) {
+			return labels[i]
+		}
+		return strconv.Itoa(i)
+	}
+
+	cw := csv.NewWriter(w)
+	record := make([]string, co.lenItems+1)
+	for i := 0; i < co.lenItems; i++ {
+		record[i+1] = label(i)
+	}
+	if err := cw.Write(record); err != nil {
+		return err
+	}
+
+	for i := 0; i < co.lenItems; i++ {
+		record[0] = label(i)
+		for j := 0; j < co.lenItems; j++ {
+			record[j+1] = strconv.FormatUint(co.Count(i, j), 10)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// MarshalJSON encodes the matrix as an object holding the number of subsets and the counts as nested arrays
+func (co *CoOccurrence) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Subsets uint64     `json:"subsets"`
+		Counts  [][]uint64 `json:"counts"`
+	}{co.subsets, co.Matrix()})
+}
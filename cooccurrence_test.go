@@ -0,0 +1,60 @@
+package powerset
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestCoOccurrence(t *testing.T) {
+	co := NewCoOccurrence(3)
+	hasher := &FamilyHasher{}
+
+	out, _ := VariableSize(3)
+	Drain(out, co, hasher)
+
+	// every index is in 4 of the 8 subsets, and every pair is in 2 of them
+	correct := [][]uint64{
+		{4, 2, 2},
+		{2, 4, 2},
+		{2, 2, 4},
+	}
+	if !reflect.DeepEqual(correct, co.Matrix()) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", co.Matrix(), correct)
+	}
+	if co.Subsets() != 8 || hasher.Len() != 8 {
+		t.Fatalf("expected both sinks to see 8 subsets")
+	}
+}
+
+func TestCoOccurrenceCSV(t *testing.T) {
+	co := NewCoOccurrence(2)
+	co.Add([]int{0, 1})
+	co.Add([]int{1})
+
+	buf := new(bytes.Buffer)
+	if err := co.WriteCSV(buf, []string{"a"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	correct := ",a,1\na,1,1\n1,1,2\n"
+	if buf.String() != correct {
+		t.Fatalf("\n%q\n!=\n%q", buf.String(), correct)
+	}
+}
+
+func TestCoOccurrenceJSON(t *testing.T) {
+	co := NewCoOccurrence(2)
+	co.Add([]int{0, 1})
+
+	data, err := json.Marshal(co)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	correct := `{"subsets":1,"counts":[[1,1],[1,1]]}`
+	if string(data) != correct {
+		t.Fatalf("\n%s\n!=\n%s", data, correct)
+	}
+}
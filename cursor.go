@@ -0,0 +1,92 @@
+package powerset
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// the version of the format Cursor.MarshalBinary writes
+const cursorVersion = 1
+
+// Cursor is the position of a FixedSize, VariableSize or Callback traversal of LenItems items, which can be
+// checkpointed to disk with MarshalBinary and resumed after a restart with Option.  the consumer advances it past
+// every subset or leaf it has finished with, and a cursor that isn't advanced past a subset resumes from it again.  a
+// Cursor isn't safe for concurrent use
+type Cursor struct {
+	LenItems int
+
+	// the rank of the next subset or leaf to visit, which is 2^LenItems once every one has been
+	Rank *big.Int
+}
+
+// NewCursor returns a cursor at the start of a traversal of lenItems items
+func NewCursor(lenItems int) *Cursor {
+	return &Cursor{LenItems: lenItems, Rank: new(big.Int)}
+}
+
+// Visited advances the cursor past a subset of VariableSize, given by its included indices in any order
+func (c *Cursor) Visited(subset []int) {
+	rank := Rank(subset, c.LenItems)
+	c.Rank = rank.Add(rank, bigOne)
+}
+
+// VisitedFixed advances the cursor past a subset of FixedSize
+func (c *Cursor) VisitedFixed(included []bool) {
+	c.Rank = new(big.Int)
+	for idx, isIn := range included {
+		if isIn {
+			c.Rank.SetBit(c.Rank, c.LenItems-1-idx, 1)
+		}
+	}
+	c.Rank.Add(c.Rank, bigOne)
+}
+
+// VisitedPath advances the cursor past every leaf below the node a Callback's path leads to, which is the leaf itself
+// at a leaf, and the whole subtree at a node the callback prunes
+func (c *Cursor) VisitedPath(path Path) {
+	_, hi := path.RankInterval(c.LenItems)
+	c.Rank = hi.Add(hi, bigOne)
+}
+
+// Done reports whether the cursor is past every subset
+func (c *Cursor) Done() bool {
+	return c.Rank.BitLen() > c.LenItems
+}
+
+// Option returns the option that starts FixedSize, VariableSize or Callback at the cursor, in canonical order
+func (c *Cursor) Option() Option {
+	return WithStartRank(new(big.Int).Set(c.Rank))
+}
+
+// MarshalBinary encodes the cursor as a version byte, the number of items as a uvarint, and the rank as big endian
+// bytes
+func (c *Cursor) MarshalBinary() ([]byte, error) {
+	if c.LenItems < 0 || c.Rank == nil || c.Rank.Sign() < 0 {
+		return nil, errors.New("powerset: can't encode an invalid cursor")
+	}
+	data := []byte{cursorVersion}
+	data = binary.AppendUvarint(data, uint64(c.LenItems))
+	return append(data, c.Rank.Bytes()...), nil
+}
+
+// UnmarshalBinary decodes a cursor encoded by MarshalBinary, rejecting ranks that are out of range for its number of
+// items
+func (c *Cursor) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 || data[0] != cursorVersion {
+		return errors.New("powerset: not a cursor, or one of an unsupported version")
+	}
+	lenItems, n := binary.Uvarint(data[1:])
+	if n <= 0 || lenItems > 1<<31 {
+		return errors.New("powerset: the cursor's number of items is corrupt")
+	}
+
+	rank := new(big.Int).SetBytes(data[1+n:])
+	end := new(big.Int).Lsh(bigOne, uint(lenItems))
+	if rank.Cmp(end) > 0 {
+		return fmt.Errorf("powerset: rank %v is out of range for %d items", rank, lenItems)
+	}
+	c.LenItems, c.Rank = int(lenItems), rank
+	return nil
+}
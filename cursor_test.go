@@ -0,0 +1,115 @@
+package powerset
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+// a cursor checkpointed partway through VariableSize resumes at the next subset after a round trip
+func TestCursorVariableSize(t *testing.T) {
+	full := [][]int{}
+	cursor := NewCursor(5)
+	out, stop := VariableSize(5)
+	for subset := range out {
+		full = append(full, subset)
+		cursor.Visited(subset)
+		if len(full) == 11 {
+			stop()
+			break
+		}
+	}
+
+	data, err := cursor.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resumed := &Cursor{}
+	if err := resumed.UnmarshalBinary(data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resumed.LenItems != 5 || resumed.Rank.Cmp(big.NewInt(11)) != 0 {
+		t.Fatalf("resumed at %d items, rank %v", resumed.LenItems, resumed.Rank)
+	}
+
+	out, _ = VariableSize(5, resumed.Option())
+	for subset := range out {
+		full = append(full, subset)
+		resumed.Visited(subset)
+	}
+	correct := [][]int{}
+	out, _ = VariableSize(5)
+	for subset := range out {
+		correct = append(correct, subset)
+	}
+	if !reflect.DeepEqual(full, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", full, correct)
+	}
+	if !resumed.Done() {
+		t.Fatalf("expected the cursor to be done at rank %v", resumed.Rank)
+	}
+}
+
+func TestCursorFixedSize(t *testing.T) {
+	cursor := NewCursor(4)
+	out, stop := FixedSize(4)
+	for included := range out {
+		cursor.VisitedFixed(included)
+		if cursor.Rank.Int64() == 6 {
+			stop()
+			break
+		}
+	}
+
+	out, _ = FixedSize(4, cursor.Option())
+	first := <-out
+	if correct := []bool{false, true, true, false}; !reflect.DeepEqual(first, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", first, correct)
+	}
+	for range out {
+	}
+}
+
+// leaves visited before the checkpoint, or inside subtrees pruned before it, aren't visited again
+func TestCursorCallback(t *testing.T) {
+	cursor := NewCursor(4)
+	leaves := []int64{}
+	cb := func(path Path, isLeaf bool, state interface{}, out chan<- interface{}) (bool, int, interface{}) {
+		if !isLeaf {
+			// skip every subtree that includes index 1
+			if len(path) == 2 && path[0].Included {
+				cursor.VisitedPath(path)
+				return true, 1, nil
+			}
+			return false, 0, nil
+		}
+		lo, _ := path.RankInterval(4)
+		leaves = append(leaves, lo.Int64())
+		cursor.VisitedPath(path)
+		if lo.Int64() == 2 {
+			return true, -1, nil
+		}
+		return false, 0, nil
+	}
+	for range Callback(4, cb, nil) {
+	}
+	for range Callback(4, cb, nil, cursor.Option()) {
+	}
+
+	correct := []int64{0, 1, 2, 3, 8, 9, 10, 11}
+	if !reflect.DeepEqual(leaves, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", leaves, correct)
+	}
+}
+
+func TestCursorUnmarshalInvalid(t *testing.T) {
+	valid, _ := (&Cursor{LenItems: 3, Rank: big.NewInt(8)}).MarshalBinary()
+	for _, data := range [][]byte{nil, {2, 3}, {cursorVersion}, {cursorVersion, 3, 9}, {cursorVersion, 3, 1, 0}} {
+		if err := (&Cursor{}).UnmarshalBinary(data); err == nil {
+			t.Fatalf("expected an error decoding %v", data)
+		}
+	}
+	if err := (&Cursor{}).UnmarshalBinary(valid); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package powerset
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrSolutionDeadline is reported by a search that was aborted by WithSolutionDeadline
+var ErrSolutionDeadline = errors.New("powerset: no new solution before the solution deadline")
+
+// solutionWatchdog tracks how long it has been since the last solution
+type solutionWatchdog struct {
+	deadline time.Duration
+	last     time.Time
+}
+
+func newSolutionWatchdog(deadline time.Duration) *solutionWatchdog {
+	return &solutionWatchdog{deadline: deadline, last: time.Now()}
+}
+
+// reset records that a solution was just emitted
+func (wd *solutionWatchdog) reset() {
+	wd.last = time.Now()
+}
+
+// check returns false once the deadline has passed without a solution.  a search that was just paused starts its
+// deadline over, since the time it spent paused wasn't spent searching
+func (wd *solutionWatchdog) check(waited bool) bool {
+	now := time.Now()
+	if waited {
+		wd.last = now
+		return true
+	}
+	return now.Sub(wd.last) <= wd.deadline
+}
@@ -0,0 +1,56 @@
+package powerset
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSolutionDeadline(t *testing.T) {
+	// only the empty set is a solution, and the rest of the 2^30 leaves are rejected one at a time
+	onlyEmpty := Constraint{Name: "only empty", Allow: func(indices []int) bool { return len(indices) == 0 }}
+	out, ctl, _ := Start(Config{LenItems: 30, Constraints: []Constraint{onlyEmpty}},
+		WithSolutionDeadline(20*time.Millisecond))
+
+	count := 0
+	done := make(chan bool)
+	go func() {
+		for range out {
+			count++
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		ctl.Stop()
+		t.Fatalf("the deadline never aborted the search")
+	}
+
+	if count != 1 {
+		t.Fatalf("expected the single solution, got %d", count)
+	}
+	if ctl.Err() != ErrSolutionDeadline {
+		t.Fatalf("expected ErrSolutionDeadline, got %v", ctl.Err())
+	}
+}
+
+func TestSolutionDeadlineMet(t *testing.T) {
+	out, ctl, _ := Start(Config{LenItems: 8}, WithSolutionDeadline(time.Second))
+	for range out {
+	}
+	if ctl.Err() != nil {
+		t.Fatalf("unexpected error: %v", ctl.Err())
+	}
+}
+
+func TestSolutionDeadlinePaused(t *testing.T) {
+	wd := newSolutionWatchdog(10 * time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	if !wd.check(true) {
+		t.Fatalf("time spent paused counted against the deadline")
+	}
+	if !wd.check(false) {
+		t.Fatalf("the deadline should have started over after the pause")
+	}
+}
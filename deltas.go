@@ -0,0 +1,66 @@
+package powerset
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Delta is the single change from one subset to the next in a minimal change ordering
+type Delta struct {
+	Index int
+	Added bool
+}
+
+// Deltas generates the powerset of lenItems items as a stream of changes instead of full subsets.  the stream starts
+// from the empty subset, and each Delta adds or removes a single index to move to the next subset, so consumers can
+// maintain their own incremental state with O(1) updates per subset.  the subsets visited are those of the given
+// order, which must be a minimal change ordering: OrderGray, or OrderLocality, which is the same order on the full
+// powerset.  there are 2^lenItems-1 deltas in total
+func Deltas(lenItems int, order Order) (<-chan Delta, func(), error) {
+	if order != OrderGray && order != OrderLocality {
+		return nil, nil, fmt.Errorf("powerset: order %d isn't a minimal change ordering", order)
+	}
+
+	out := make(chan Delta)
+	stopIn := make(chan bool)
+
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		defer close(out)
+		defer wg.Done()
+
+		included := make([]bool, lenItems)
+		grayFlips(lenItems, func(idx int) bool {
+			included[idx] = !included[idx]
+			select {
+			case <-stopIn:
+				return false
+			case out <- Delta{Index: idx, Added: included[idx]}:
+				return true
+			}
+		})
+	}()
+
+	stop := makeStopper(stopIn, wg)
+	return out, stop, nil
+}
+
+// grayFlips calls flip with each index that the reflected Gray code flips to move from one subset to the next, starting
+// from the empty subset, until flip returns false.  the code flips the lowest set bit of a counter of the subsets
+// visited so far.  the counter is a slice of bits, least significant first, so lenItems isn't limited to the size of a
+// machine word, and incrementing it is O(1) amortized.  bit j is index lenItems-1-j, keeping index 0 most significant
+func grayFlips(lenItems int, flip func(idx int) bool) {
+	counter := make([]bool, lenItems)
+	for {
+		bit := 0
+		for bit < lenItems && counter[bit] {
+			counter[bit] = false
+			bit++
+		}
+		if bit == lenItems || !flip(lenItems-1-bit) {
+			return
+		}
+		counter[bit] = true
+	}
+}
@@ -0,0 +1,62 @@
+package powerset
+
+import (
+	"reflect"
+	"testing"
+)
+
+// applies each delta to a running subset, returning the subsets visited
+func applyDeltas(lenItems int, in <-chan Delta) [][]int {
+	included := make([]bool, lenItems)
+	allValues := [][]int{{}}
+	for delta := range in {
+		included[delta.Index] = delta.Added
+		subset := []int{}
+		for idx, in := range included {
+			if in {
+				subset = append(subset, idx)
+			}
+		}
+		allValues = append(allValues, subset)
+	}
+	return allValues
+}
+
+func TestDeltas(t *testing.T) {
+	for _, lenItems := range []int{0, 1, 3, 9} {
+		deltas, _, err := Deltas(lenItems, OrderGray)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		allValues := applyDeltas(lenItems, deltas)
+		correct := collectFamily(t, Config{LenItems: lenItems}, WithOrder(OrderGray))
+		if !reflect.DeepEqual(allValues, correct) {
+			t.Fatalf("\n%v\n\n!=\n\n%v", allValues, correct)
+		}
+	}
+}
+
+func TestDeltasAdded(t *testing.T) {
+	included := make([]bool, 4)
+	deltas, _, _ := Deltas(4, OrderLocality)
+	for delta := range deltas {
+		if included[delta.Index] == delta.Added {
+			t.Fatalf("%+v doesn't change index %d", delta, delta.Index)
+		}
+		included[delta.Index] = delta.Added
+	}
+}
+
+func TestDeltasStop(t *testing.T) {
+	deltas, stop, _ := Deltas(100, OrderGray)
+	for i := 0; i < 10; i++ {
+		<-deltas
+	}
+	stop()
+}
+
+func TestDeltasOrder(t *testing.T) {
+	if _, _, err := Deltas(3, OrderCanonical); err == nil {
+		t.Fatalf("expected the canonical order to be an error")
+	}
+}
@@ -0,0 +1,164 @@
+package powerset
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Descriptor describes one item of a data driven configuration, as a row of a descriptor file
+type Descriptor struct {
+	Name   string  `json:"name"`
+	Weight float64 `json:"weight,omitempty"`
+
+	// whether every subset must include the item, or must leave it out
+	Mandatory bool `json:"mandatory,omitempty"`
+	Excluded  bool `json:"excluded,omitempty"`
+
+	// the names of the items that can't be included together with this one
+	ExcludedWith []string `json:"excludedWith,omitempty"`
+}
+
+// Catalog is a configuration built from descriptors: the items, in the order they were described, their weights, for
+// ByWeight, KSmallest or a score, and a Config with the mandatory items required, the excluded items forbidden, and a
+// parsed constraint for every pair of items that exclude each other
+type Catalog struct {
+	Items   *Items
+	Weights []float64
+	Config  Config
+}
+
+// NewCatalog builds a Catalog from descriptors.  names must be unique, and every name an item is excluded with must be
+// described too
+func NewCatalog(descs []Descriptor) (*Catalog, error) {
+	cat := &Catalog{Items: &Items{index: map[string]int{}}}
+	for _, d := range descs {
+		idx, err := cat.Items.Add(d.Name)
+		if err != nil {
+			return nil, err
+		}
+		cat.Weights = append(cat.Weights, d.Weight)
+		if d.Mandatory && d.Excluded {
+			return nil, fmt.Errorf("powerset: item %q is both mandatory and excluded", d.Name)
+		}
+		if d.Mandatory {
+			cat.Config.Required = append(cat.Config.Required, idx)
+		}
+		if d.Excluded {
+			cat.Config.Forbidden = append(cat.Config.Forbidden, idx)
+		}
+	}
+	cat.Config.LenItems = cat.Items.Len()
+
+	// a pair can be declared from either side, or both, but only needs one constraint
+	seen := map[[2]int]bool{}
+	for a, d := range descs {
+		for _, name := range d.ExcludedWith {
+			b, err := cat.Items.Index(name)
+			if err != nil {
+				return nil, err
+			}
+			pair := [2]int{a, b}
+			if b < a {
+				pair = [2]int{b, a}
+			}
+			if a == b || seen[pair] {
+				continue
+			}
+			seen[pair] = true
+
+			// the expression refers to indices, since names can't always be quoted
+			c, err := ParseConstraint(fmt.Sprintf("!(%d & %d)", pair[0], pair[1]), nil)
+			if err != nil {
+				return nil, err
+			}
+			c.Name = fmt.Sprintf("%s excluded with %s", cat.Items.Name(pair[0]), cat.Items.Name(pair[1]))
+			cat.Config.Constraints = append(cat.Config.Constraints, c)
+		}
+	}
+	return cat, cat.Config.Validate()
+}
+
+// LoadDescriptorsJSON builds a Catalog from a JSON array of Descriptors, like
+// [{"name": "a", "weight": 2, "mandatory": true}, {"name": "b", "excludedWith": ["a"]}].  unknown fields are an error
+func LoadDescriptorsJSON(r io.Reader) (*Catalog, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var descs []Descriptor
+	if err := strictUnmarshal(data, &descs); err != nil {
+		return nil, fmt.Errorf("powerset: bad descriptors: %w", err)
+	}
+	return NewCatalog(descs)
+}
+
+// the columns of a descriptor CSV file
+var descriptorColumns = map[string]bool{
+	"name": true, "weight": true, "mandatory": true, "excluded": true, "excluded_with": true,
+}
+
+// LoadDescriptorsCSV builds a Catalog from a CSV file with a header row naming its columns, in any order: name, which
+// is required, and weight, mandatory, excluded and excluded_with.  mandatory and excluded are booleans, excluded_with
+// is a list of names separated by semicolons, and an empty cell is the zero value
+func LoadDescriptorsCSV(r io.Reader) (*Catalog, error) {
+	cr := csv.NewReader(r)
+	cr.TrimLeadingSpace = true
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("powerset: bad descriptors: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("powerset: descriptors have no header row")
+	}
+
+	column := map[string]int{}
+	for i, name := range rows[0] {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !descriptorColumns[name] {
+			return nil, fmt.Errorf("powerset: unknown descriptor column %q", name)
+		}
+		column[name] = i
+	}
+	if _, ok := column["name"]; !ok {
+		return nil, fmt.Errorf("powerset: descriptors have no name column")
+	}
+
+	descs := []Descriptor{}
+	for line, row := range rows[1:] {
+		cell := func(name string) string {
+			if i, ok := column[name]; ok {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		d := Descriptor{Name: cell("name")}
+		bad := func(name string, err error) error {
+			return fmt.Errorf("powerset: bad %s on descriptor line %d: %w", name, line+2, err)
+		}
+		if s := cell("weight"); s != "" {
+			if d.Weight, err = strconv.ParseFloat(s, 64); err != nil {
+				return nil, bad("weight", err)
+			}
+		}
+		if s := cell("mandatory"); s != "" {
+			if d.Mandatory, err = strconv.ParseBool(s); err != nil {
+				return nil, bad("mandatory", err)
+			}
+		}
+		if s := cell("excluded"); s != "" {
+			if d.Excluded, err = strconv.ParseBool(s); err != nil {
+				return nil, bad("excluded", err)
+			}
+		}
+		for _, name := range strings.Split(cell("excluded_with"), ";") {
+			if name = strings.TrimSpace(name); name != "" {
+				d.ExcludedWith = append(d.ExcludedWith, name)
+			}
+		}
+		descs = append(descs, d)
+	}
+	return NewCatalog(descs)
+}
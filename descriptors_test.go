@@ -0,0 +1,72 @@
+package powerset
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadDescriptorsCSV(t *testing.T) {
+	data := `name, weight, mandatory, excluded_with, excluded
+base, 1.5, true, ,
+gzip, 2, , brotli,
+brotli, 3, , gzip; zstd,
+zstd, 0.5, , ,
+legacy, , , , true
+`
+	cat, err := LoadDescriptorsCSV(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(cat.Weights, []float64{1.5, 2, 3, 0.5, 0}) {
+		t.Fatalf("unexpected weights %v", cat.Weights)
+	}
+	if !reflect.DeepEqual(cat.Config.Required, []int{0}) || !reflect.DeepEqual(cat.Config.Forbidden, []int{4}) {
+		t.Fatalf("unexpected config %+v", cat.Config)
+	}
+	names := []string{}
+	for _, c := range cat.Config.Constraints {
+		names = append(names, c.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"gzip excluded with brotli", "brotli excluded with zstd"}) {
+		t.Fatalf("unexpected constraints %v", names)
+	}
+
+	members := [][]string{}
+	for _, subset := range collectFamily(t, cat.Config) {
+		members = append(members, cat.Items.Labels(subset))
+	}
+	correct := [][]string{{"base"}, {"base", "zstd"}, {"base", "brotli"}, {"base", "gzip"}, {"base", "gzip", "zstd"}}
+	if !reflect.DeepEqual(members, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", members, correct)
+	}
+}
+
+func TestLoadDescriptorsJSON(t *testing.T) {
+	data := `[{"name": "a", "weight": 2, "mandatory": true}, {"name": "b", "excludedWith": ["a"]}]`
+	cat, err := LoadDescriptorsJSON(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := collectFamily(t, cat.Config); !reflect.DeepEqual(got, [][]int{{0}}) {
+		t.Fatalf("unexpected family %v", got)
+	}
+}
+
+func TestLoadDescriptorsInvalid(t *testing.T) {
+	for _, data := range []string{
+		"weight\n1\n",
+		"name, color\na, red\n",
+		"name, weight\na, heavy\n",
+		"name, excluded_with\na, b\n",
+		"name\na\na\n",
+		"name, mandatory, excluded\na, true, true\n",
+	} {
+		if _, err := LoadDescriptorsCSV(strings.NewReader(data)); err == nil {
+			t.Fatalf("expected an error for %q", data)
+		}
+	}
+	if _, err := LoadDescriptorsJSON(strings.NewReader(`[{"name": "a", "wieght": 1}]`)); err == nil {
+		t.Fatalf("expected an error for an unknown field")
+	}
+}
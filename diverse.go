@@ -0,0 +1,94 @@
+package powerset
+
+import (
+	"math"
+	"sort"
+)
+
+// HammingDistance returns the number of indices in exactly one of a and b.  a and b must be sorted
+func HammingDistance(a, b []int) int {
+	common := 0
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			common++
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	return len(a) + len(b) - 2*common
+}
+
+// Diverse selects up to k of the subsets received on in, until in is closed, trying to maximize the smallest Hamming
+// distance between any two of them, with ties broken by the total distance.  it's for when you want a spread of
+// qualitatively different solutions rather than the first k found.  memory is bounded by k: each new subset greedily
+// replaces whichever selected subset most improves the spread, if any does, so the result is a good selection rather
+// than a guaranteed optimal one.  duplicates are never selected, so fewer than k subsets are returned when there are
+// fewer than k distinct ones.  the selected subsets are returned sorted
+func Diverse(in <-chan []int, k int) [][]int {
+	selected := [][]int{}
+
+	for subset := range in {
+		candidate := append([]int{}, subset...)
+		sort.Ints(candidate)
+
+		distances := make([]int, len(selected))
+		duplicate := false
+		for i, s := range selected {
+			distances[i] = HammingDistance(candidate, s)
+			if distances[i] == 0 {
+				duplicate = true
+			}
+		}
+		if duplicate || k <= 0 {
+			continue
+		}
+
+		if len(selected) < k {
+			selected = append(selected, candidate)
+			continue
+		}
+
+		bestMin, bestSum := spread(selected, -1, nil)
+		replace := -1
+		for i := range selected {
+			min, sum := spread(selected, i, distances)
+			if min > bestMin || (min == bestMin && sum > bestSum) {
+				bestMin, bestSum, replace = min, sum, i
+			}
+		}
+		if replace >= 0 {
+			selected[replace] = candidate
+		}
+	}
+	return selected
+}
+
+// spread returns the smallest and total pairwise distance of the selected subsets.  if replace isn't -1, the subset
+// at that position is swapped for a candidate whose distances to each selected subset are given
+func spread(selected [][]int, replace int, distances []int) (int, int) {
+	min, sum := math.MaxInt, 0
+	add := func(d int) {
+		if d < min {
+			min = d
+		}
+		sum += d
+	}
+
+	for i := range selected {
+		for j := i + 1; j < len(selected); j++ {
+			if i == replace || j == replace {
+				continue
+			}
+			add(HammingDistance(selected[i], selected[j]))
+		}
+		if replace >= 0 && i != replace {
+			add(distances[i])
+		}
+	}
+	return min, sum
+}
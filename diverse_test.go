@@ -0,0 +1,55 @@
+package powerset
+
+import "testing"
+
+func TestHammingDistance(t *testing.T) {
+	if d := HammingDistance([]int{0, 1, 2}, []int{1, 3}); d != 3 {
+		t.Fatalf("distance %d != 3", d)
+	}
+	if d := HammingDistance([]int{}, []int{}); d != 0 {
+		t.Fatalf("distance %d != 0", d)
+	}
+}
+
+func minDistance(selected [][]int) int {
+	min, _ := spread(selected, -1, nil)
+	return min
+}
+
+func TestDiverse(t *testing.T) {
+	// the two most different subsets of 4 items are complements of each other
+	out, _ := VariableSize(4)
+	selected := Diverse(out, 2)
+	if len(selected) != 2 {
+		t.Fatalf("expected 2 subsets, got %v", selected)
+	}
+	if d := minDistance(selected); d != 4 {
+		t.Fatalf("expected complements, got %v at distance %d", selected, d)
+	}
+}
+
+func TestDiverseSpread(t *testing.T) {
+	// in the order Family produces them, the first 4 subsets of 6 items are all within distance 2 of each other
+	out, _, _ := Family(Config{LenItems: 6})
+	selected := Diverse(out, 4)
+	if len(selected) != 4 {
+		t.Fatalf("expected 4 subsets, got %v", selected)
+	}
+	if d := minDistance(selected); d < 3 {
+		t.Fatalf("selection %v is only spread by %d", selected, d)
+	}
+}
+
+func TestDiverseDuplicates(t *testing.T) {
+	in := make(chan []int, 4)
+	in <- []int{1, 0}
+	in <- []int{0, 1}
+	in <- []int{0, 1}
+	in <- []int{2}
+	close(in)
+
+	selected := Diverse(in, 3)
+	if len(selected) != 2 {
+		t.Fatalf("expected duplicates to be skipped, got %v", selected)
+	}
+}
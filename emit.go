@@ -0,0 +1,160 @@
+package powerset
+
+import (
+	"sort"
+	"time"
+	"unsafe"
+)
+
+// emitter applies the options that filter and transform subsets on their way to the output channel, so every
+// generator that supports them behaves the same way
+type emitter struct {
+	o       *options
+	bloom   *bloomFilter
+	limiter *tokenBucket
+	ring    *spscRing
+	pool    *bufferPool
+	memory  *memoryMeter
+
+	// records the time spent waiting to send, if not nil
+	recorder *recorder
+
+	// delivers the subsets that are sent
+	transport Transport
+
+	// how many subsets of each size have been accepted, when there's a quota per size
+	perSize map[int]int
+
+	// the hashes of the subsets delivered by this run and earlier ones, with WithJournal
+	journal *journal
+}
+
+func newEmitter(o *options) *emitter {
+	e := &emitter{o: o, memory: &memoryMeter{budget: o.memoryBudget}}
+	if o.quotaPerSize > 0 {
+		e.perSize = map[int]int{}
+	}
+	if o.bloomExpected > 0 {
+		e.bloom = fitBloomFilter(o.bloomExpected, o.bloomFPRate, e.memory.share(bloomBudgetShare))
+		e.memory.add(int64(len(e.bloom.bits)) * 8)
+	}
+	if o.rateLimit > 0 {
+		e.limiter = newTokenBucket(o.rateLimit)
+	}
+	if o.ringSize > 0 && o.transport == nil {
+		e.ring = newSPSCRing(fitRingSize(o.ringSize, e.memory.share(ringBudgetShare)))
+		e.ring.memory = e.memory
+		e.memory.add(int64(len(e.ring.buf)) * int64(unsafe.Sizeof([]int(nil))))
+	}
+	switch {
+	case e.ring != nil:
+		e.transport = e.ring
+	case o.transport != nil:
+		e.transport = o.transport
+	}
+	if o.ownership.borrow {
+		e.pool = newBufferPool(1, o.ownership.free)
+		e.pool.memory = e.memory
+		if e.ring != nil {
+			e.pool.lag += len(e.ring.buf)
+		}
+		if batch, ok := o.transport.(*batchTransport); ok {
+			e.pool.lag += batch.size
+		}
+	}
+	return e
+}
+
+// accept returns the subset that should be emitted in place of indices, or false if it should be skipped.  the
+// returned slice is never the one that was passed in, so it's safe to send
+func (e *emitter) accept(indices []int) ([]int, bool) {
+	if e.o.canonicalize != nil {
+		// the canonicalizer may return memory the user owns, like a cached canonical form, so its result is copied
+		// like any other subset before it's sorted
+		indices = e.o.canonicalize(append([]int{}, indices...))
+	}
+	var subset []int
+	if e.pool != nil {
+		subset = e.pool.fill(indices)
+	} else {
+		subset = append([]int{}, indices...)
+	}
+	if e.o.canonicalize != nil {
+		sort.Ints(subset)
+	}
+
+	if (e.perSize != nil && e.perSize[len(subset)] >= e.o.quotaPerSize) ||
+		(e.bloom != nil && e.bloom.testAndAdd(HashSubset(subset))) ||
+		(e.journal != nil && e.journal.recorded(HashSubset(subset))) {
+		if e.pool != nil {
+			e.pool.reject(subset)
+		}
+		return nil, false
+	}
+	if e.perSize != nil {
+		e.perSize[len(subset)]++
+	}
+	return subset, true
+}
+
+// exhausted reports whether every subset of a size from lo to hi would be rejected by accept, so a subtree that can
+// only contain those sizes doesn't need to be explored
+func (e *emitter) exhausted(lo int, hi int) bool {
+	if e.perSize == nil || e.o.canonicalize != nil {
+		return false
+	}
+	for size := lo; size <= hi; size++ {
+		if e.perSize[size] < e.o.quotaPerSize {
+			return false
+		}
+	}
+	return true
+}
+
+// send emits a subset returned by accept through the transport, returning false if stopIn was closed before it could
+// be sent, or the transport wouldn't take it
+func (e *emitter) send(subset []int, stopIn <-chan bool) bool {
+	// the subset may be recycled once it's sent, so it's hashed first
+	var hash uint64
+	if e.journal != nil {
+		hash = HashSubset(subset)
+	}
+	if e.limiter != nil {
+		start := time.Now()
+		ok := e.limiter.take(stopIn)
+		e.waited(start)
+		if !ok {
+			return false
+		}
+	}
+
+	sent := e.transport.Send(subset, stopIn)
+	if sent && e.pool != nil {
+		e.pool.wasSent(subset)
+		if e.memory.over() {
+			e.relieve()
+		}
+	}
+	if sent && e.journal != nil && !e.journal.record(hash) {
+		return false
+	}
+	return sent
+}
+
+func (e *emitter) waited(start time.Time) {
+	if e.recorder != nil {
+		e.recorder.waited(start)
+	}
+}
+
+// relieve gives back the memory the emitter can do without when the search is over its memory budget
+func (e *emitter) relieve() {
+	if e.pool != nil {
+		e.pool.flush()
+	}
+}
+
+// done closes the transport once nothing more will be sent
+func (e *emitter) done() {
+	e.transport.Close()
+}
@@ -0,0 +1,97 @@
+package powerset
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// the smallest rotation of a subset of items arranged in a ring
+func smallestRotation(size int) func([]int) []int {
+	return func(indices []int) []int {
+		best := indices
+		for shift := 1; shift < size; shift++ {
+			rotated := make([]int, len(indices))
+			for i, idx := range indices {
+				rotated[i] = (idx + shift) % size
+			}
+			sort.Ints(rotated)
+			if lessSubset(rotated, best) {
+				best = rotated
+			}
+		}
+		return best
+	}
+}
+
+func lessSubset(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+func TestCanonicalizerNecklaces(t *testing.T) {
+	out, _, _ := Family(Config{LenItems: 4}, WithCanonicalizer(smallestRotation(4)), WithBloomDedup(16, 1e-6))
+
+	allValues := [][]int{}
+	for indices := range out {
+		allValues = append(allValues, indices)
+	}
+
+	// the 6 binary necklaces of length 4
+	correct := [][]int{
+		{},
+		{0},
+		{0, 1},
+		{0, 2},
+		{0, 1, 2},
+		{0, 1, 2, 3},
+	}
+	sort.Slice(allValues, func(i, j int) bool {
+		if len(allValues[i]) != len(allValues[j]) {
+			return len(allValues[i]) < len(allValues[j])
+		}
+		return lessSubset(allValues[i], allValues[j])
+	})
+	if !reflect.DeepEqual(correct, allValues) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", allValues, correct)
+	}
+}
+
+func TestCanonicalizerSorts(t *testing.T) {
+	reverse := func(indices []int) []int {
+		for i, j := 0, len(indices)-1; i < j; i, j = i+1, j-1 {
+			indices[i], indices[j] = indices[j], indices[i]
+		}
+		return indices
+	}
+	e := newEmitter(buildOptions([]Option{WithCanonicalizer(reverse)}))
+
+	original := []int{0, 1, 2}
+	subset, _ := e.accept(original)
+	if !reflect.DeepEqual(subset, []int{0, 1, 2}) || !reflect.DeepEqual(original, []int{0, 1, 2}) {
+		t.Fatalf("canonicalizer output %v wasn't sorted, or modified the original %v", subset, original)
+	}
+}
+
+// a canonical form that the canonicalizer hands out from its own cache is never sorted or recycled in place
+func TestCanonicalizerOwnsResult(t *testing.T) {
+	cached := []int{2, 0}
+	canonical := func([]int) []int { return cached }
+	for _, opts := range [][]Option{nil, {WithOwnership(Borrow)}} {
+		e := newEmitter(buildOptions(append(opts, WithCanonicalizer(canonical))))
+		subset, _ := e.accept([]int{0})
+		if !reflect.DeepEqual(subset, []int{0, 2}) || !reflect.DeepEqual(cached, []int{2, 0}) {
+			t.Fatalf("emitted %v, and the cached form became %v", subset, cached)
+		}
+		if e.pool != nil {
+			e.pool.reject(subset)
+			if again, _ := e.accept([]int{1}); &again[0] == &cached[0] {
+				t.Fatalf("the cached form was recycled as a buffer")
+			}
+		}
+	}
+}
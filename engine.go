@@ -0,0 +1,82 @@
+package powerset
+
+// Engine is the strategy a search uses to walk its family, as reported in its Stats
+type Engine string
+
+const (
+	// EngineBranchAndBound walks the powerset tree, pruning the subtrees that can't satisfy the family's rules.  it's
+	// the default, and the only engine that handles every configuration
+	EngineBranchAndBound Engine = "branch-and-bound"
+
+	// EngineBitmask counts through the ranks of an unconstrained family, in the canonical order, with no tree to walk
+	EngineBitmask Engine = "bitmask"
+
+	// EngineGray counts through an unconstrained family in the reflected Gray code order, for consumers of OrderGray
+	// and OrderLocality that follow the deltas between subsets
+	EngineGray Engine = "gray"
+
+	// EngineMeetInTheMiddle walks the powerset tree pruned by the sums reachable from the upper half of the indices,
+	// and is used whenever a search has WithTargetSum
+	EngineMeetInTheMiddle Engine = "meet-in-the-middle"
+)
+
+// the most items a bitmask engine can count through
+const maxBitmaskItems = 63
+
+// WithAutoEngine lets Start pick the engine for each configuration, instead of always walking the powerset tree.  a
+// family with no size bounds, required or forbidden indices or constraints is counted through with a bitmask, in the
+// canonical or Gray code order, which visits no internal nodes, and everything else is walked with branch-and-bound,
+// or meet-in-the-middle with WithTargetSum.  the engine used is reported in Stats.Engine.  the subsets and their order
+// are the same whichever engine runs, but Stats.Nodes only counts subsets under the bitmask engines
+func WithAutoEngine() Option {
+	return func(o *options) {
+		o.autoEngine = true
+	}
+}
+
+// engine picks the engine for the family under the given options
+func (fam *family) engine(o *options) Engine {
+	if o.targetSum != nil {
+		return EngineMeetInTheMiddle
+	}
+	unconstrained := !fam.empty && len(fam.constraints) == 0 && len(fam.exprs) == 0 && fam.numFree == fam.lenItems &&
+		fam.minSize == 0 && fam.maxSize == fam.lenItems
+	if !o.autoEngine || !unconstrained || fam.lenItems > maxBitmaskItems || o.quotaPerSize > 0 || o.adaptive != nil {
+		return EngineBranchAndBound
+	}
+
+	switch o.order {
+	case OrderCanonical:
+		return EngineBitmask
+	case OrderGray, OrderLocality:
+		return EngineGray
+	}
+	return EngineBranchAndBound
+}
+
+// countUp visits every subset of an unconstrained family by counting through the ranks, where index 0 is the most
+// significant bit, so the subsets come out in the canonical order, or in the reflected Gray code order with gray.  the
+// slice passed to visit is reused between calls.  returns false if visit stopped it early
+func (fam *family) countUp(gray bool, visit func([]int) bool) bool {
+	n := uint(fam.lenItems)
+	indices := make([]int, 0, n)
+	last := uint64(1)<<n - 1
+	for r := uint64(0); ; r++ {
+		mask := r
+		if gray {
+			mask ^= r >> 1
+		}
+		indices = indices[:0]
+		for idx := uint(0); idx < n; idx++ {
+			if mask&(1<<(n-1-idx)) != 0 {
+				indices = append(indices, int(idx))
+			}
+		}
+		if !visit(indices) {
+			return false
+		}
+		if r == last {
+			return true
+		}
+	}
+}
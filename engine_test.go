@@ -0,0 +1,72 @@
+package powerset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func collectStart(t *testing.T, cfg Config, opts ...Option) ([][]int, Stats) {
+	out, ctl, err := Start(cfg, opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	subsets := [][]int{}
+	for subset := range out {
+		subsets = append(subsets, subset)
+	}
+	return subsets, ctl.Stats()
+}
+
+func TestWithAutoEngine(t *testing.T) {
+	items, _ := NewItems("a", "b", "c", "d", "e")
+	cases := []struct {
+		cfg    Config
+		opts   []Option
+		engine Engine
+	}{
+		{Config{LenItems: 5}, nil, EngineBitmask},
+		{Config{LenItems: 0}, nil, EngineBitmask},
+		{Config{LenItems: 5}, []Option{WithOrder(OrderGray)}, EngineGray},
+		{Config{LenItems: 5}, []Option{WithOrder(OrderLocality)}, EngineGray},
+		{Config{LenItems: 5}, []Option{WithOrder(OrderZigZag)}, EngineBranchAndBound},
+		{Config{LenItems: 5, MaxSize: 3}, nil, EngineBranchAndBound},
+		{Config{LenItems: 5, Required: []int{1}}, nil, EngineBranchAndBound},
+		{Config{LenItems: 5, Constraints: []Constraint{mustParse(t, "a -> !e", items)}}, nil, EngineBranchAndBound},
+		{Config{LenItems: 5}, []Option{WithTargetSum([]int64{1, 2, 3, 4, 5}, 6)}, EngineMeetInTheMiddle},
+	}
+	for _, c := range cases {
+		correct, _ := collectStart(t, c.cfg, c.opts...)
+		got, stats := collectStart(t, c.cfg, append(c.opts, WithAutoEngine())...)
+		if stats.Engine != c.engine {
+			t.Fatalf("%+v: expected engine %q, got %q", c.cfg, c.engine, stats.Engine)
+		}
+		if !reflect.DeepEqual(got, correct) {
+			t.Fatalf("%+v, %s: \n%v\n\n!=\n\n%v", c.cfg, stats.Engine, got, correct)
+		}
+	}
+}
+
+func TestWithAutoEngineStop(t *testing.T) {
+	out, ctl, err := Start(Config{LenItems: 40}, WithAutoEngine(), WithMaxSolutions(3))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	subsets := [][]int{}
+	for subset := range out {
+		subsets = append(subsets, subset)
+	}
+	correct := [][]int{{}, {39}, {38}}
+	if !reflect.DeepEqual(subsets, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", subsets, correct)
+	}
+	if ctl.Stats().Engine != EngineBitmask {
+		t.Fatalf("expected the bitmask engine, got %q", ctl.Stats().Engine)
+	}
+}
+
+func TestDefaultEngine(t *testing.T) {
+	_, stats := collectStart(t, Config{LenItems: 3})
+	if stats.Engine != EngineBranchAndBound {
+		t.Fatalf("expected %q, got %q", EngineBranchAndBound, stats.Engine)
+	}
+}
@@ -1,8 +1,9 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"math"
+	"math/big"
 	"os"
 	"strconv"
 
@@ -137,11 +138,22 @@ func copyState(state boardState) boardState {
 func main() {
 	boardSize, _ := strconv.Atoi(os.Args[1])
 
+	// an optional second argument stops after that many solutions
+	limit := 0
+	if len(os.Args) > 2 {
+		limit, _ = strconv.Atoi(os.Args[2])
+	}
+
+	// cancelled once we stop reading solutions, so a callback that is sending one doesn't block forever
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	powersetSize := boardSize * boardSize
 	state := newBoardState(boardSize)
 
 	// we'll keep track of all the nodes we visited vs all the nodes we skipped, for logging
-	var visited, skipped uint64
+	var visited uint64
+	skipped := new(big.Int)
 
 	// our callback to the powerset.Callback function.  it is in charge of determining if a queen position is valid, and
 	// if it isn't, to backtrack
@@ -172,11 +184,11 @@ func main() {
 			// if we're not feasible, we need to backtrack up to the parent node, and let the other branch (if there is
 			// one) be explored
 			if !feasible {
-				// the following few lines are for book keeping to see how many nodes we skipped by backtracking
-				remainingHeight := powersetSize - len(path)
-				// +1 is for counting total nodes in a binary tree, which is 2^(height+1)-1, and the -2 comes from
-				// skipping the current node, -1, since we visited it, and combining it with the -1
-				skipped = skipped + uint64(math.Pow(2.0, float64(remainingHeight+1))) - 2
+				// the following few lines are for book keeping to see how many nodes we skipped by backtracking.  a
+				// subtree with l leaves has 2l-1 nodes, and we skip all of them except the current node, since we
+				// visited it
+				subtree := new(big.Int).Lsh(path.LeafCount(powersetSize), 1)
+				skipped.Add(skipped, subtree.Sub(subtree, big.NewInt(2)))
 
 				// backtrack up to our parent
 				parent := len(path) - 1
@@ -196,7 +208,9 @@ func main() {
 
 		// we've hit a fully evaluated leaf node.  if there are n queens, yield a copy of the board
 		if isLeaf && state.numQueens == boardSize {
-			out <- copyBoard(board)
+			if err := powerset.Emit(ctx, out, copyBoard(board)); err != nil {
+				return true, -1, nil
+			}
 		}
 
 		// otherwise, continue evaluating nodes
@@ -204,14 +218,18 @@ func main() {
 	}
 
 	// start generating the powerset
-	out := powerset.Callback(powersetSize, cb, state)
+	out := powerset.Callback(powersetSize, cb, state, powerset.WithContext(ctx))
 
 	solutions := 0
 	for board := range out {
 		solutions++
 		PrintBoard(board.(Board))
 		fmt.Println("")
+		if solutions == limit {
+			cancel()
+			break
+		}
 	}
 
-	fmt.Printf("solutions = %v, visited = %+v, skipped = %+v\n", solutions, visited, skipped)
+	fmt.Printf("solutions = %v, visited = %v, skipped = %v\n", solutions, visited, skipped)
 }
@@ -0,0 +1,57 @@
+package powerset
+
+// Explain lists every rule of the family described by cfg that subset breaks, for debugging why an expected combination
+// never shows up in the output.  the rules are "MinSize", "MaxSize", "Required", "Forbidden" and the Names of the
+// Constraints that reject the subset, then "Shard" if it's outside of the Config's Shard, in that order, and a member
+// of the family is explained by "member" alone.  the subset may be in any order.  unlike Verify, every Constraint is
+// checked, even once one has rejected the subset
+func Explain(subset []int, cfg Config) ([]string, error) {
+	fam, err := cfg.compile()
+	if err != nil {
+		return nil, err
+	}
+	sorted, err := sortSubset(fam.lenItems, subset)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := fam.violations(sorted)
+	if len(rules) == 0 {
+		return []string{"member"}, nil
+	}
+	return rules, nil
+}
+
+// violations returns the name of every rule that the sorted subset breaks
+func (fam *family) violations(indices []int) []string {
+	rules := []string{}
+	if len(indices) < fam.minSize {
+		rules = append(rules, "MinSize")
+	}
+	if len(indices) > fam.maxSize {
+		rules = append(rules, "MaxSize")
+	}
+
+	included := make([]bool, fam.lenItems)
+	for _, idx := range indices {
+		included[idx] = true
+	}
+	missing, extra := false, false
+	for idx, m := range fam.members {
+		missing = missing || (m == required && !included[idx])
+		extra = extra || (m == forbidden && included[idx])
+	}
+	if missing {
+		rules = append(rules, "Required")
+	}
+	if extra {
+		rules = append(rules, "Forbidden")
+	}
+
+	for _, c := range fam.constraints {
+		if !c.Allow(indices) {
+			rules = append(rules, c.Name)
+		}
+	}
+	return rules
+}
@@ -0,0 +1,72 @@
+package powerset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	items, _ := NewItems("a", "b", "c", "d", "e")
+	evenSum := Constraint{
+		Name: "even sum",
+		Allow: func(indices []int) bool {
+			sum := 0
+			for _, idx := range indices {
+				sum += idx
+			}
+			return sum%2 == 0
+		},
+	}
+	cfg := Config{
+		LenItems:    5,
+		MinSize:     2,
+		MaxSize:     3,
+		Required:    []int{1},
+		Forbidden:   []int{4},
+		Constraints: []Constraint{evenSum, mustParse(t, "a -> c", items)},
+	}
+
+	explanations := []struct {
+		subset []int
+		rules  []string
+	}{
+		{[]int{3, 1}, []string{"member"}},
+		{[]int{1}, []string{"MinSize", "even sum"}},
+		{[]int{0, 1, 2, 3}, []string{"MaxSize"}},
+		{[]int{4, 0}, []string{"Required", "Forbidden", "a -> c"}},
+		{[]int{0, 1}, []string{"even sum", "a -> c"}},
+	}
+	for _, e := range explanations {
+		rules, err := Explain(e.subset, cfg)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(rules, e.rules) {
+			t.Fatalf("%v: \n%v\n\n!=\n\n%v", e.subset, rules, e.rules)
+		}
+	}
+}
+
+func TestExplainMalformed(t *testing.T) {
+	cfg := Config{LenItems: 3}
+	for _, subset := range [][]int{{3}, {-1}, {1, 1}} {
+		if _, err := Explain(subset, cfg); err == nil {
+			t.Fatalf("expected an error for %v", subset)
+		}
+	}
+	if _, err := Explain(nil, Config{LenItems: -1}); err == nil {
+		t.Fatalf("expected an error for an invalid Config")
+	}
+}
+
+func TestExplainShard(t *testing.T) {
+	cfg := Config{LenItems: 4, MaxSize: 1, Shard: &ShardSpec{Count: 4, Index: 1}, Order: OrderGray}
+	rules, err := Explain([]int{0, 1}, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	correct := []string{"MaxSize", "Shard"}
+	if !reflect.DeepEqual(rules, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", rules, correct)
+	}
+}
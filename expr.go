@@ -0,0 +1,402 @@
+package powerset
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ParseConstraint compiles a small boolean expression over items into a Constraint, so that command lines and
+// configuration files can express constraints without Go code.  the language has:
+//
+//	a           true when item a is included
+//	!x          not
+//	x & y       and
+//	x | y       or
+//	x -> y      implies
+//	x <-> y     if and only if
+//	count(3..5) true when between 3 and 5 items are included, inclusive.  count(3), count(3..) and count(..5) also work
+//	(x)         grouping
+//	true, false
+//
+// from the lowest precedence to the highest, <-> and -> (which are right associative), |, &, !.  items are named by
+// identifiers made of letters, digits and underscores, or by quoted strings for other names, and are resolved with
+// items.  an item can always be named by its index instead, and items may be nil if every item is.
+//
+// besides checking complete subsets, the compiled constraint is evaluated on the partial subsets at the internal nodes
+// of the powerset tree, and prunes a subtree as soon as no subset below it can satisfy the expression
+func ParseConstraint(expr string, items *Items) (Constraint, error) {
+	p := &exprParser{items: items}
+	if err := p.tokenize(expr); err != nil {
+		return Constraint{}, err
+	}
+
+	node, err := p.parseExpr()
+	if err != nil {
+		return Constraint{}, err
+	}
+	if p.pos < len(p.tokens) {
+		return Constraint{}, p.errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+
+	return Constraint{
+		Name: expr,
+		Expr: expr,
+		Allow: func(indices []int) bool {
+			size := node.maxIndex() + 1
+			for _, idx := range indices {
+				if idx >= size {
+					size = idx + 1
+				}
+			}
+			in := make([]bool, size)
+			for _, idx := range indices {
+				in[idx] = true
+			}
+			return node.eval(&exprState{in: in, next: size, count: len(indices)}) == triTrue
+		},
+		expr: node,
+	}, nil
+}
+
+// a three valued truth, for evaluating expressions over partial subsets
+type tri int
+
+const (
+	triFalse tri = iota
+	triUnknown
+	triTrue
+)
+
+func (t tri) not() tri {
+	return triTrue - t
+}
+
+// exprState is a subset that may only be partially decided.  in[idx] is only meaningful for idx < next.  beyond next,
+// members says which indices are required or forbidden, if it isn't nil, and the rest are unknown.  count is how many
+// indices are known to be included, and undecided is how many more might be
+type exprState struct {
+	in        []bool
+	members   []membership
+	next      int
+	count     int
+	undecided int
+}
+
+type exprNode interface {
+	eval(st *exprState) tri
+
+	// the largest index the expression refers to, or -1
+	maxIndex() int
+}
+
+type exprItem int
+
+func (n exprItem) eval(st *exprState) tri {
+	idx := int(n)
+	if idx >= st.next {
+		if idx < len(st.members) {
+			switch st.members[idx] {
+			case required:
+				return triTrue
+			case forbidden:
+				return triFalse
+			}
+		}
+		return triUnknown
+	}
+	if idx < len(st.in) && st.in[idx] {
+		return triTrue
+	}
+	return triFalse
+}
+
+func (n exprItem) maxIndex() int { return int(n) }
+
+type exprConst bool
+
+func (n exprConst) eval(*exprState) tri {
+	if n {
+		return triTrue
+	}
+	return triFalse
+}
+
+func (n exprConst) maxIndex() int { return -1 }
+
+type exprNot struct{ x exprNode }
+
+func (n exprNot) eval(st *exprState) tri { return n.x.eval(st).not() }
+func (n exprNot) maxIndex() int          { return n.x.maxIndex() }
+
+// and, or, implies and iff
+type exprBinary struct {
+	op   string
+	x, y exprNode
+}
+
+func (n exprBinary) eval(st *exprState) tri {
+	x := n.x.eval(st)
+	switch n.op {
+	case "&":
+		if x == triFalse {
+			return triFalse
+		}
+		if y := n.y.eval(st); y < x {
+			return y
+		}
+		return x
+	case "|":
+		if x == triTrue {
+			return triTrue
+		}
+		if y := n.y.eval(st); y > x {
+			return y
+		}
+		return x
+	case "->":
+		return exprBinary{"|", exprNot{n.x}, n.y}.eval(st)
+	default:
+		y := n.y.eval(st)
+		if x == triUnknown || y == triUnknown {
+			return triUnknown
+		}
+		if x == y {
+			return triTrue
+		}
+		return triFalse
+	}
+}
+
+func (n exprBinary) maxIndex() int {
+	if x, y := n.x.maxIndex(), n.y.maxIndex(); x > y {
+		return x
+	}
+	return n.y.maxIndex()
+}
+
+// true when the number of included items is between lo and hi, inclusive.  hi is -1 for no upper bound
+type exprCount struct {
+	lo, hi int
+}
+
+func (n exprCount) eval(st *exprState) tri {
+	least, most := st.count, st.count+st.undecided
+	if (n.hi >= 0 && least > n.hi) || most < n.lo {
+		return triFalse
+	}
+	if least >= n.lo && (n.hi < 0 || most <= n.hi) {
+		return triTrue
+	}
+	return triUnknown
+}
+
+func (n exprCount) maxIndex() int { return -1 }
+
+type exprToken struct {
+	text   string
+	quoted bool
+	pos    int
+}
+
+type exprParser struct {
+	items  *Items
+	tokens []exprToken
+	pos    int
+	input  string
+}
+
+func (p *exprParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("powerset: constraint %q: "+format, append([]interface{}{p.input}, args...)...)
+}
+
+func (p *exprParser) tokenize(input string) error {
+	p.input = input
+	for i := 0; i < len(input); {
+		c, size := utf8.DecodeRuneInString(input[i:])
+		switch {
+		case unicode.IsSpace(c):
+			i += size
+		case strings.HasPrefix(input[i:], "<->"):
+			p.tokens = append(p.tokens, exprToken{text: "<->", pos: i})
+			i += 3
+		case strings.HasPrefix(input[i:], "->"), strings.HasPrefix(input[i:], ".."):
+			p.tokens = append(p.tokens, exprToken{text: input[i : i+2], pos: i})
+			i += 2
+		case strings.ContainsRune("!&|(),", c):
+			p.tokens = append(p.tokens, exprToken{text: string(c), pos: i})
+			i++
+		case c == '"':
+			end := strings.IndexByte(input[i+1:], '"')
+			if end < 0 {
+				return p.errorf("unterminated quote at %d", i)
+			}
+			p.tokens = append(p.tokens, exprToken{text: input[i+1 : i+1+end], quoted: true, pos: i})
+			i += end + 2
+		case c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c):
+			start := i
+			for i < len(input) {
+				c, size := utf8.DecodeRuneInString(input[i:])
+				if c != '_' && !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+					break
+				}
+				i += size
+			}
+			p.tokens = append(p.tokens, exprToken{text: input[start:i], pos: start})
+		default:
+			return p.errorf("unexpected %q at %d", c, i)
+		}
+	}
+	return nil
+}
+
+func (p *exprParser) peek() string {
+	if p.pos < len(p.tokens) && !p.tokens[p.pos].quoted {
+		return p.tokens[p.pos].text
+	}
+	return ""
+}
+
+func (p *exprParser) expect(text string) error {
+	if p.peek() != text {
+		if p.pos >= len(p.tokens) {
+			return p.errorf("expected %q at the end", text)
+		}
+		return p.errorf("expected %q at %d", text, p.tokens[p.pos].pos)
+	}
+	p.pos++
+	return nil
+}
+
+// expr := or (("->" | "<->") expr)?
+func (p *exprParser) parseExpr() (exprNode, error) {
+	x, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if op := p.peek(); op == "->" || op == "<->" {
+		p.pos++
+		y, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		return exprBinary{op, x, y}, nil
+	}
+	return x, nil
+}
+
+// or := and ("|" and)*
+func (p *exprParser) parseOr() (exprNode, error) {
+	x, err := p.parseAnd()
+	for err == nil && p.peek() == "|" {
+		p.pos++
+		var y exprNode
+		y, err = p.parseAnd()
+		x = exprBinary{"|", x, y}
+	}
+	return x, err
+}
+
+// and := unary ("&" unary)*
+func (p *exprParser) parseAnd() (exprNode, error) {
+	x, err := p.parseUnary()
+	for err == nil && p.peek() == "&" {
+		p.pos++
+		var y exprNode
+		y, err = p.parseUnary()
+		x = exprBinary{"&", x, y}
+	}
+	return x, err
+}
+
+// unary := "!" unary | "(" expr ")" | "count" "(" range ")" | "true" | "false" | item
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, p.errorf("unexpected end")
+	}
+	tok := p.tokens[p.pos]
+
+	switch p.peek() {
+	case "!":
+		p.pos++
+		x, err := p.parseUnary()
+		return exprNot{x}, err
+	case "(":
+		p.pos++
+		x, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		return x, p.expect(")")
+	case "true", "false":
+		p.pos++
+		return exprConst(tok.text == "true"), nil
+	case "count":
+		if p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].text == "(" {
+			p.pos += 2
+			return p.parseCount()
+		}
+	case "&", "|", "->", "<->", ")", ",", "..":
+		return nil, p.errorf("unexpected %q at %d", tok.text, tok.pos)
+	}
+
+	p.pos++
+	idx, err := p.resolve(tok)
+	return exprItem(idx), err
+}
+
+// range := int | int? ".." int?, followed by the closing parenthesis
+func (p *exprParser) parseCount() (exprNode, error) {
+	lo, err := 0, error(nil)
+	if p.peek() != ".." {
+		if lo, err = p.parseInt(); err != nil {
+			return nil, err
+		}
+	}
+	n := exprCount{lo: lo, hi: lo}
+	if p.peek() == ".." {
+		p.pos++
+		n.hi = -1
+		if p.peek() != ")" {
+			if n.hi, err = p.parseInt(); err != nil {
+				return nil, err
+			}
+			if n.hi < n.lo {
+				return nil, p.errorf("empty count range %d..%d", n.lo, n.hi)
+			}
+		}
+	}
+	return n, p.expect(")")
+}
+
+func (p *exprParser) parseInt() (int, error) {
+	if p.pos >= len(p.tokens) {
+		return 0, p.errorf("expected a number at the end")
+	}
+	tok := p.tokens[p.pos]
+	value, err := strconv.Atoi(tok.text)
+	if err != nil || tok.quoted || value < 0 {
+		return 0, p.errorf("expected a number at %d", tok.pos)
+	}
+	p.pos++
+	return value, nil
+}
+
+// resolve turns an item token into its index
+func (p *exprParser) resolve(tok exprToken) (int, error) {
+	if !tok.quoted {
+		if idx, err := strconv.Atoi(tok.text); err == nil {
+			return idx, nil
+		}
+	}
+	if p.items == nil {
+		return 0, p.errorf("can't resolve item %q without an Items registry", tok.text)
+	}
+	idx, err := p.items.Index(tok.text)
+	if err != nil {
+		return 0, p.errorf("%w", err)
+	}
+	return idx, nil
+}
@@ -0,0 +1,177 @@
+package powerset
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// the subsets of [0, n) that satisfy allow, in canonical order
+func filterPowerset(n int, allow func([]int) bool) [][]int {
+	out, _, _ := Family(Config{LenItems: n})
+	allValues := [][]int{}
+	for indices := range out {
+		if allow(indices) {
+			allValues = append(allValues, indices)
+		}
+	}
+	return allValues
+}
+
+func TestParseConstraint(t *testing.T) {
+	items, _ := NewItems("a", "b", "c", "d", "two words")
+	has := func(indices []int, idx int) bool {
+		for _, included := range indices {
+			if included == idx {
+				return true
+			}
+		}
+		return false
+	}
+
+	tests := []struct {
+		expr    string
+		correct func(indices []int) bool
+	}{
+		{"a -> b", func(s []int) bool { return !has(s, 0) || has(s, 1) }},
+		{"!(c & d)", func(s []int) bool { return !(has(s, 2) && has(s, 3)) }},
+		{"count(3..5)", func(s []int) bool { return len(s) >= 3 && len(s) <= 5 }},
+		{"count(2)", func(s []int) bool { return len(s) == 2 }},
+		{"count(4..)", func(s []int) bool { return len(s) >= 4 }},
+		{"a | b & c", func(s []int) bool { return has(s, 0) || (has(s, 1) && has(s, 2)) }},
+		{"a -> b -> c", func(s []int) bool { return !has(s, 0) || !has(s, 1) || has(s, 2) }},
+		{`"two words" <-> 0`, func(s []int) bool { return has(s, 4) == has(s, 0) }},
+		{"(a | d) & !count(0..1)", func(s []int) bool { return (has(s, 0) || has(s, 3)) && len(s) > 1 }},
+		{"true & !false", func(s []int) bool { return true }},
+	}
+
+	for _, test := range tests {
+		c, err := ParseConstraint(test.expr, items)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", test.expr, err)
+		}
+		allValues := collectFamily(t, Config{LenItems: 5, Constraints: []Constraint{c}})
+		correct := filterPowerset(5, test.correct)
+		if !reflect.DeepEqual(allValues, correct) {
+			t.Fatalf("%q:\n%v\n\n!=\n\n%v", test.expr, allValues, correct)
+		}
+	}
+}
+
+func TestParseConstraintWithRules(t *testing.T) {
+	c, err := ParseConstraint("(0 -> 2) & count(2..3)", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg := Config{LenItems: 6, MaxSize: 3, Required: []int{1}, Forbidden: []int{4}, Constraints: []Constraint{c}}
+	allValues := collectFamily(t, cfg)
+
+	plain := cfg
+	plain.Constraints = []Constraint{{Name: "leaves only", Allow: c.Allow}}
+	correct := collectFamily(t, plain)
+	if !reflect.DeepEqual(allValues, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", allValues, correct)
+	}
+}
+
+func TestParseConstraintPrunes(t *testing.T) {
+	c, _ := ParseConstraint("!0 & count(..2)", nil)
+	out, ctl, _ := Start(Config{LenItems: 12, Constraints: []Constraint{c}})
+	count := 0
+	for range out {
+		count++
+	}
+	if count != 1+11+55 {
+		t.Fatalf("generated %d subsets, expected 67", count)
+	}
+
+	// without pruning, the walk would visit every one of the 2^13-1 nodes
+	if nodes := ctl.Stats().Nodes; nodes >= 1000 {
+		t.Fatalf("visited %d nodes, expected the expression to prune the tree", nodes)
+	}
+}
+
+// unquoted names can use letters from outside of ASCII
+func TestParseConstraintUnicode(t *testing.T) {
+	items, _ := NewItems("café", "thé", "naïve")
+	c, err := ParseConstraint("café & !thé\u00a0| naïve", items)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	allValues := collectFamily(t, Config{LenItems: 3, Constraints: []Constraint{c}})
+	correct := [][]int{{2}, {1, 2}, {0}, {0, 2}, {0, 1, 2}}
+	if !reflect.DeepEqual(allValues, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", allValues, correct)
+	}
+}
+
+func TestParseConstraintErrors(t *testing.T) {
+	items, _ := NewItems("a")
+
+	invalid := []string{
+		"",
+		"a &",
+		"a b",
+		"(a",
+		"a)",
+		"missing",
+		`"a`,
+		"count(3..1)",
+		"count(x)",
+		"count()",
+		"a ? a",
+	}
+	for _, expr := range invalid {
+		if _, err := ParseConstraint(expr, items); err == nil {
+			t.Fatalf("expected %q to be an error", expr)
+		}
+	}
+
+	var unknown *UnknownItemError
+	if _, err := ParseConstraint("b", items); !errors.As(err, &unknown) || unknown.Name != "b" {
+		t.Fatalf("expected an UnknownItemError, got %v", err)
+	}
+
+	if _, err := ParseConstraint("a", nil); err == nil {
+		t.Fatalf("expected a name to be an error without an Items registry")
+	}
+
+	c, _ := ParseConstraint("5", nil)
+	if _, _, err := Family(Config{LenItems: 3, Constraints: []Constraint{c}}); err == nil {
+		t.Fatalf("expected an out of range index to be an error")
+	}
+}
+
+func TestConfigJSONExpr(t *testing.T) {
+	items, _ := NewItems("a", "b", "c")
+	implies, _ := ParseConstraint("a -> b", items)
+	small, _ := ParseConstraint("count(..2)", nil)
+	small.Name = "small"
+	cfg := Config{LenItems: 3, Constraints: []Constraint{implies, small}}
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	correct := `{"lenItems":3,"constraints":[{"expr":"a -\u003e b"},{"name":"small","expr":"count(..2)"}]}`
+	if string(data) != correct {
+		t.Fatalf("\n%s\n!=\n%s", data, correct)
+	}
+
+	if _, err := LoadConfig(strings.NewReader(string(data))); err == nil {
+		t.Fatalf("expected item names to be an error without an Items registry")
+	}
+	loaded, err := items.LoadConfig(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	allValues := collectFamily(t, loaded)
+	if expected := collectFamily(t, cfg); !reflect.DeepEqual(allValues, expected) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", allValues, expected)
+	}
+	if loaded.Constraints[1].Name != "small" {
+		t.Fatalf("lost the constraint name, got %q", loaded.Constraints[1].Name)
+	}
+}
@@ -0,0 +1,408 @@
+package powerset
+
+import "fmt"
+
+// Family generates every subset in the family described by cfg.  each slice returned on the output channel contains the
+// sorted indices of the items included in the subset, and subsets come out in the same order as FixedSize.  branches of
+// the powerset tree that can't satisfy the size bounds, required or forbidden indices are pruned instead of being
+// generated and discarded, as are branches that can't satisfy a constraint built by ParseConstraint, while other
+// Constraints are checked at the leaves.  Family understands the same options as Start except WithRingBuffer, which is
+// an error, since a ring buffer is read with the Control handle that Family replaces with a plain stop function
+func Family(cfg Config, opts ...Option) (<-chan []int, func(), error) {
+	if buildOptions(opts).ringSize > 0 {
+		return nil, nil, fmt.Errorf("powerset: Family doesn't support WithRingBuffer, use Start")
+	}
+	out, ctl, err := Start(cfg, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, ctl.Stop, nil
+}
+
+// Start generates the family described by cfg exactly like Family, but returns a Control handle that can pause, resume
+// and stop the search.  Start understands WithBloomDedup, WithCanonicalizer, WithRateLimit,
+// WithBackground, WithSizeStats, WithTiming, WithQuotaPerSize, WithMaxSolutions, WithSolutionDeadline, WithOrder,
+// WithRingBuffer, WithOwnership, WithMemoryBudget, WithFeasibilityCheck, WithTargetSum,
+// WithInterchangeable, WithAutoEngine, WithJournal, WithTransport, WithAdaptivePruning, WithProfileLabel and
+// WithContext
+func Start(cfg Config, opts ...Option) (<-chan []int, *Control, error) {
+	return start(cfg, opts, nil)
+}
+
+// start is Start, sending subsets on borrowedOut instead of the returned channel if it isn't nil
+func start(cfg Config, opts []Option, borrowedOut chan<- Borrowed[[]int]) (<-chan []int, *Control, error) {
+	fam, err := cfg.compile()
+	if err != nil {
+		return nil, nil, err
+	}
+	o := buildOptions(opts)
+	if o.order == OrderCanonical {
+		o.order = cfg.Order
+	}
+	switch o.order {
+	case OrderLexicographic:
+		return nil, nil, fmt.Errorf("powerset: Start doesn't support OrderLexicographic")
+	case OrderColex:
+		return nil, nil, fmt.Errorf("powerset: Start doesn't support OrderColex")
+	}
+	if o.solver != nil {
+		if err := fam.feasible(o.solver); err != nil {
+			return nil, nil, err
+		}
+	}
+	if o.targetSum != nil {
+		if err := o.targetSum.prepare(fam.lenItems); err != nil {
+			return nil, nil, err
+		}
+		fam.constraints = append(append([]Constraint{}, fam.constraints...), o.targetSum.constraint())
+	}
+	var ic *interchange
+	if o.interchangeable != nil {
+		if ic, err = newInterchange(fam.lenItems, o.interchangeable); err != nil {
+			return nil, nil, err
+		}
+		fam.constraints = append(append([]Constraint{}, fam.constraints...), ic.constraint())
+	}
+	if borrowedOut != nil {
+		o.ringSize = 0
+		o.transport = nil
+	}
+	e := newEmitter(o)
+	if o.journalPath != "" {
+		if e.journal, err = openJournal(o.journalPath); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	stats := newSearchStats(fam.lenItems, o)
+	stats.engine = fam.engine(o)
+	if o.adaptive != nil {
+		stats.adaptive = newAdaptiveController(o.adaptive)
+	}
+	stats.memory = e.memory
+	if stats.calls != nil {
+		stats.timeConstraints(fam, o, len(cfg.Constraints))
+	}
+	ctl := newControl(stats)
+	ctl.recorder = newRecorder(cfg)
+	e.recorder = ctl.recorder
+	var out chan []int
+	switch {
+	case e.ring != nil:
+		ctl.ring = e.ring
+	case borrowedOut != nil:
+		e.transport = &borrowedTransport{out: borrowedOut, waited: e.waited}
+	case e.transport == nil:
+		out = make(chan []int)
+		e.transport = &channelTransport{out: out, waited: e.waited}
+	}
+
+	var throttle *backgroundThrottle
+	if o.background {
+		throttle = &backgroundThrottle{}
+	}
+	var watchdog *solutionWatchdog
+	if o.solutionDeadline > 0 {
+		watchdog = newSolutionWatchdog(o.solutionDeadline)
+	}
+	checkpoint := func() bool {
+		stats.nodes.Add(1)
+		ctl.recorder.tick(stats)
+		if throttle != nil {
+			throttle.tick()
+		}
+		ok, waited := ctl.checkpoint()
+		if !ok {
+			ctl.end(ReasonStopped, nil)
+			return false
+		}
+		if watchdog != nil && !watchdog.check(waited) {
+			ctl.end(ReasonDeadline, ErrSolutionDeadline)
+			return false
+		}
+		return true
+	}
+
+	release := func() bool { return false }
+	if o.ctx != nil {
+		release = ctl.cancelWith(o.ctx)
+	}
+
+	ctl.wg.Add(1)
+	go o.profiled("Family", 0, func() {
+		defer release()
+		defer ctl.wg.Done()
+		defer e.done()
+		defer ctl.recorder.finish()
+		defer ctl.end(ReasonCompleted, nil)
+		if e.journal != nil {
+			defer func() {
+				if err := e.journal.close(); err != nil {
+					ctl.end(ReasonJournal, err)
+				}
+			}()
+		}
+
+		// sends a subset, returning false when the search should stop
+		emit := func(subset []int) bool {
+			// the subset may be recycled once it's sent, so it's scored first
+			var score float64
+			if stats.scores != nil {
+				score = stats.scores.score(subset)
+			}
+			if !e.send(subset, ctl.stopIn) {
+				if e.journal != nil && e.journal.err != nil {
+					ctl.end(ReasonJournal, e.journal.err)
+				} else {
+					ctl.end(ReasonStopped, nil)
+				}
+				return false
+			}
+			stats.addEmitted(len(subset))
+			if stats.scores != nil {
+				stats.scores.record(score)
+			}
+			if watchdog != nil {
+				watchdog.reset()
+			}
+			if o.maxSolutions > 0 && stats.emitted.Load() >= uint64(o.maxSolutions) {
+				ctl.end(ReasonMaxSolutions, nil)
+				return false
+			}
+			return true
+		}
+
+		var reorder *localityBuffer
+		if o.order == OrderLocality {
+			reorder = newLocalityBuffer(o.lookahead, e.memory)
+		}
+
+		visit := func(indices []int) bool {
+			if !checkpoint() {
+				return false
+			}
+			subset, ok := e.accept(indices)
+			if !ok {
+				stats.suppressed.Add(1)
+				return true
+			}
+			if reorder == nil {
+				return emit(subset)
+			}
+
+			reorder.push(subset)
+			if e.memory.over() {
+				e.relieve()
+				reorder.shrink()
+			}
+			for subset, ok := reorder.ready(); ok; subset, ok = reorder.ready() {
+				if !emit(subset) {
+					return false
+				}
+			}
+			return true
+		}
+
+		if stats.engine == EngineBitmask || stats.engine == EngineGray {
+			if !fam.countUp(stats.engine == EngineGray, visit) {
+				return
+			}
+		} else {
+			stopped := false
+			// the rule of the last subtree the prune hook skipped
+			hookRule := ""
+			for _, p := range fam.passes(o.order) {
+				p := p
+				cont := p.fam.walkHooks(walkHooks{
+					visit: visit,
+					prune: func(included []int, next int) bool {
+						if !checkpoint() {
+							stopped = true
+						}
+						if stopped {
+							return true
+						}
+						if e.exhausted(p.fam.reachableSizes(len(included), next)) {
+							hookRule = "WithQuotaPerSize"
+							return true
+						}
+						if o.targetSum != nil && !o.targetSum.completable(included, next) {
+							hookRule = targetSumRule
+							return true
+						}
+						if ic != nil && !ic.canonical(included, next) {
+							hookRule = interchangeableRule
+							return true
+						}
+						if stats.adaptive != nil {
+							if rule, ok := stats.adaptive.prune(included, next, stats.emitted.Load()); ok {
+								hookRule = rule
+								return true
+							}
+						}
+						return false
+					},
+					pruned: func(numIncluded int, numUndecided int, rule string) {
+						// the rest of the tree is skipped once we've been stopped, but it wasn't pruned
+						if stopped {
+							return
+						}
+						if rule == prunedByHook {
+							rule = hookRule
+						}
+						stats.addPruned(numIncluded, numUndecided, p.size, rule)
+					},
+					gray: o.order == OrderGray || o.order == OrderLocality,
+				})
+				if !cont || stopped {
+					return
+				}
+			}
+		}
+
+		for reorder != nil {
+			subset, ok := reorder.pop()
+			if !ok || !emit(subset) {
+				return
+			}
+		}
+	})
+
+	return out, ctl, nil
+}
+
+// the rule reported for subtrees skipped by the prune hook
+const prunedByHook = "prune"
+
+// walkHooks are the callbacks a walk reports to.  only visit is required
+type walkHooks struct {
+	// called at every member of the family, with a slice that is reused between calls.  returning false stops the walk
+	visit func(indices []int) bool
+
+	// called at every internal node with the indices included so far and the next index to decide.  returning true
+	// skips the node's subtree
+	prune func(included []int, next int) bool
+
+	// called for every subtree the walk skips, whether by prune, the family's rules or a rejected leaf, with how many
+	// indices were included at its root, how many indices were left undecided below it, and the rule that pruned it,
+	// named like the rules of contains, or prunedByHook
+	pruned func(numIncluded int, numUndecided int, rule string)
+
+	// walk in reflected Gray code order instead of the canonical order, so that consecutive subsets of the full
+	// powerset differ by a single index
+	gray bool
+}
+
+// walk visits every member of the family in order, stopping early if visit returns false.  the slice passed to visit
+// is reused between calls, so it must be copied if it is retained.  if prune is not nil, it is called at every internal
+// node with the indices included so far and the next index to decide, and returning true skips that node's subtree.
+// returns false if the walk was stopped early
+func (fam *family) walk(visit func([]int) bool, prune func(included []int, next int) bool) bool {
+	return fam.walkHooks(walkHooks{visit: visit, prune: prune})
+}
+
+// walkHooks is walk, with every hook available
+func (fam *family) walkHooks(hooks walkHooks) bool {
+	pruned := hooks.pruned
+	if pruned == nil {
+		pruned = func(int, int, string) {}
+	}
+
+	if fam.empty {
+		pruned(0, fam.lenItems, "empty family")
+		return true
+	}
+
+	availFrom, requiredFrom := fam.availFrom, fam.requiredFrom
+	included := make([]int, 0, fam.lenItems)
+	var partial *exprState
+	if len(fam.exprs) > 0 {
+		partial = &exprState{in: make([]bool, fam.lenItems), members: fam.members}
+	}
+
+	var recurse func(n int) bool
+	recurse = func(n int) bool {
+		if n == fam.lenItems {
+			for _, c := range fam.constraints {
+				if !c.Allow(included) {
+					pruned(len(included), 0, c.Name)
+					return true
+				}
+			}
+			return hooks.visit(included)
+		}
+
+		if hooks.prune != nil && hooks.prune(included, n) {
+			pruned(len(included), fam.lenItems-n, prunedByHook)
+			return true
+		}
+		if fam.shard != nil && !fam.shard.overlaps(included, n) {
+			pruned(len(included), fam.lenItems-n, shardRule)
+			return true
+		}
+		if partial != nil {
+			if ok, rule := fam.satisfiable(partial, len(included), n); !ok {
+				pruned(len(included), fam.lenItems-n, rule)
+				return true
+			}
+		}
+
+		count := len(included)
+		member := fam.members[n]
+		undecided := fam.lenItems - n - 1
+
+		exclude := func() bool {
+			if member != required && count+availFrom[n+1] >= fam.minSize {
+				return recurse(n + 1)
+			}
+			if member == required {
+				pruned(count, undecided, "Required")
+			} else {
+				pruned(count, undecided, "MinSize")
+			}
+			return true
+		}
+		include := func() bool {
+			if member != forbidden && count+1+requiredFrom[n+1] <= fam.maxSize {
+				included = append(included, n)
+				if partial != nil {
+					partial.in[n] = true
+				}
+				cont := recurse(n + 1)
+				if partial != nil {
+					partial.in[n] = false
+				}
+				included = included[:count]
+				return cont
+			}
+			if member == forbidden {
+				pruned(count+1, undecided, "Forbidden")
+			} else {
+				pruned(count+1, undecided, "MaxSize")
+			}
+			return true
+		}
+
+		// the reflected Gray code visits a node's children in reverse whenever an odd number of indices are included
+		if hooks.gray && count%2 == 1 {
+			return include() && exclude()
+		}
+		return exclude() && include()
+	}
+
+	return recurse(0)
+}
+
+// satisfiable reports whether every parsed constraint could still hold below a node where numIncluded indices have been
+// included and next is the next index to decide, returning the name of the first that can't if one can't
+func (fam *family) satisfiable(st *exprState, numIncluded int, next int) (bool, string) {
+	st.next = next
+	st.count = numIncluded + fam.requiredFrom[next]
+	st.undecided = fam.availFrom[next] - fam.requiredFrom[next]
+	for _, c := range fam.exprs {
+		if c.expr.eval(st) == triFalse {
+			return false, c.Name
+		}
+	}
+	return true, ""
+}
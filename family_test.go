@@ -0,0 +1,155 @@
+package powerset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func collectFamily(t *testing.T, cfg Config, opts ...Option) [][]int {
+	out, _, err := Family(cfg, opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	allValues := [][]int{}
+	for indices := range out {
+		allValues = append(allValues, indices)
+	}
+	return allValues
+}
+
+func TestFamilyUnconstrained(t *testing.T) {
+	allValues := collectFamily(t, Config{LenItems: 3})
+	correct := [][]int{
+		{},
+		{2},
+		{1},
+		{1, 2},
+		{0},
+		{0, 2},
+		{0, 1},
+		{0, 1, 2},
+	}
+	if !reflect.DeepEqual(correct, allValues) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", allValues, correct)
+	}
+}
+
+// a ring buffer can only be read through a Control, which Family doesn't return
+func TestFamilyRingBuffer(t *testing.T) {
+	if _, _, err := Family(Config{LenItems: 3}, WithRingBuffer(4)); err == nil {
+		t.Fatalf("expected an error")
+	}
+	items, _ := NewItems("a", "b")
+	if _, _, err := items.Family(ItemsConfig{}, WithRingBuffer(4)); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestFamilyBounds(t *testing.T) {
+	allValues := collectFamily(t, Config{
+		LenItems:  4,
+		MinSize:   2,
+		MaxSize:   3,
+		Required:  []int{1},
+		Forbidden: []int{3},
+	})
+	correct := [][]int{
+		{1, 2},
+		{0, 1},
+		{0, 1, 2},
+	}
+	if !reflect.DeepEqual(correct, allValues) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", allValues, correct)
+	}
+}
+
+func TestFamilyConstraints(t *testing.T) {
+	noAdjacent := Constraint{
+		Name: "no adjacent",
+		Allow: func(indices []int) bool {
+			for i := 1; i < len(indices); i++ {
+				if indices[i] == indices[i-1]+1 {
+					return false
+				}
+			}
+			return true
+		},
+	}
+
+	allValues := collectFamily(t, Config{LenItems: 4, MinSize: 2, Constraints: []Constraint{noAdjacent}})
+	correct := [][]int{
+		{1, 3},
+		{0, 3},
+		{0, 2},
+	}
+	if !reflect.DeepEqual(correct, allValues) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", allValues, correct)
+	}
+}
+
+func TestFamilyContradiction(t *testing.T) {
+	allValues := collectFamily(t, Config{LenItems: 3, Required: []int{0}, Forbidden: []int{0}})
+	if len(allValues) != 0 {
+		t.Fatalf("expected an empty family, got %v", allValues)
+	}
+}
+
+func TestFamilyStop(t *testing.T) {
+	out, stop, _ := Family(Config{LenItems: 10})
+
+	i := 0
+	for range out {
+		if i == 3 {
+			stop()
+			break
+		}
+		i++
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	invalid := []Config{
+		{LenItems: -1},
+		{LenItems: 3, MinSize: -1},
+		{LenItems: 3, MinSize: 3, MaxSize: 2},
+		{LenItems: 3, Required: []int{3}},
+		{LenItems: 3, Forbidden: []int{-1}},
+		{LenItems: 3, Constraints: []Constraint{{Name: "nil"}}},
+	}
+	for _, cfg := range invalid {
+		if err := cfg.Validate(); err == nil {
+			t.Fatalf("expected %+v to be invalid", cfg)
+		}
+		if _, _, err := Family(cfg); err == nil {
+			t.Fatalf("expected Family to reject %+v", cfg)
+		}
+	}
+}
+
+// the shards of a family are its members split by rank, in the order the Config asks for
+func TestFamilyShardAndOrder(t *testing.T) {
+	base := Config{LenItems: 6, MinSize: 1, Forbidden: []int{2}}
+	for _, order := range []Order{OrderCanonical, OrderGray, OrderZigZag} {
+		cfg := base
+		cfg.Order = order
+		whole := collectFamily(t, cfg)
+		if got := collectFamily(t, base, WithOrder(order)); !reflect.DeepEqual(whole, got) {
+			t.Fatalf("order %d:\n%v\n\n!=\n\n%v", order, whole, got)
+		}
+
+		for shard := 0; shard < 3; shard++ {
+			cfg.Shard = &ShardSpec{Count: 3, Index: shard}
+			lo, hi, _ := Shard(6, 3, shard)
+			correct := [][]int{}
+			for _, subset := range whole {
+				if rank := Rank(subset, 6); rank.Cmp(lo) >= 0 && rank.Cmp(hi) <= 0 {
+					correct = append(correct, subset)
+				}
+			}
+			if got := collectFamily(t, cfg); !reflect.DeepEqual(got, correct) {
+				t.Fatalf("order %d, shard %d:\n%v\n\n!=\n\n%v", order, shard, got, correct)
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package powerset
+
+import (
+	"fmt"
+	"sync"
+)
+
+// FlagFailure is a combination of feature flags that a handler failed on
+type FlagFailure struct {
+	Enabled []string
+	Err     error
+}
+
+// EvaluateFlags calls handler with every valid combination of flags, and streams the combinations it returns an error
+// for, so integration tests can check that a service, like an HTTP handler wrapped in the middleware each flag turns
+// on, works with any set of flags that could be enabled in production.  compatible is a square matrix over flags, and a
+// combination is valid when compatible[i][j] is true for every i and j it enables, so a flag whose own entry is false
+// is never enabled.  enabled is the names of the enabled flags, in the order of flags, and the handler is called from a
+// single goroutine in the same order as Family.  the output channel is closed once every combination has been tried, or
+// once the returned function stops the evaluation
+func EvaluateFlags(flags []string, compatible [][]bool, handler func(enabled []string) error) (<-chan FlagFailure,
+	func(), error) {
+
+	if len(compatible) != len(flags) {
+		return nil, nil, fmt.Errorf("powerset: compatibility matrix has %d rows for %d flags", len(compatible),
+			len(flags))
+	}
+	cfg := Config{LenItems: len(flags)}
+	for i, row := range compatible {
+		if len(row) != len(flags) {
+			return nil, nil, fmt.Errorf("powerset: compatibility matrix row %d has %d columns for %d flags", i,
+				len(row), len(flags))
+		}
+		for j := i + 1; j < len(flags); j++ {
+			if compatible[i][j] && compatible[j][i] {
+				continue
+			}
+			c, err := ParseConstraint(fmt.Sprintf("!(%d & %d)", i, j), nil)
+			if err != nil {
+				return nil, nil, err
+			}
+			c.Name = fmt.Sprintf("%s is incompatible with %s", flags[i], flags[j])
+			cfg.Constraints = append(cfg.Constraints, c)
+		}
+		if !row[i] {
+			cfg.Forbidden = append(cfg.Forbidden, i)
+		}
+	}
+	fam, err := cfg.compile()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := make(chan FlagFailure)
+	stopIn := make(chan bool)
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		defer close(out)
+		defer wg.Done()
+
+		fam.walk(func(indices []int) bool {
+			enabled := make([]string, len(indices))
+			for i, idx := range indices {
+				enabled[i] = flags[idx]
+			}
+			if err := handler(enabled); err != nil {
+				select {
+				case <-stopIn:
+					return false
+				case out <- FlagFailure{Enabled: enabled, Err: err}:
+				}
+			}
+			select {
+			case <-stopIn:
+				return false
+			default:
+				return true
+			}
+		}, nil)
+	}()
+
+	return out, makeStopper(stopIn, wg), nil
+}
@@ -0,0 +1,76 @@
+package powerset
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEvaluateFlags(t *testing.T) {
+	flags := []string{"gzip", "brotli", "auth", "debug"}
+	compatible := [][]bool{
+		{true, false, true, true},
+		{false, true, true, true},
+		{true, true, true, true},
+		{true, true, true, false},
+	}
+
+	tried := [][]string{}
+	broken := errors.New("auth breaks compression")
+	out, _, err := EvaluateFlags(flags, compatible, func(enabled []string) error {
+		tried = append(tried, enabled)
+		joined := strings.Join(enabled, ",")
+		if strings.Contains(joined, "auth") && (strings.Contains(joined, "gzip") || strings.Contains(joined, "brotli")) {
+			return broken
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	failures := [][]string{}
+	for failure := range out {
+		if failure.Err != broken {
+			t.Fatalf("unexpected error %v", failure.Err)
+		}
+		failures = append(failures, failure.Enabled)
+	}
+
+	correctTried := [][]string{{}, {"auth"}, {"brotli"}, {"brotli", "auth"}, {"gzip"}, {"gzip", "auth"}}
+	if !reflect.DeepEqual(tried, correctTried) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", tried, correctTried)
+	}
+	correctFailures := [][]string{{"brotli", "auth"}, {"gzip", "auth"}}
+	if !reflect.DeepEqual(failures, correctFailures) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", failures, correctFailures)
+	}
+}
+
+func TestEvaluateFlagsStop(t *testing.T) {
+	flags := []string{"a", "b", "c", "d", "e", "f"}
+	compatible := make([][]bool, len(flags))
+	for i := range compatible {
+		compatible[i] = []bool{true, true, true, true, true, true}
+	}
+	out, stop, err := EvaluateFlags(flags, compatible, func([]string) error { return errors.New("fail") })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-out
+	<-out
+	stop()
+	for range out {
+	}
+}
+
+func TestEvaluateFlagsBadMatrix(t *testing.T) {
+	handler := func([]string) error { return nil }
+	if _, _, err := EvaluateFlags([]string{"a", "b"}, [][]bool{{true, true}}, handler); err == nil {
+		t.Fatalf("expected an error for a missing row")
+	}
+	if _, _, err := EvaluateFlags([]string{"a", "b"}, [][]bool{{true}, {true, true}}, handler); err == nil {
+		t.Fatalf("expected an error for a short row")
+	}
+}
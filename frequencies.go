@@ -0,0 +1,99 @@
+package powerset
+
+import "math/big"
+
+// Frequencies describes how often each index appears across the subsets of a family.  Counts[i] is the number of
+// subsets containing index i, and Total is the number of subsets in the family
+type Frequencies struct {
+	Total  *big.Int
+	Counts []*big.Int
+}
+
+// Probability returns the probability that index i is included in a subset drawn uniformly from the family.  an empty
+// family has a probability of zero for every index
+func (freq *Frequencies) Probability(i int) *big.Rat {
+	if freq.Total.Sign() == 0 {
+		return new(big.Rat)
+	}
+	return new(big.Rat).SetFrac(freq.Counts[i], freq.Total)
+}
+
+// ElementFrequencies counts, for each index, how many subsets of the family described by cfg include it.  when the
+// Config only uses size bounds and required or forbidden indices, the counts are computed exactly with binomial
+// coefficients without generating a single subset.  Constraints and shards can't be counted analytically, so a Config
+// with either is generated with Family and its subsets are counted as they're streamed
+func ElementFrequencies(cfg Config) (*Frequencies, error) {
+	fam, err := cfg.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fam.constraints) > 0 {
+		freq := newFrequencies(fam.lenItems)
+		fam.walk(func(indices []int) bool {
+			freq.add(indices)
+			return true
+		}, nil)
+		return freq, nil
+	}
+	return fam.countFrequencies(), nil
+}
+
+// StreamFrequencies counts how many of the subsets received on in include each index, until in is closed.  this is
+// for families that can only be described by the stream that generates them, like the solutions written by a Callback
+func StreamFrequencies(lenItems int, in <-chan []int) *Frequencies {
+	freq := newFrequencies(lenItems)
+	for indices := range in {
+		freq.add(indices)
+	}
+	return freq
+}
+
+func newFrequencies(lenItems int) *Frequencies {
+	freq := &Frequencies{
+		Total:  new(big.Int),
+		Counts: make([]*big.Int, lenItems),
+	}
+	for i := range freq.Counts {
+		freq.Counts[i] = new(big.Int)
+	}
+	return freq
+}
+
+var bigOne = big.NewInt(1)
+
+func (freq *Frequencies) add(indices []int) {
+	freq.Total.Add(freq.Total, bigOne)
+	for _, idx := range indices {
+		freq.Counts[idx].Add(freq.Counts[idx], bigOne)
+	}
+}
+
+// countFrequencies computes the exact frequencies of a family without constraints.  a subset of size k contains every
+// required index plus k-r of the f free indices, so there are C(f, k-r) of them, and a particular free index is in
+// C(f-1, k-r-1) of those
+func (fam *family) countFrequencies() *Frequencies {
+	freq := newFrequencies(fam.lenItems)
+	if fam.empty {
+		return freq
+	}
+
+	r, f := fam.numRequired, fam.numFree
+	withFree := new(big.Int)
+
+	lo, hi := fam.sizeRange()
+	for k := lo; k <= hi; k++ {
+		freq.Total.Add(freq.Total, binomial(f, k-r))
+		withFree.Add(withFree, binomial(f-1, k-r-1))
+	}
+
+	for idx, m := range fam.members {
+		switch m {
+		case required:
+			freq.Counts[idx].Set(freq.Total)
+		case free:
+			freq.Counts[idx].Set(withFree)
+		}
+	}
+	return freq
+}
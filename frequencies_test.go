@@ -0,0 +1,94 @@
+package powerset
+
+import (
+	"math/big"
+	"testing"
+)
+
+func checkFrequencies(t *testing.T, freq *Frequencies, total int64, counts []int64) {
+	if freq.Total.Cmp(big.NewInt(total)) != 0 {
+		t.Fatalf("total %v != %v", freq.Total, total)
+	}
+	for i, count := range counts {
+		if freq.Counts[i].Cmp(big.NewInt(count)) != 0 {
+			t.Fatalf("index %d: count %v != %v", i, freq.Counts[i], count)
+		}
+	}
+}
+
+func TestElementFrequenciesExact(t *testing.T) {
+	freq, err := ElementFrequencies(Config{LenItems: 5, MinSize: 1, MaxSize: 3, Required: []int{0}, Forbidden: []int{4}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 0 is always in, 4 never is, and 1-3 fill out subsets of size 1 to 3: C(3,0) + C(3,1) + C(3,2) = 7
+	checkFrequencies(t, freq, 7, []int64{7, 3, 3, 3, 0})
+
+	p := freq.Probability(1)
+	if p.Cmp(big.NewRat(3, 7)) != 0 {
+		t.Fatalf("probability %v != 3/7", p)
+	}
+}
+
+func TestElementFrequenciesMatchesStream(t *testing.T) {
+	cfg := Config{LenItems: 7, MinSize: 2, MaxSize: 5, Required: []int{3}, Forbidden: []int{0, 6}}
+	exact, _ := ElementFrequencies(cfg)
+
+	out, _, _ := Family(cfg)
+	streamed := StreamFrequencies(cfg.LenItems, out)
+
+	if exact.Total.Cmp(streamed.Total) != 0 {
+		t.Fatalf("total %v != %v", exact.Total, streamed.Total)
+	}
+	for i := range exact.Counts {
+		if exact.Counts[i].Cmp(streamed.Counts[i]) != 0 {
+			t.Fatalf("index %d: count %v != %v", i, exact.Counts[i], streamed.Counts[i])
+		}
+	}
+}
+
+func TestElementFrequenciesConstrained(t *testing.T) {
+	onlyEven := Constraint{
+		Name: "only even",
+		Allow: func(indices []int) bool {
+			for _, idx := range indices {
+				if idx%2 != 0 {
+					return false
+				}
+			}
+			return true
+		},
+	}
+
+	freq, err := ElementFrequencies(Config{LenItems: 4, Constraints: []Constraint{onlyEven}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkFrequencies(t, freq, 4, []int64{2, 0, 2, 0})
+}
+
+func TestElementFrequenciesEmpty(t *testing.T) {
+	freq, _ := ElementFrequencies(Config{LenItems: 3, MinSize: 3, Forbidden: []int{1}})
+	checkFrequencies(t, freq, 0, []int64{0, 0, 0})
+
+	if freq.Probability(0).Sign() != 0 {
+		t.Fatalf("expected a zero probability for an empty family")
+	}
+}
+
+func TestElementFrequenciesShard(t *testing.T) {
+	freq, err := ElementFrequencies(Config{LenItems: 4, Shard: &ShardSpec{Count: 4, Index: 1}, Order: OrderGray})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	correct := []int64{0, 4, 2, 2}
+	for i, count := range freq.Counts {
+		if count.Int64() != correct[i] {
+			t.Fatalf("index %d:\n%v\n\n!=\n\n%v", i, count, correct[i])
+		}
+	}
+	if freq.Total.Int64() != 4 {
+		t.Fatalf("\n%v\n\n!=\n\n%v", freq.Total, 4)
+	}
+}
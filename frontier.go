@@ -0,0 +1,82 @@
+package powerset
+
+import "container/list"
+
+// FromFrontier resumes a Callback traversal of the powerset of lenItems items beneath each of the partial paths that
+// WithMaxDepth wrote to its frontier, which completes a split-then-conquer workflow: one process explores the top of
+// the tree and distributes the frontier, and workers explore the subtrees below it.  the callback isn't called again
+// for the frontier nodes themselves, since the first traversal already did, so the two traversals together call it
+// exactly as often as a single full traversal would.
+//
+// states gives the state each frontier node's children start from.  pass no states to start every subtree from nil,
+// a single state to share it between every subtree, or one state per partial path.  a callback that terminates above
+// a frontier node's depth abandons every later partial path below the node it terminated to, exactly as it would in
+// a full traversal, so partial paths should be given in the order the frontier produced them
+func FromFrontier(lenItems int, partials []Path, cb NodeCallback, states ...interface{}) <-chan interface{} {
+	if len(states) > 1 && len(states) != len(partials) {
+		panic("powerset: FromFrontier needs no states, one state, or one state per partial path")
+	}
+
+	out := make(chan interface{})
+	wrappedCb := func(indices *list.List, isLeaf bool, state interface{}) (bool, int, interface{}) {
+		return cb(llToPath(indices), isLeaf, state, out)
+	}
+	o := buildOptions(nil)
+
+	go func() {
+		defer close(out)
+
+		// the path nodes of the subtree we've abandoned, or nil if we haven't abandoned one
+		var abandoned Path
+
+		for i, partial := range partials {
+			if abandoned != nil && hasPrefix(partial, abandoned) {
+				continue
+			}
+
+			var state interface{}
+			switch len(states) {
+			case 0:
+			case 1:
+				state = states[0]
+			default:
+				state = states[i]
+			}
+
+			// rebuild the traversal's linked lists, whose fronts are the deepest nodes, just like Path
+			path := list.New()
+			indices := list.New()
+			for j := len(partial) - 1; j >= 0; j-- {
+				node := partial[j]
+				path.PushFront(node)
+				if node.Included {
+					indices.PushFront(node.Index)
+				}
+			}
+
+			depth := len(partial)
+			if depth >= lenItems {
+				continue
+			}
+
+			stop, stopNode := powerSetChildren(depth, lenItems, indices, wrappedCb, path, state, o)
+			if stop && stopNode < depth {
+				if stopNode < 0 {
+					return
+				}
+				// the node at depth stopNode carries on with its other children, so the subtree we terminated from
+				// is the one rooted at depth stopNode+1
+				abandoned = partial[len(partial)-stopNode-1:]
+			}
+		}
+	}()
+	return out
+}
+
+// hasPrefix reports whether path passes through every node of prefix, which is a path to one of its ancestors
+func hasPrefix(path Path, prefix Path) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	return ValidatePath(path[len(path)-len(prefix):], prefix)
+}
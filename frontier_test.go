@@ -0,0 +1,94 @@
+package powerset
+
+import "testing"
+
+// splits a traversal at depth 2, then finishes it with FromFrontier, and compares the nodes visited against a single
+// full traversal
+func TestFromFrontier(t *testing.T) {
+	record := func(visited *[]string) NodeCallback {
+		return func(path Path, isLeaf bool, rawState interface{}, out chan<- interface{}) (bool, int, interface{}) {
+			state := rawState.(string)
+			if len(path) > 0 {
+				state = stringState(state, path[0])
+			}
+			*visited = append(*visited, state)
+			return false, 0, state
+		}
+	}
+
+	full := []string{}
+	for range Callback(4, record(&full), "") {
+	}
+
+	split := []string{}
+	frontier := make(chan Path, 16)
+	for range Callback(4, record(&split), "", WithMaxDepth(2, frontier)) {
+	}
+
+	partials := []Path{}
+	states := []interface{}{}
+	for partial := range frontier {
+		partials = append(partials, partial)
+		state := ""
+		for i := len(partial) - 1; i >= 0; i-- {
+			state = stringState(state, partial[i])
+		}
+		states = append(states, state)
+	}
+	for range FromFrontier(4, partials, record(&split), states...) {
+	}
+
+	if len(split) != len(full) {
+		t.Fatalf("split traversal visited %d nodes, full traversal visited %d", len(split), len(full))
+	}
+	seen := map[string]bool{}
+	for _, state := range split {
+		seen[state] = true
+	}
+	for _, state := range full {
+		if !seen[state] {
+			t.Fatalf("split traversal never visited %q", state)
+		}
+	}
+}
+
+func TestFromFrontierTerminate(t *testing.T) {
+	partials := []Path{
+		{{1, false}, {0, false}},
+		{{1, true}, {0, false}},
+		{{1, false}, {0, true}},
+		{{1, true}, {0, true}},
+	}
+
+	// terminating to the root from the first subtree abandons the rest of the root's left subtree, but not its right
+	leaves := 0
+	visit := func(path Path, isLeaf bool, state interface{}, out chan<- interface{}) (bool, int, interface{}) {
+		if isLeaf {
+			leaves++
+			if ValidatePath(path, Path{{2, false}, {1, false}, {0, false}}) {
+				return true, 0, nil
+			}
+		}
+		return false, 0, nil
+	}
+	for range FromFrontier(3, partials, visit) {
+	}
+	if leaves != 5 {
+		t.Fatalf("expected 5 leaves, got %d", leaves)
+	}
+
+	// terminating to before the root ends everything
+	leaves = 0
+	stopAll := func(path Path, isLeaf bool, state interface{}, out chan<- interface{}) (bool, int, interface{}) {
+		if isLeaf {
+			leaves++
+			return true, -1, nil
+		}
+		return false, 0, nil
+	}
+	for range FromFrontier(3, partials, stopAll) {
+	}
+	if leaves != 1 {
+		t.Fatalf("expected 1 leaf, got %d", leaves)
+	}
+}
@@ -0,0 +1,58 @@
+package powerset
+
+import (
+	"sort"
+	"sync"
+)
+
+// GrayStep is a subset in the reflected Gray code order, with the single index that was flipped to reach it from the
+// previous one.  the first subset, which is empty, has no previous one, so its Flipped is -1
+type GrayStep struct {
+	Subset  []int
+	Flipped int
+	Added   bool
+}
+
+// GrayCode generates the powerset of lenItems items in the reflected Gray code order, where each subset differs from
+// the previous one by exactly one index.  each subset is a new sorted slice of its indices, and comes with the index
+// that flipped, so consumers can keep sums, hashes or caches up to date with an O(1) update per subset while still
+// seeing every subset in full.  stop works the same way as for VariableSize
+func GrayCode(lenItems int) (<-chan GrayStep, func()) {
+	out := make(chan GrayStep)
+	stopIn := make(chan bool)
+
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		defer close(out)
+		defer wg.Done()
+
+		send := func(step GrayStep) bool {
+			select {
+			case <-stopIn:
+				return false
+			case out <- step:
+				return true
+			}
+		}
+		indices := []int{}
+		if !send(GrayStep{Subset: []int{}, Flipped: -1}) {
+			return
+		}
+
+		grayFlips(lenItems, func(idx int) bool {
+			pos := sort.SearchInts(indices, idx)
+			added := pos == len(indices) || indices[pos] != idx
+			if added {
+				indices = append(indices, 0)
+				copy(indices[pos+1:], indices[pos:])
+				indices[pos] = idx
+			} else {
+				indices = append(indices[:pos], indices[pos+1:]...)
+			}
+			return send(GrayStep{Subset: append([]int{}, indices...), Flipped: idx, Added: added})
+		})
+	}()
+
+	return out, makeStopper(stopIn, wg)
+}
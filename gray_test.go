@@ -0,0 +1,66 @@
+package powerset
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestGrayCode(t *testing.T) {
+	out, _ := GrayCode(4)
+	seen := map[uint64]bool{}
+	var prev []int
+	for step := range out {
+		if prev == nil {
+			if len(step.Subset) != 0 || step.Flipped != -1 {
+				t.Fatalf("expected to start from the empty subset, got %v", step)
+			}
+		} else {
+			correct := append([]int{}, prev...)
+			if step.Added {
+				correct = append(correct, step.Flipped)
+			} else {
+				correct = removeIndex(correct, step.Flipped)
+			}
+			sort.Ints(correct)
+			if !reflect.DeepEqual(correct, step.Subset) {
+				t.Fatalf("%v flipped by %d isn't %v", prev, step.Flipped, step.Subset)
+			}
+		}
+		seen[HashSubset(step.Subset)] = true
+		prev = step.Subset
+	}
+	if len(seen) != 16 {
+		t.Fatalf("expected 16 distinct subsets, got %d", len(seen))
+	}
+}
+
+// the flips are the same as the ones Deltas streams
+func TestGrayCodeMatchesDeltas(t *testing.T) {
+	out, _ := GrayCode(5)
+	deltas, _, _ := Deltas(5, OrderGray)
+	<-out
+	for step := range out {
+		delta := <-deltas
+		if step.Flipped != delta.Index || step.Added != delta.Added {
+			t.Fatalf("\n%v\n\n!=\n\n%v", step, delta)
+		}
+	}
+}
+
+func TestGrayCodeStop(t *testing.T) {
+	out, stop := GrayCode(40)
+	<-out
+	<-out
+	stop()
+}
+
+func removeIndex(subset []int, idx int) []int {
+	kept := []int{}
+	for _, i := range subset {
+		if i != idx {
+			kept = append(kept, i)
+		}
+	}
+	return kept
+}
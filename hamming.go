@@ -0,0 +1,71 @@
+package powerset
+
+import "sync"
+
+// HammingBall generates every subset of lenItems items that differs from center by at most radius indices.  center is a
+// slice of the included indices, and each subset on the output channel is a sorted slice of included indices, starting
+// with center itself.  the ball is the neighborhood that local search moves through, so flipping up to radius indices
+// of a subset is the same as walking this ball.  the ball is empty, and the channel is closed without sending anything,
+// when the radius is negative or center has an index out of range
+func HammingBall(lenItems int, center []int, radius int) (<-chan []int, func()) {
+	out := make(chan []int)
+	stopIn := make(chan bool)
+
+	valid := radius >= 0
+	inCenter := make([]bool, lenItems)
+	for _, idx := range center {
+		if idx < 0 || idx >= lenItems {
+			valid = false
+			break
+		}
+		inCenter[idx] = true
+	}
+
+	// the ball is the family of "flip sets" with at most radius indices, each applied to the center
+	flips := &family{
+		lenItems: lenItems,
+		maxSize:  radius,
+		members:  make([]membership, lenItems),
+		numFree:  lenItems,
+	}
+	if flips.maxSize > lenItems {
+		flips.maxSize = lenItems
+	}
+	flips.countAhead()
+
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		defer close(out)
+		defer wg.Done()
+		if !valid {
+			return
+		}
+
+		flipped := make([]bool, lenItems)
+		flips.walk(func(flipIndices []int) bool {
+			for _, idx := range flipIndices {
+				flipped[idx] = true
+			}
+			subset := []int{}
+			for idx := 0; idx < lenItems; idx++ {
+				if inCenter[idx] != flipped[idx] {
+					subset = append(subset, idx)
+				}
+			}
+			for _, idx := range flipIndices {
+				flipped[idx] = false
+			}
+
+			select {
+			case <-stopIn:
+				return false
+			case out <- subset:
+				return true
+			}
+		}, nil)
+	}()
+
+	stop := makeStopper(stopIn, wg)
+	return out, stop
+}
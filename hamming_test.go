@@ -0,0 +1,57 @@
+package powerset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHammingBall(t *testing.T) {
+	out, _ := HammingBall(3, []int{1}, 1)
+	correct := [][]int{
+		{1},
+		{1, 2},
+		{},
+		{0, 1},
+	}
+
+	allValues := [][]int{}
+	for indices := range out {
+		allValues = append(allValues, indices)
+	}
+	if !reflect.DeepEqual(correct, allValues) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", allValues, correct)
+	}
+}
+
+func TestHammingBallSizes(t *testing.T) {
+	// a ball of radius r around any center of 5 items holds sum(C(5, i)) for i <= r subsets
+	sizes := []int{1, 6, 16, 26, 31, 32, 32}
+	for radius, size := range sizes {
+		out, _ := HammingBall(5, []int{0, 3}, radius)
+		count := 0
+		for range out {
+			count++
+		}
+		if count != size {
+			t.Fatalf("radius %d: %d subsets != %d", radius, count, size)
+		}
+	}
+}
+
+func TestHammingBallStop(t *testing.T) {
+	out, stop := HammingBall(10, nil, 3)
+	<-out
+	stop()
+}
+
+func TestHammingBallEmpty(t *testing.T) {
+	for _, tc := range []struct {
+		center []int
+		radius int
+	}{{[]int{1}, -1}, {[]int{5}, 1}, {[]int{-1}, 1}} {
+		out, _ := HammingBall(5, tc.center, tc.radius)
+		for indices := range out {
+			t.Fatalf("%v radius %d: expected an empty ball, got %v", tc.center, tc.radius, indices)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package powerset
+
+// mix64 is the splitmix64 finalizer, which scrambles its input so that nearby values hash far apart
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// HashSubset returns a deterministic 64 bit fingerprint of a subset of indices.  the hash doesn't depend on the order
+// of the indices, so []int{2, 0} and []int{0, 2} hash the same, and it is stable across runs and machines.  it's meant
+// for deduplicating and comparing results, not for security.  the subset must not contain duplicate indices
+func HashSubset(subset []int) uint64 {
+	// adding the scrambled indices together is what makes the hash order insensitive
+	var sum uint64
+	for _, idx := range subset {
+		sum += mix64(uint64(idx) + 0x9e3779b97f4a7c15)
+	}
+	return mix64(sum ^ uint64(len(subset)))
+}
+
+// FamilyHasher incrementally fingerprints a family of subsets.  like HashSubset, the result doesn't depend on the
+// order the subsets are added in, so two runs that emit the same family in different orders produce the same hash.  the
+// zero value is ready to use
+type FamilyHasher struct {
+	sum   uint64
+	count uint64
+}
+
+// Add adds a subset to the family
+func (h *FamilyHasher) Add(subset []int) {
+	h.sum += mix64(HashSubset(subset))
+	h.count++
+}
+
+// Len returns how many subsets have been added
+func (h *FamilyHasher) Len() uint64 {
+	return h.count
+}
+
+// Sum64 returns the fingerprint of every subset added so far
+func (h *FamilyHasher) Sum64() uint64 {
+	return mix64(h.sum ^ mix64(h.count))
+}
+
+// HashFamily fingerprints every subset received on in, until in is closed.  see FamilyHasher
+func HashFamily(in <-chan []int) uint64 {
+	h := FamilyHasher{}
+	for subset := range in {
+		h.Add(subset)
+	}
+	return h.Sum64()
+}
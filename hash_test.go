@@ -0,0 +1,55 @@
+package powerset
+
+import "testing"
+
+func TestHashSubsetOrderInsensitive(t *testing.T) {
+	if HashSubset([]int{3, 0, 7}) != HashSubset([]int{7, 3, 0}) {
+		t.Fatalf("hash depends on order")
+	}
+	if HashSubset([]int{}) == HashSubset([]int{0}) {
+		t.Fatalf("empty set collides with {0}")
+	}
+}
+
+func TestHashSubsetDistinct(t *testing.T) {
+	// every subset of 12 items should get its own hash
+	seen := map[uint64][]int{}
+	out, _ := VariableSize(12)
+	for indices := range out {
+		h := HashSubset(indices)
+		if other, ok := seen[h]; ok {
+			t.Fatalf("%v and %v collide", indices, other)
+		}
+		seen[h] = indices
+	}
+}
+
+func TestHashFamily(t *testing.T) {
+	cfg := Config{LenItems: 6, MinSize: 2, MaxSize: 4}
+
+	out, _, _ := Family(cfg)
+	forward := HashFamily(out)
+
+	// the same family, fed in reverse
+	out, _, _ = Family(cfg)
+	all := [][]int{}
+	for indices := range out {
+		all = append(all, indices)
+	}
+	h := FamilyHasher{}
+	for i := len(all) - 1; i >= 0; i-- {
+		h.Add(all[i])
+	}
+
+	if h.Sum64() != forward {
+		t.Fatalf("family hash depends on order")
+	}
+	if h.Len() != uint64(len(all)) {
+		t.Fatalf("len %d != %d", h.Len(), len(all))
+	}
+
+	out, _, _ = Family(Config{LenItems: 6, MinSize: 2, MaxSize: 3})
+	if HashFamily(out) == forward {
+		t.Fatalf("different families hash the same")
+	}
+}
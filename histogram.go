@@ -0,0 +1,108 @@
+package powerset
+
+import (
+	"math/bits"
+	"sync/atomic"
+	"time"
+)
+
+// Histogram is a distribution of durations, in buckets whose width grows with the duration, like an HDR histogram, so
+// nanosecond and second latencies are both kept to within about 6% without a fixed range
+type Histogram struct {
+	counts []uint64
+	total  uint64
+	sum    time.Duration
+}
+
+// each power of two is split into 2^histogramSubBits buckets
+const (
+	histogramSubBits  = 4
+	histogramSubCount = 1 << histogramSubBits
+	histogramBuckets  = (64 - histogramSubBits + 1) * histogramSubCount
+)
+
+// histogramBucket returns the bucket of a value.  values below histogramSubCount have a bucket each, and above that, a
+// value whose highest set bit is msb falls into one of the histogramSubCount buckets of width 2^(msb-histogramSubBits)
+func histogramBucket(v uint64) int {
+	msb := bits.Len64(v) - 1
+	if msb < histogramSubBits {
+		return int(v)
+	}
+	shift := msb - histogramSubBits
+	return (shift+1)*histogramSubCount + int(v>>shift) - histogramSubCount
+}
+
+// histogramUpper returns the largest value in a bucket
+func histogramUpper(bucket int) uint64 {
+	if bucket < histogramSubCount {
+		return uint64(bucket)
+	}
+	shift := bucket/histogramSubCount - 1
+	lowest := uint64(bucket%histogramSubCount+histogramSubCount) << shift
+	return lowest + (1 << shift) - 1
+}
+
+// Count returns the number of durations recorded
+func (h Histogram) Count() uint64 {
+	return h.total
+}
+
+// Mean returns the average of the durations recorded, or zero if there are none
+func (h Histogram) Mean() time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	return h.sum / time.Duration(h.total)
+}
+
+// Quantile returns the duration that a fraction q of the recorded durations are at most, rounded up to the end of its
+// bucket, so Quantile(0.99) is the 99th percentile.  it's zero if nothing was recorded
+func (h Histogram) Quantile(q float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	rank := uint64(q * float64(h.total))
+	if rank >= h.total {
+		rank = h.total - 1
+	}
+	seen := uint64(0)
+	for bucket, count := range h.counts {
+		seen += count
+		if seen > rank {
+			return time.Duration(histogramUpper(bucket))
+		}
+	}
+	return time.Duration(histogramUpper(len(h.counts) - 1))
+}
+
+// latencyHistogram is the live, concurrently recordable version of Histogram
+type latencyHistogram struct {
+	counts [histogramBuckets]atomic.Uint64
+	total  atomic.Uint64
+	sum    atomic.Int64
+}
+
+func (h *latencyHistogram) record(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	h.counts[histogramBucket(uint64(d))].Add(1)
+	h.total.Add(1)
+	h.sum.Add(int64(d))
+}
+
+func (h *latencyHistogram) snapshot() Histogram {
+	snap := Histogram{counts: make([]uint64, 0, histogramBuckets)}
+	for i := range h.counts {
+		snap.counts = append(snap.counts, h.counts[i].Load())
+	}
+	// trim the empty buckets at the end, which is nearly all of them
+	for len(snap.counts) > 0 && snap.counts[len(snap.counts)-1] == 0 {
+		snap.counts = snap.counts[:len(snap.counts)-1]
+	}
+	for _, count := range snap.counts {
+		snap.total += count
+	}
+	snap.sum = time.Duration(h.sum.Load())
+	return snap
+}
@@ -0,0 +1,56 @@
+package powerset
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistogramBuckets(t *testing.T) {
+	prev := -1
+	for _, v := range []uint64{0, 1, 15, 16, 17, 31, 32, 33, 1000, 1 << 40, 1<<64 - 1} {
+		bucket := histogramBucket(v)
+		if bucket < prev {
+			t.Fatalf("bucket %d of %d is before %d", bucket, v, prev)
+		}
+		if bucket >= histogramBuckets {
+			t.Fatalf("bucket %d of %d is out of range", bucket, v)
+		}
+		upper := histogramUpper(bucket)
+		if upper < v {
+			t.Fatalf("bucket %d ends at %d, before %d", bucket, upper, v)
+		}
+		// the bucket is within 1/histogramSubCount of the value
+		if v >= histogramSubCount && float64(upper-v) > float64(v)/histogramSubCount {
+			t.Fatalf("bucket %d ending at %d is too wide for %d", bucket, upper, v)
+		}
+		prev = bucket
+	}
+}
+
+func TestHistogramQuantile(t *testing.T) {
+	h := &latencyHistogram{}
+	for i := 1; i <= 100; i++ {
+		h.record(time.Duration(i) * time.Microsecond)
+	}
+	snap := h.snapshot()
+
+	if snap.Count() != 100 {
+		t.Fatalf("expected 100 durations, got %d", snap.Count())
+	}
+	if mean := snap.Mean(); mean != 50500*time.Nanosecond {
+		t.Fatalf("mean %v != 50.5µs", mean)
+	}
+	for _, q := range []struct {
+		q       float64
+		correct time.Duration
+	}{{0, time.Microsecond}, {0.5, 51 * time.Microsecond}, {0.99, 100 * time.Microsecond}, {1, 100 * time.Microsecond}} {
+		got := snap.Quantile(q.q)
+		if got < q.correct || float64(got-q.correct) > float64(q.correct)/histogramSubCount {
+			t.Fatalf("quantile %v is %v, expected about %v", q.q, got, q.correct)
+		}
+	}
+
+	if (Histogram{}).Quantile(0.5) != 0 || (Histogram{}).Mean() != 0 {
+		t.Fatalf("expected an empty histogram to be zero")
+	}
+}
@@ -0,0 +1,41 @@
+package powerset
+
+import "container/list"
+
+// IndexedCallback is a NodeCallback that is also given the indices that the node's path includes, in ascending order
+type IndexedCallback func(path Path, isLeaf bool, included []int, state interface{}, out chan<- interface{}) (bool, int,
+	interface{})
+
+// CallbackWithIndices is Callback, passing the callback the indices included so far at every node, for callbacks that
+// only need to know which items are in, like placing queens, and would otherwise rebuild that from the path on every
+// call.  the engine keeps included up to date as it walks the tree, at the cost of a push and a pop per included index,
+// and reuses it between calls, so it must not be modified, and must be copied to be kept
+func CallbackWithIndices(lenItems int, cb IndexedCallback, state interface{}, opts ...Option) <-chan interface{} {
+	listCb := func(path *list.List, included []int, fixed []bool, isLeaf bool, state interface{},
+		out chan<- interface{}) (bool, int, interface{}) {
+
+		return cb(llToPath(path), isLeaf, included, state, out)
+	}
+	return callback(lenItems, listCb, state, opts...)
+}
+
+// FixedCallback is a NodeCallback that is also given the subset at each leaf in the representation of FixedSize, and
+// nil at internal nodes
+type FixedCallback func(path Path, isLeaf bool, fixed []bool, state interface{}, out chan<- interface{}) (bool, int,
+	interface{})
+
+// CallbackWithFixed is Callback, passing the callback the subset of every leaf as a slice of lenItems booleans, so a
+// callback that emits solutions doesn't have to rebuild it from the path.  the engine keeps the slice up to date as it
+// walks the tree, at the cost of one assignment per node, and reuses it between leaves, so it must not be modified, and
+// must be copied to be kept
+func CallbackWithFixed(lenItems int, cb FixedCallback, state interface{}, opts ...Option) <-chan interface{} {
+	listCb := func(path *list.List, included []int, fixed []bool, isLeaf bool, state interface{},
+		out chan<- interface{}) (bool, int, interface{}) {
+
+		if !isLeaf {
+			fixed = nil
+		}
+		return cb(llToPath(path), isLeaf, fixed, state, out)
+	}
+	return callback(lenItems, listCb, state, opts...)
+}
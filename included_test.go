@@ -0,0 +1,106 @@
+package powerset
+
+import (
+	"reflect"
+	"testing"
+)
+
+// the included indices agree with the path at every node
+func TestCallbackWithIndices(t *testing.T) {
+	leaves := [][]int{}
+	cb := func(path Path, isLeaf bool, included []int, state interface{}, out chan<- interface{}) (bool, int,
+		interface{}) {
+
+		correct := []int{}
+		for i := len(path) - 1; i >= 0; i-- {
+			if path[i].Included {
+				correct = append(correct, path[i].Index)
+			}
+		}
+		if !reflect.DeepEqual(included, correct) {
+			t.Fatalf("%v:\n%v\n\n!=\n\n%v", path, included, correct)
+		}
+		if isLeaf {
+			leaves = append(leaves, append([]int{}, included...))
+		}
+		// skip every subtree that includes both index 0 and index 1
+		if len(included) >= 2 && included[1] == 1 {
+			return true, len(path) - 1, nil
+		}
+		return false, 0, nil
+	}
+	for range CallbackWithIndices(3, cb, nil) {
+	}
+
+	correct := [][]int{{}, {2}, {1}, {1, 2}, {0}, {0, 2}}
+	if !reflect.DeepEqual(leaves, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", leaves, correct)
+	}
+}
+
+// the included indices still agree with the path after a stop that unwinds several levels at once
+func TestCallbackWithIndicesUnwind(t *testing.T) {
+	leaves := 0
+	cb := func(path Path, isLeaf bool, included []int, state interface{}, out chan<- interface{}) (bool, int,
+		interface{}) {
+
+		correct := []int{}
+		for i := len(path) - 1; i >= 0; i-- {
+			if path[i].Included {
+				correct = append(correct, path[i].Index)
+			}
+		}
+		if !reflect.DeepEqual(included, correct) {
+			t.Fatalf("%v:\n%v\n\n!=\n\n%v", path, included, correct)
+		}
+		if isLeaf {
+			leaves++
+			// a leaf that includes index 3 abandons everything below the decision of index 1
+			if len(included) > 0 && included[len(included)-1] == 3 {
+				return true, 1, nil
+			}
+		}
+		return false, 0, nil
+	}
+	for range CallbackWithIndices(5, cb, nil) {
+	}
+
+	// each of the 4 choices of indices 0 and 1 reaches a leaf that includes 3 on its third leaf
+	if leaves != 12 {
+		t.Fatalf("\n%v\n\n!=\n\n%v", leaves, 12)
+	}
+}
+
+// the leaves agree with FixedSize, even when the callbacks of internal nodes are cached
+func TestCallbackWithFixed(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithPrefixCache(16)}} {
+		leaves := [][]bool{}
+		cb := func(path Path, isLeaf bool, fixed []bool, state interface{}, out chan<- interface{}) (bool, int,
+			interface{}) {
+
+			if !isLeaf {
+				if fixed != nil {
+					t.Fatalf("internal node %v was given %v", path, fixed)
+				}
+				return false, 0, nil
+			}
+			leaves = append(leaves, append([]bool{}, fixed...))
+			return false, 0, nil
+		}
+		for range CallbackWithFixed(4, cb, nil, opts...) {
+		}
+		for range CallbackWithFixed(4, cb, nil, opts...) {
+		}
+
+		correct := [][]bool{}
+		for i := 0; i < 2; i++ {
+			out, _ := FixedSize(4)
+			for included := range out {
+				correct = append(correct, included)
+			}
+		}
+		if !reflect.DeepEqual(leaves, correct) {
+			t.Fatalf("\n%v\n\n!=\n\n%v", leaves, correct)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package powerset
+
+import (
+	"iter"
+	"sort"
+)
+
+// Incremental maintains a user defined state S across the powerset, by applying the single change between consecutive
+// subsets of the Gray code order instead of rebuilding the state for every subset.  Add returns the state with an index
+// added, and Remove returns it with an index removed.  S may be a value, or a pointer that Add and Remove update in
+// place
+type Incremental[S any] struct {
+	Add    func(state S, idx int) S
+	Remove func(state S, idx int) S
+}
+
+// All yields every subset of lenItems items in the Gray code order, as sorted included indices, paired with its state,
+// starting from the empty subset with the initial state.  each subset after the first costs a single call to Add or
+// Remove.  the pairs are yielded from the caller's goroutine, so a state that Add and Remove update in place is never
+// changed while the loop body is looking at it.  the slice of indices is reused between iterations, so it must be
+// copied if it is retained
+func (inc Incremental[S]) All(lenItems int, initial S) iter.Seq2[[]int, S] {
+	return func(yield func([]int, S) bool) {
+		state := initial
+		indices := make([]int, 0, lenItems)
+		if !yield(indices, state) {
+			return
+		}
+
+		in := make([]bool, lenItems)
+		grayFlips(lenItems, func(idx int) bool {
+			pos := sort.SearchInts(indices, idx)
+			in[idx] = !in[idx]
+			if in[idx] {
+				indices = append(indices, 0)
+				copy(indices[pos+1:], indices[pos:])
+				indices[pos] = idx
+				state = inc.Add(state, idx)
+			} else {
+				indices = append(indices[:pos], indices[pos+1:]...)
+				state = inc.Remove(state, idx)
+			}
+			return yield(indices, state)
+		})
+	}
+}
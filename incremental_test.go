@@ -0,0 +1,70 @@
+package powerset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIncremental(t *testing.T) {
+	weights := []int{3, 5, 7, 11, 13}
+	sum := Incremental[int]{
+		Add:    func(total int, idx int) int { return total + weights[idx] },
+		Remove: func(total int, idx int) int { return total - weights[idx] },
+	}
+
+	allValues := [][]int{}
+	for indices, state := range sum.All(len(weights), 0) {
+		total := 0
+		for _, idx := range indices {
+			total += weights[idx]
+		}
+		if state != total {
+			t.Fatalf("%v: state %d, expected %d", indices, state, total)
+		}
+		allValues = append(allValues, append([]int{}, indices...))
+	}
+
+	correct := collectFamily(t, Config{LenItems: len(weights)}, WithOrder(OrderGray))
+	if !reflect.DeepEqual(allValues, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", allValues, correct)
+	}
+}
+
+func TestIncrementalPointer(t *testing.T) {
+	calls := 0
+	counts := Incremental[*[]int]{
+		Add:    func(state *[]int, idx int) *[]int { calls++; (*state)[idx]++; return state },
+		Remove: func(state *[]int, idx int) *[]int { calls++; (*state)[idx]--; return state },
+	}
+
+	for indices, state := range counts.All(3, &[]int{0, 0, 0}) {
+		seen := []int{}
+		for idx, count := range *state {
+			if count == 1 {
+				seen = append(seen, idx)
+			}
+		}
+		if !reflect.DeepEqual(seen, indices) {
+			t.Fatalf("\n%v\n\n!=\n\n%v", seen, indices)
+		}
+	}
+	if calls != 7 {
+		t.Fatalf("made %d calls, expected one per subset after the first", calls)
+	}
+}
+
+func TestIncrementalBreak(t *testing.T) {
+	size := Incremental[int]{
+		Add:    func(n int, _ int) int { return n + 1 },
+		Remove: func(n int, _ int) int { return n - 1 },
+	}
+	seen := 0
+	for _, n := range size.All(80, 0) {
+		if seen++; seen == 5 {
+			break
+		}
+		if n > 2 {
+			t.Fatalf("size %d after %d subsets", n, seen)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package powerset
+
+import (
+	"fmt"
+	"sort"
+)
+
+// WithInterchangeable declares groups of indices whose items are identical, so a subset only matters by how many of
+// each group it includes, not which.  Start and Family then only emit the canonical member of each class, the one that
+// includes the lowest indices of every group, so a group of g items contributes g+1 choices instead of 2^g.  subtrees
+// that include an index of a group without the one before it are pruned, reported under the rule
+// "WithInterchangeable".  the family's other rules apply to the canonical members, so a required or forbidden index
+// inside a group pins the canonical member rather than a count.  an index can belong to at most one group
+func WithInterchangeable(groups [][]int) Option {
+	return func(o *options) {
+		o.interchangeable = groups
+	}
+}
+
+// the rule reported for subtrees skipped by WithInterchangeable
+const interchangeableRule = "WithInterchangeable"
+
+type interchange struct {
+	// prev[idx] is the index before idx in its group, or -1 if it's the first of its group or in no group
+	prev []int
+}
+
+func newInterchange(lenItems int, groups [][]int) (*interchange, error) {
+	ic := &interchange{prev: make([]int, lenItems)}
+	for idx := range ic.prev {
+		ic.prev[idx] = -1
+	}
+
+	grouped := make([]bool, lenItems)
+	for _, group := range groups {
+		sorted := append([]int{}, group...)
+		sort.Ints(sorted)
+		for i, idx := range sorted {
+			if idx < 0 || idx >= lenItems {
+				return nil, fmt.Errorf("powerset: interchangeable index %d is out of range", idx)
+			}
+			if grouped[idx] {
+				return nil, fmt.Errorf("powerset: index %d is in more than one interchangeable group", idx)
+			}
+			grouped[idx] = true
+			if i > 0 {
+				ic.prev[idx] = sorted[i-1]
+			}
+		}
+	}
+	return ic, nil
+}
+
+// canonical reports whether the last decision, of index next-1, keeps included canonical.  every decision before it
+// was checked when it was made
+func (ic *interchange) canonical(included []int, next int) bool {
+	d := next - 1
+	if d < 0 || ic.prev[d] < 0 || len(included) == 0 || included[len(included)-1] != d {
+		return true
+	}
+	p := ic.prev[d]
+	i := sort.SearchInts(included, p)
+	return i < len(included) && included[i] == p
+}
+
+// constraint checks that a whole subset is canonical, at the leaves of the walk
+func (ic *interchange) constraint() Constraint {
+	return Constraint{
+		Name: interchangeableRule,
+		Allow: func(indices []int) bool {
+			for _, idx := range indices {
+				if p := ic.prev[idx]; p >= 0 {
+					i := sort.SearchInts(indices, p)
+					if i == len(indices) || indices[i] != p {
+						return false
+					}
+				}
+			}
+			return true
+		},
+	}
+}
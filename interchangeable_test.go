@@ -0,0 +1,58 @@
+package powerset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithInterchangeable(t *testing.T) {
+	// items 0, 2 and 3 are identical, as are 1 and 4
+	groups := [][]int{{3, 0, 2}, {1, 4}}
+	subsets, stats := collectStart(t, Config{LenItems: 5}, WithInterchangeable(groups))
+	if len(subsets) != 4*3 {
+		t.Fatalf("expected 12 classes, got %d: %v", len(subsets), subsets)
+	}
+	for _, subset := range subsets {
+		in := map[int]bool{}
+		for _, idx := range subset {
+			in[idx] = true
+		}
+		if (in[2] && !in[0]) || (in[3] && !in[2]) || (in[4] && !in[1]) {
+			t.Fatalf("%v isn't canonical", subset)
+		}
+	}
+	if stats.PrunedBy[interchangeableRule] == 0 {
+		t.Fatalf("expected subtrees to be pruned, got %v", stats.PrunedBy)
+	}
+}
+
+func TestWithInterchangeableSizes(t *testing.T) {
+	subsets, _ := collectStart(t, Config{LenItems: 4, MinSize: 2, MaxSize: 2}, WithInterchangeable([][]int{{0, 1, 2}}))
+	correct := [][]int{{0, 3}, {0, 1}}
+	if !reflect.DeepEqual(subsets, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", subsets, correct)
+	}
+}
+
+// a group of g items is walked in time linear in g, not exponential
+func TestWithInterchangeablePrunes(t *testing.T) {
+	group := make([]int, 40)
+	for i := range group {
+		group[i] = i
+	}
+	subsets, stats := collectStart(t, Config{LenItems: 40}, WithInterchangeable([][]int{group}))
+	if len(subsets) != 41 {
+		t.Fatalf("expected 41 subsets, got %d", len(subsets))
+	}
+	if stats.Nodes > 40*41 {
+		t.Fatalf("expected a small tree, visited %d nodes", stats.Nodes)
+	}
+}
+
+func TestWithInterchangeableInvalid(t *testing.T) {
+	for _, groups := range [][][]int{{{0, 5}}, {{0, 1}, {1, 2}}} {
+		if _, _, err := Start(Config{LenItems: 3}, WithInterchangeable(groups)); err == nil {
+			t.Fatalf("expected an error for %v", groups)
+		}
+	}
+}
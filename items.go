@@ -0,0 +1,164 @@
+package powerset
+
+import (
+	"fmt"
+	"sync"
+)
+
+// UnknownItemError is returned when a name isn't registered with an Items registry
+type UnknownItemError struct {
+	Name string
+}
+
+func (err *UnknownItemError) Error() string {
+	return fmt.Sprintf("powerset: unknown item %q", err.Name)
+}
+
+// Items is a registry of named items, which takes care of the index bookkeeping between your data and the generators.
+// each item is registered once and given the next index, and an item's index never changes, so it's a stable ID for
+// the lifetime of the registry.  configurations, constraints and outputs can then all be written in terms of names
+type Items struct {
+	names []string
+	index map[string]int
+}
+
+// NewItems creates a registry holding the given names, in order, so the first name is index 0
+func NewItems(names ...string) (*Items, error) {
+	it := &Items{index: map[string]int{}}
+	for _, name := range names {
+		if _, err := it.Add(name); err != nil {
+			return nil, err
+		}
+	}
+	return it, nil
+}
+
+// Add registers a new item and returns its index.  names must be unique
+func (it *Items) Add(name string) (int, error) {
+	if _, ok := it.index[name]; ok {
+		return 0, fmt.Errorf("powerset: item %q is already registered", name)
+	}
+	it.index[name] = len(it.names)
+	it.names = append(it.names, name)
+	return len(it.names) - 1, nil
+}
+
+// Len returns the number of registered items, which is the LenItems of every Config built from the registry
+func (it *Items) Len() int {
+	return len(it.names)
+}
+
+// Name returns the name of the item at index idx
+func (it *Items) Name(idx int) string {
+	return it.names[idx]
+}
+
+// Index returns the index of the named item
+func (it *Items) Index(name string) (int, error) {
+	idx, ok := it.index[name]
+	if !ok {
+		return 0, &UnknownItemError{Name: name}
+	}
+	return idx, nil
+}
+
+// Indices returns the indices of the named items, in the same order
+func (it *Items) Indices(names ...string) ([]int, error) {
+	indices := make([]int, len(names))
+	for i, name := range names {
+		idx, err := it.Index(name)
+		if err != nil {
+			return nil, err
+		}
+		indices[i] = idx
+	}
+	return indices, nil
+}
+
+// Labels returns the names of the items at the given indices, in the same order.  it turns a subset from any generator
+// back into names
+func (it *Items) Labels(indices []int) []string {
+	labels := make([]string, len(indices))
+	for i, idx := range indices {
+		labels[i] = it.names[idx]
+	}
+	return labels
+}
+
+// Constraint creates a Constraint whose predicate receives the names of the included items instead of their indices
+func (it *Items) Constraint(name string, allow func(names []string) bool) Constraint {
+	return Constraint{
+		Name: name,
+		Allow: func(indices []int) bool {
+			return allow(it.Labels(indices))
+		},
+	}
+}
+
+// ItemsConfig is a Config written in terms of item names
+type ItemsConfig struct {
+	MinSize     int
+	MaxSize     int
+	Required    []string
+	Forbidden   []string
+	Constraints []Constraint
+}
+
+// Config converts an ItemsConfig to a Config over every registered item, returning an *UnknownItemError if it names an
+// item that isn't registered
+func (it *Items) Config(ic ItemsConfig) (Config, error) {
+	required, err := it.Indices(ic.Required...)
+	if err != nil {
+		return Config{}, err
+	}
+	forbidden, err := it.Indices(ic.Forbidden...)
+	if err != nil {
+		return Config{}, err
+	}
+
+	return Config{
+		LenItems:    it.Len(),
+		MinSize:     ic.MinSize,
+		MaxSize:     ic.MaxSize,
+		Required:    required,
+		Forbidden:   forbidden,
+		Constraints: ic.Constraints,
+	}, nil
+}
+
+// Family generates the family described by ic like the package level Family, but emits the names of the included items
+// in index order
+func (it *Items) Family(ic ItemsConfig, opts ...Option) (<-chan []string, func(), error) {
+	cfg, err := it.Config(ic)
+	if err != nil {
+		return nil, nil, err
+	}
+	in, stopIn, err := Family(cfg, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := make(chan []string)
+	done := make(chan bool)
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		defer close(out)
+		defer wg.Done()
+
+		for indices := range in {
+			select {
+			case <-done:
+				return
+			case out <- it.Labels(indices):
+			}
+		}
+	}()
+
+	stop := func() {
+		stopIn()
+		close(done)
+		wg.Wait()
+	}
+	return out, stop, nil
+}
@@ -0,0 +1,80 @@
+package powerset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestItems(t *testing.T) {
+	it, err := NewItems("cheese", "ham", "olives")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	idx, _ := it.Add("peppers")
+	if idx != 3 || it.Len() != 4 {
+		t.Fatalf("expected peppers to get index 3, got %d", idx)
+	}
+	if _, err := it.Add("ham"); err == nil {
+		t.Fatalf("expected registering ham twice to fail")
+	}
+
+	indices, err := it.Indices("olives", "cheese")
+	if err != nil || !reflect.DeepEqual(indices, []int{2, 0}) {
+		t.Fatalf("unexpected indices %v, %v", indices, err)
+	}
+	if labels := it.Labels([]int{1, 3}); !reflect.DeepEqual(labels, []string{"ham", "peppers"}) {
+		t.Fatalf("unexpected labels %v", labels)
+	}
+
+	_, err = it.Index("anchovies")
+	if uerr, ok := err.(*UnknownItemError); !ok || uerr.Name != "anchovies" {
+		t.Fatalf("expected an *UnknownItemError, got %v", err)
+	}
+}
+
+func TestItemsFamily(t *testing.T) {
+	it, _ := NewItems("cheese", "ham", "olives", "pineapple")
+	noPineappleWithHam := it.Constraint("no pineapple with ham", func(names []string) bool {
+		has := map[string]bool{}
+		for _, name := range names {
+			has[name] = true
+		}
+		return !(has["ham"] && has["pineapple"])
+	})
+
+	out, _, err := it.Family(ItemsConfig{
+		MinSize:     3,
+		Required:    []string{"cheese"},
+		Constraints: []Constraint{noPineappleWithHam},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	allValues := [][]string{}
+	for names := range out {
+		allValues = append(allValues, names)
+	}
+	correct := [][]string{
+		{"cheese", "olives", "pineapple"},
+		{"cheese", "ham", "olives"},
+	}
+	if !reflect.DeepEqual(correct, allValues) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", allValues, correct)
+	}
+}
+
+func TestItemsUnknownName(t *testing.T) {
+	it, _ := NewItems("a", "b")
+	if _, _, err := it.Family(ItemsConfig{Forbidden: []string{"c"}}); err == nil {
+		t.Fatalf("expected an unknown forbidden item to be an error")
+	}
+}
+
+func TestItemsFamilyStop(t *testing.T) {
+	it, _ := NewItems("a", "b", "c", "d", "e", "f", "g", "h", "i", "j")
+	out, stop, _ := it.Family(ItemsConfig{})
+	<-out
+	stop()
+}
@@ -0,0 +1,62 @@
+package powerset
+
+// Iterator steps through the members of a family on demand, in the order of Family, without a producer goroutine, so
+// a family can be consumed from inside an existing state machine or event loop.  it's built on NextInFamily, so each
+// step jumps straight to the next subset that satisfies the size bounds and the required and forbidden indices, and
+// Constraints are then checked on those in turn.  an Iterator isn't safe for concurrent use
+type Iterator struct {
+	fam *family
+	in  []bool
+
+	started bool
+	done    bool
+}
+
+// NewIterator creates an Iterator over the family described by cfg, whose Order must be OrderCanonical
+func NewIterator(cfg Config) (*Iterator, error) {
+	if err := cfg.canonicalOnly("NewIterator"); err != nil {
+		return nil, err
+	}
+	fam, err := cfg.compile()
+	if err != nil {
+		return nil, err
+	}
+	return &Iterator{fam: fam, in: make([]bool, fam.lenItems), done: fam.empty}, nil
+}
+
+// Next returns the next member of the family as its sorted included indices, or false once there are no more.  each
+// slice is newly allocated, so it can be kept
+func (it *Iterator) Next() ([]int, bool) {
+	if it.done {
+		return nil, false
+	}
+
+	var subset []int
+	ok := true
+	if !it.started {
+		it.started = true
+		if it.in, ok = it.fam.complete(it.in, 0, 0); ok {
+			subset, ok = it.fam.nextMember(it.in, false)
+		}
+	} else {
+		subset, ok = it.fam.nextMember(it.in, true)
+	}
+	if !ok {
+		it.Close()
+		return nil, false
+	}
+
+	// nextMember overwrites its argument, so the position is rebuilt from the member
+	it.in = make([]bool, it.fam.lenItems)
+	for _, idx := range subset {
+		it.in[idx] = true
+	}
+	return subset, true
+}
+
+// Close ends the iteration early, after which Next always returns false.  an Iterator holds no goroutines or other
+// resources, so closing it is only needed to make later calls to Next return false
+func (it *Iterator) Close() {
+	it.done = true
+	it.in = nil
+}
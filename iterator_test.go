@@ -0,0 +1,72 @@
+package powerset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIterator(t *testing.T) {
+	items, _ := NewItems("a", "b", "c", "d", "e")
+	cfgs := []Config{
+		{LenItems: 0},
+		{LenItems: 4},
+		{LenItems: 5, MinSize: 2, MaxSize: 3, Required: []int{4}, Forbidden: []int{1}},
+		{LenItems: 5, Constraints: []Constraint{mustParse(t, "a -> (b <-> !c)", items)}},
+		{LenItems: 3, MinSize: 2, Forbidden: []int{0, 1}},
+	}
+	for _, cfg := range cfgs {
+		it, err := NewIterator(cfg)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got := [][]int{}
+		for subset, ok := it.Next(); ok; subset, ok = it.Next() {
+			got = append(got, subset)
+		}
+		if correct := collectFamily(t, cfg); !reflect.DeepEqual(got, correct) {
+			t.Fatalf("%+v: \n%v\n\n!=\n\n%v", cfg, got, correct)
+		}
+		if _, ok := it.Next(); ok {
+			t.Fatalf("expected the iterator to stay finished")
+		}
+	}
+}
+
+func TestIteratorClose(t *testing.T) {
+	it, _ := NewIterator(Config{LenItems: 50})
+	it.Next()
+	if subset, _ := it.Next(); !reflect.DeepEqual(subset, []int{49}) {
+		t.Fatalf("unexpected subset %v", subset)
+	}
+	it.Close()
+	if _, ok := it.Next(); ok {
+		t.Fatalf("expected nothing after Close")
+	}
+}
+
+func TestIteratorInvalid(t *testing.T) {
+	if _, err := NewIterator(Config{LenItems: 2, Required: []int{3}}); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestIteratorShardAndOrder(t *testing.T) {
+	cfg := Config{LenItems: 4, Shard: &ShardSpec{Count: 4, Index: 1}}
+	it, err := NewIterator(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	allValues := [][]int{}
+	for subset, ok := it.Next(); ok; subset, ok = it.Next() {
+		allValues = append(allValues, subset)
+	}
+	correct := [][]int{{1}, {1, 3}, {1, 2}, {1, 2, 3}}
+	if !reflect.DeepEqual(allValues, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", allValues, correct)
+	}
+
+	cfg.Order = OrderGray
+	if _, err := NewIterator(cfg); err == nil {
+		t.Fatalf("expected an error for OrderGray")
+	}
+}
@@ -0,0 +1,104 @@
+package powerset
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+)
+
+// WithJournal records the HashSubset of every subset a search delivers in the file at path, and skips the subsets
+// already recorded there, so a search that is re-run with more items or relaxed constraints only emits what previous
+// runs didn't.  the file is created if it doesn't exist, and is a plain sequence of little endian 64 bit hashes, so
+// journals can be concatenated.  a subset is recorded once it has been sent to the consumer, and writes are buffered
+// until the search ends, so a crash can lose the most recent records and repeat those subsets on the next run.
+// skipped subsets are counted as suppressed.  hashes can collide, so with around 2^32 subsets recorded a new subset
+// will occasionally be skipped as well.  a journal that can't be opened is an error from Start, and one that can't be
+// written to ends the search with ReasonJournal and its error in Control.Err
+func WithJournal(path string) Option {
+	return func(o *options) {
+		o.journalPath = path
+	}
+}
+
+// the size of one record of a journal
+const journalRecordSize = 8
+
+type journal struct {
+	f    *os.File
+	w    *bufio.Writer
+	seen map[uint64]bool
+
+	// the first error writing to the file, after which nothing more is written
+	err error
+}
+
+// openJournal reads every record of the journal at path and readies it for appending.  a partial record at the end,
+// left by a write that was interrupted, is truncated away
+func openJournal(path string) (*journal, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("powerset: can't open journal: %w", err)
+	}
+
+	j := &journal{f: f, seen: map[uint64]bool{}}
+	r := bufio.NewReader(f)
+	record := make([]byte, journalRecordSize)
+	var size int64
+	for {
+		if _, err := io.ReadFull(r, record); err != nil {
+			if err != io.EOF && err != io.ErrUnexpectedEOF {
+				f.Close()
+				return nil, fmt.Errorf("powerset: can't read journal: %w", err)
+			}
+			break
+		}
+		j.seen[binary.LittleEndian.Uint64(record)] = true
+		size += journalRecordSize
+	}
+
+	if err := f.Truncate(size); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("powerset: can't truncate journal: %w", err)
+	}
+	if _, err := f.Seek(size, io.SeekStart); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("powerset: can't seek journal: %w", err)
+	}
+	j.w = bufio.NewWriter(f)
+	return j, nil
+}
+
+// recorded reports whether a subset with this hash was recorded, by this run or an earlier one
+func (j *journal) recorded(hash uint64) bool {
+	return j.seen[hash]
+}
+
+// record appends a hash to the journal, returning false if the journal can't be written to
+func (j *journal) record(hash uint64) bool {
+	if j.err != nil {
+		return false
+	}
+	j.seen[hash] = true
+	var record [journalRecordSize]byte
+	binary.LittleEndian.PutUint64(record[:], hash)
+	if _, err := j.w.Write(record[:]); err != nil {
+		j.err = fmt.Errorf("powerset: can't write journal: %w", err)
+		return false
+	}
+	return true
+}
+
+// close flushes the journal and closes its file, returning the first error writing to it
+func (j *journal) close() error {
+	if j.err == nil {
+		if err := j.w.Flush(); err != nil {
+			j.err = fmt.Errorf("powerset: can't write journal: %w", err)
+		}
+	}
+	if err := j.f.Close(); err != nil && j.err == nil {
+		j.err = fmt.Errorf("powerset: can't close journal: %w", err)
+	}
+	return j.err
+}
@@ -0,0 +1,73 @@
+package powerset
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"testing"
+)
+
+// failJournal points the descriptor the search opened for the journal at path to /dev/full, so every write to it fails
+func failJournal(t *testing.T, path string) {
+	full, err := os.OpenFile("/dev/full", os.O_WRONLY, 0)
+	if err != nil {
+		t.Skipf("can't open /dev/full: %v", err)
+	}
+	defer full.Close()
+
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Skipf("can't list descriptors: %v", err)
+	}
+	for _, entry := range entries {
+		if target, _ := os.Readlink(filepath.Join("/proc/self/fd", entry.Name())); target == path {
+			fd, _ := strconv.Atoi(entry.Name())
+			if err := syscall.Dup3(int(full.Fd()), fd, 0); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			return
+		}
+	}
+	t.Fatalf("no descriptor for %s", path)
+}
+
+func TestWithJournalWriteFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+
+	out, ctl, err := Start(Config{LenItems: 10}, WithJournal(path))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	failJournal(t, path)
+	var got int
+	for range out {
+		got++
+	}
+	if got == 1<<10 {
+		t.Fatalf("expected the search to end early")
+	}
+	if ctl.Reason() != ReasonJournal || ctl.Err() == nil {
+		t.Fatalf("expected a journal error, got %v, %v", ctl.Reason(), ctl.Err())
+	}
+}
+
+func TestWithJournalFlushFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+
+	out, ctl, err := Start(Config{LenItems: 3}, WithJournal(path))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	failJournal(t, path)
+	var got int
+	for range out {
+		got++
+	}
+	if got != 8 {
+		t.Fatalf("\n%v\n\n!=\n\n%v", got, 8)
+	}
+	if ctl.Reason() != ReasonJournal || ctl.Err() == nil {
+		t.Fatalf("expected a journal error, got %v, %v", ctl.Reason(), ctl.Err())
+	}
+}
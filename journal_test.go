@@ -0,0 +1,84 @@
+package powerset
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWithJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+
+	first, stats := collectStart(t, Config{LenItems: 4, MaxSize: 2}, WithJournal(path))
+	if len(first) != 11 || stats.Suppressed != 0 {
+		t.Fatalf("expected 11 new subsets, got %v with %d suppressed", first, stats.Suppressed)
+	}
+
+	// the expanded search only emits what the first run didn't
+	second, stats := collectStart(t, Config{LenItems: 4}, WithJournal(path))
+	correct := [][]int{{1, 2, 3}, {0, 2, 3}, {0, 1, 3}, {0, 1, 2}, {0, 1, 2, 3}}
+	if !reflect.DeepEqual(second, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", second, correct)
+	}
+	if stats.Suppressed != 11 {
+		t.Fatalf("expected 11 suppressed subsets, got %d", stats.Suppressed)
+	}
+
+	third, _ := collectStart(t, Config{LenItems: 4}, WithJournal(path))
+	if len(third) != 0 {
+		t.Fatalf("expected nothing new, got %v", third)
+	}
+}
+
+func TestWithJournalStopped(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+
+	out, ctl, err := Start(Config{LenItems: 3}, WithJournal(path))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	seen := [][]int{<-out, <-out}
+	ctl.Stop()
+
+	rest, _ := collectStart(t, Config{LenItems: 3}, WithJournal(path))
+	if len(rest) != 8-len(seen) {
+		t.Fatalf("expected %d subsets, got %v", 8-len(seen), rest)
+	}
+	for _, subset := range rest {
+		for _, s := range seen {
+			if reflect.DeepEqual(subset, s) {
+				t.Fatalf("%v was emitted twice", subset)
+			}
+		}
+	}
+}
+
+func TestWithJournalPartialRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	collectStart(t, Config{LenItems: 2, MaxSize: 1}, WithJournal(path))
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	f.Write([]byte{1, 2, 3})
+	f.Close()
+
+	rest, _ := collectStart(t, Config{LenItems: 2}, WithJournal(path))
+	if !reflect.DeepEqual(rest, [][]int{{0, 1}}) {
+		t.Fatalf("unexpected subsets %v", rest)
+	}
+	info, _ := os.Stat(path)
+	if info.Size() != 4*journalRecordSize {
+		t.Fatalf("expected 4 whole records, got %d bytes", info.Size())
+	}
+}
+
+func TestWithJournalInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "journal")
+	if _, _, err := Start(Config{LenItems: 2}, WithJournal(path)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected a missing directory error, got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package powerset
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Label is a key=value pair, as attached to a Kubernetes object
+type Label struct {
+	Key   string
+	Value string
+}
+
+func (l Label) String() string {
+	return l.Key + "=" + l.Value
+}
+
+// Selector is an equality based label selector, which matches the objects that have every one of its labels
+type Selector []Label
+
+// String formats the selector the way kubectl's --selector flag takes it, like "app=web,tier=frontend".  the empty
+// selector, which matches everything, is the empty string
+func (sel Selector) String() string {
+	parts := make([]string, len(sel))
+	for i, l := range sel {
+		parts[i] = l.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+// SelectorConfig describes a family of selectors over a set of labels.  the size bounds count labels like a Config's
+// do, and a selector must have a label for every one of RequiredKeys, and none for any of ForbiddenKeys
+type SelectorConfig struct {
+	Labels        []Label
+	MinSize       int
+	MaxSize       int
+	RequiredKeys  []string
+	ForbiddenKeys []string
+}
+
+// Selectors generates every selector in the family described by sc, for building test matrices of selectors and the
+// policies that use them.  a selector never has two labels with the same key, since no object could match it.  the
+// labels of each selector are in the order of sc.Labels, and the selectors come out in the same order as Family.
+// Selectors understands the same options as Family
+func Selectors(sc SelectorConfig, opts ...Option) (<-chan Selector, func(), error) {
+	cfg := Config{LenItems: len(sc.Labels), MinSize: sc.MinSize, MaxSize: sc.MaxSize}
+
+	byKey := map[string][]int{}
+	keys := []string{}
+	seen := map[Label]bool{}
+	for idx, l := range sc.Labels {
+		if seen[l] {
+			return nil, nil, fmt.Errorf("powerset: label %v appears more than once", l)
+		}
+		seen[l] = true
+		if byKey[l.Key] == nil {
+			keys = append(keys, l.Key)
+		}
+		byKey[l.Key] = append(byKey[l.Key], idx)
+	}
+
+	for _, key := range keys {
+		indices := byKey[key]
+		clauses := []string{}
+		for i := range indices {
+			for j := i + 1; j < len(indices); j++ {
+				clauses = append(clauses, fmt.Sprintf("!(%d & %d)", indices[i], indices[j]))
+			}
+		}
+		if len(clauses) > 0 {
+			if err := addLabelConstraint(&cfg, "one value of "+key, strings.Join(clauses, " & ")); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	for _, key := range sc.RequiredKeys {
+		indices, ok := byKey[key]
+		if !ok {
+			return nil, nil, fmt.Errorf("powerset: required key %q has no labels", key)
+		}
+		options := make([]string, len(indices))
+		for i, idx := range indices {
+			options[i] = fmt.Sprint(idx)
+		}
+		if err := addLabelConstraint(&cfg, "required key "+key, strings.Join(options, " | ")); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, key := range sc.ForbiddenKeys {
+		indices, ok := byKey[key]
+		if !ok {
+			return nil, nil, fmt.Errorf("powerset: forbidden key %q has no labels", key)
+		}
+		cfg.Forbidden = append(cfg.Forbidden, indices...)
+	}
+
+	in, stopIn, err := Family(cfg, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := make(chan Selector)
+	done := make(chan bool)
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		defer close(out)
+		defer wg.Done()
+
+		for indices := range in {
+			sel := make(Selector, len(indices))
+			for i, idx := range indices {
+				sel[i] = sc.Labels[idx]
+			}
+			select {
+			case <-done:
+				return
+			case out <- sel:
+			}
+		}
+	}()
+
+	stop := func() {
+		stopIn()
+		close(done)
+		wg.Wait()
+	}
+	return out, stop, nil
+}
+
+// addLabelConstraint adds a parsed constraint over label indices to cfg
+func addLabelConstraint(cfg *Config, name string, expr string) error {
+	c, err := ParseConstraint(expr, nil)
+	if err != nil {
+		return err
+	}
+	c.Name = name
+	cfg.Constraints = append(cfg.Constraints, c)
+	return nil
+}
@@ -0,0 +1,67 @@
+package powerset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func collectSelectors(t *testing.T, sc SelectorConfig) []string {
+	out, _, err := Selectors(sc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	selectors := []string{}
+	for sel := range out {
+		selectors = append(selectors, sel.String())
+	}
+	return selectors
+}
+
+func TestSelectors(t *testing.T) {
+	sc := SelectorConfig{
+		Labels: []Label{
+			{"app", "web"},
+			{"app", "api"},
+			{"tier", "frontend"},
+			{"env", "prod"},
+		},
+		RequiredKeys:  []string{"app"},
+		ForbiddenKeys: []string{"env"},
+	}
+
+	selectors := collectSelectors(t, sc)
+	correct := []string{
+		"app=api",
+		"app=api,tier=frontend",
+		"app=web",
+		"app=web,tier=frontend",
+	}
+	if !reflect.DeepEqual(selectors, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", selectors, correct)
+	}
+}
+
+func TestSelectorsOneValuePerKey(t *testing.T) {
+	sc := SelectorConfig{
+		Labels: []Label{{"zone", "a"}, {"zone", "b"}, {"zone", "c"}},
+	}
+	selectors := collectSelectors(t, sc)
+	correct := []string{"", "zone=c", "zone=b", "zone=a"}
+	if !reflect.DeepEqual(selectors, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", selectors, correct)
+	}
+}
+
+func TestSelectorsErrors(t *testing.T) {
+	broken := []SelectorConfig{
+		{Labels: []Label{{"app", "web"}, {"app", "web"}}},
+		{Labels: []Label{{"app", "web"}}, RequiredKeys: []string{"tier"}},
+		{Labels: []Label{{"app", "web"}}, ForbiddenKeys: []string{"tier"}},
+		{Labels: []Label{{"app", "web"}}, MinSize: 2, MaxSize: 1},
+	}
+	for _, sc := range broken {
+		if _, _, err := Selectors(sc); err == nil {
+			t.Fatalf("expected an error for %+v", sc)
+		}
+	}
+}
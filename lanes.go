@@ -0,0 +1,65 @@
+package powerset
+
+// Lanes are the outputs of Dispatch
+type Lanes struct {
+	// the subsets that matched the urgent predicate, as soon as they're received
+	Priority <-chan []int
+
+	// the rest of the subsets, in batches
+	Bulk <-chan [][]int
+}
+
+// Dispatch splits the subsets received on in into two lanes, for applications that must react to some subsets, like
+// perfect solutions, with minimal latency while the rest are processed in bulk.  subsets that urgent returns true for
+// go to the priority lane straight away, and the others are sent on the bulk lane in batches of batchSize, with a last
+// partial batch once in is closed.  a slow bulk consumer never holds up the priority lane: full batches queue up in
+// memory until the bulk consumer catches up, so it must keep up on average.  the priority lane is closed once in is
+// closed, and the bulk lane once it has delivered the last batch
+func Dispatch(in <-chan []int, urgent func([]int) bool, batchSize int) Lanes {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+	priority := make(chan []int)
+	bulk := make(chan [][]int)
+
+	go func() {
+		defer close(bulk)
+
+		batch := make([][]int, 0, batchSize)
+		queued := [][][]int{}
+		for in != nil || len(queued) > 0 {
+			// a nil channel is never ready, which switches off the cases that have nothing to do
+			var bulkOut chan [][]int
+			var next [][]int
+			if len(queued) > 0 {
+				bulkOut, next = bulk, queued[0]
+			}
+
+			select {
+			case subset, ok := <-in:
+				if !ok {
+					in = nil
+					close(priority)
+					if len(batch) > 0 {
+						queued = append(queued, batch)
+					}
+					continue
+				}
+				if urgent(subset) {
+					priority <- subset
+					continue
+				}
+				batch = append(batch, subset)
+				if len(batch) == batchSize {
+					queued = append(queued, batch)
+					batch = make([][]int, 0, batchSize)
+				}
+			case bulkOut <- next:
+				queued[0] = nil
+				queued = queued[1:]
+			}
+		}
+	}()
+
+	return Lanes{Priority: priority, Bulk: bulk}
+}
@@ -0,0 +1,77 @@
+package powerset
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestDispatch(t *testing.T) {
+	cfg := Config{LenItems: 6}
+	out, _, _ := Family(cfg)
+	full := func(subset []int) bool { return len(subset) >= 5 }
+	lanes := Dispatch(out, full, 4)
+
+	urgent := [][]int{}
+	batches := [][][]int{}
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for subset := range lanes.Priority {
+			urgent = append(urgent, subset)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for batch := range lanes.Bulk {
+			batches = append(batches, batch)
+		}
+	}()
+	wg.Wait()
+
+	correctUrgent, correctBulk := [][]int{}, [][]int{}
+	for _, subset := range collectFamily(t, cfg) {
+		if full(subset) {
+			correctUrgent = append(correctUrgent, subset)
+		} else {
+			correctBulk = append(correctBulk, subset)
+		}
+	}
+	if !reflect.DeepEqual(urgent, correctUrgent) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", urgent, correctUrgent)
+	}
+
+	bulk := [][]int{}
+	for i, batch := range batches {
+		if len(batch) != 4 && i != len(batches)-1 {
+			t.Fatalf("batch %d has %d subsets, expected 4", i, len(batch))
+		}
+		bulk = append(bulk, batch...)
+	}
+	if !reflect.DeepEqual(bulk, correctBulk) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", bulk, correctBulk)
+	}
+}
+
+func TestDispatchSlowBulk(t *testing.T) {
+	out, _, _ := Family(Config{LenItems: 8})
+	lanes := Dispatch(out, func(subset []int) bool { return len(subset) == 8 }, 16)
+
+	// the priority lane is delivered in full while nobody reads the bulk lane
+	urgent := 0
+	for range lanes.Priority {
+		urgent++
+	}
+	if urgent != 1 {
+		t.Fatalf("expected 1 urgent subset, got %d", urgent)
+	}
+
+	bulk := 0
+	for batch := range lanes.Bulk {
+		bulk += len(batch)
+	}
+	if bulk != 255 {
+		t.Fatalf("expected 255 bulk subsets, got %d", bulk)
+	}
+}
@@ -0,0 +1,195 @@
+// Package main builds libpowerset, a C shared library that exposes the enumeration, ranking and sharding of this
+// module over a C ABI, so other languages can reuse the engine instead of reimplementing its order.  build it with
+//
+//	go build -buildmode=c-shared -o libpowerset.so ./libpowerset
+//
+// which also writes libpowerset.h.  ranks are uint64, so n is at most 64.  the layout of every struct is fixed for a
+// given PS_ABI_VERSION, and fields are only ever added in a new version
+package main
+
+/*
+#include <stdint.h>
+#include <stdlib.h>
+
+#define PS_ABI_VERSION 1
+
+#define PS_OK 0
+#define PS_STOPPED 1
+#define PS_EINVAL -1
+#define PS_ERANGE -2
+
+// an inclusive interval of ranks
+typedef struct {
+	uint64_t lo;
+	uint64_t hi;
+} ps_range;
+
+// a subset handed to a ps_visit, whose indices are sorted and only valid until the visit returns
+typedef struct {
+	uint64_t rank;
+	uint32_t size;
+	uint32_t reserved;
+	const int32_t *indices;
+} ps_subset;
+
+// called with each subset of ps_enumerate, returning nonzero to stop
+typedef int (*ps_visit)(const ps_subset *subset, void *user);
+
+static inline int ps_call_visit(ps_visit visit, const ps_subset *subset, void *user) {
+	return visit(subset, user);
+}
+*/
+import "C"
+
+import (
+	"math/big"
+	"unsafe"
+
+	"github.com/amoffat/powerset"
+)
+
+// the most items whose ranks fit in a uint64
+const maxItems = 64
+
+func main() {}
+
+//export ps_abi_version
+func ps_abi_version() C.uint32_t {
+	return C.PS_ABI_VERSION
+}
+
+// ps_rank writes the rank of the subset of n items given by size indices, in any order
+//
+//export ps_rank
+func ps_rank(n C.uint32_t, indices *C.int32_t, size C.uint32_t, rank *C.uint64_t) C.int {
+	if rank == nil || (indices == nil && size > 0) {
+		return C.PS_EINVAL
+	}
+	subset := make([]int, size)
+	for i, idx := range unsafe.Slice(indices, size) {
+		subset[i] = int(idx)
+	}
+	r, code := rankOf(int(n), subset)
+	if code == C.PS_OK {
+		*rank = C.uint64_t(r)
+	}
+	return code
+}
+
+// ps_unrank writes the sorted indices of the subset of n items with the given rank into indices, which must have room
+// for n of them, and how many there are into size
+//
+//export ps_unrank
+func ps_unrank(n C.uint32_t, rank C.uint64_t, indices *C.int32_t, size *C.uint32_t) C.int {
+	if size == nil || (indices == nil && n > 0) {
+		return C.PS_EINVAL
+	}
+	subset, code := unrank(int(n), uint64(rank))
+	if code != C.PS_OK {
+		return code
+	}
+	out := unsafe.Slice(indices, n)
+	for i, idx := range subset {
+		out[i] = C.int32_t(idx)
+	}
+	*size = C.uint32_t(len(subset))
+	return C.PS_OK
+}
+
+// ps_shard writes the ranks of shard number shard when the powerset of n items is split into shards contiguous
+// intervals, which is empty, with lo greater than hi, when there are more shards than subsets
+//
+//export ps_shard
+func ps_shard(n C.uint32_t, shards C.uint32_t, shard C.uint32_t, out *C.ps_range) C.int {
+	if out == nil {
+		return C.PS_EINVAL
+	}
+	lo, hi, code := shardOf(int(n), int(shards), int(shard))
+	if code == C.PS_OK {
+		out.lo, out.hi = C.uint64_t(lo), C.uint64_t(hi)
+	}
+	return code
+}
+
+// ps_enumerate calls visit with every subset of n items whose rank is in r, in rank order, returning PS_STOPPED if
+// visit stopped it early
+//
+//export ps_enumerate
+func ps_enumerate(n C.uint32_t, r C.ps_range, visit C.ps_visit, user unsafe.Pointer) C.int {
+	if visit == nil {
+		return C.PS_EINVAL
+	}
+
+	// the subset handed to C must not be Go memory, since it points to the indices
+	subset := (*C.ps_subset)(C.malloc(C.size_t(unsafe.Sizeof(C.ps_subset{}))))
+	defer C.free(unsafe.Pointer(subset))
+	buf := (*C.int32_t)(C.malloc(C.size_t(maxItems * unsafe.Sizeof(C.int32_t(0)))))
+	defer C.free(unsafe.Pointer(buf))
+	indices := unsafe.Slice(buf, maxItems)
+
+	return enumerate(int(n), uint64(r.lo), uint64(r.hi), func(rank uint64, s []int) bool {
+		for i, idx := range s {
+			indices[i] = C.int32_t(idx)
+		}
+		*subset = C.ps_subset{rank: C.uint64_t(rank), size: C.uint32_t(len(s)), indices: buf}
+		return C.ps_call_visit(visit, subset, user) == 0
+	})
+}
+
+func rankOf(n int, subset []int) (uint64, C.int) {
+	if n > maxItems {
+		return 0, C.PS_ERANGE
+	}
+	seen := make([]bool, n)
+	for _, idx := range subset {
+		if idx < 0 || idx >= n || seen[idx] {
+			return 0, C.PS_EINVAL
+		}
+		seen[idx] = true
+	}
+	lo, _ := powerset.PathOf(subset, n).RankInterval(n)
+	return lo.Uint64(), C.PS_OK
+}
+
+func unrank(n int, rank uint64) ([]int, C.int) {
+	if n > maxItems {
+		return nil, C.PS_ERANGE
+	}
+	r := new(big.Int).SetUint64(rank)
+	if r.BitLen() > n {
+		return nil, C.PS_ERANGE
+	}
+	for _, subset := range powerset.EnumerateRange(n, r, r) {
+		return subset, C.PS_OK
+	}
+	return nil, C.PS_ERANGE
+}
+
+func shardOf(n int, shards int, shard int) (lo, hi uint64, code C.int) {
+	if n > maxItems {
+		return 0, 0, C.PS_ERANGE
+	}
+	bigLo, bigHi, err := powerset.Shard(n, shards, shard)
+	if err != nil {
+		return 0, 0, C.PS_EINVAL
+	}
+	// an empty shard's hi is one below its lo, which is -1 for the first
+	if bigHi.Sign() < 0 {
+		return 1, 0, C.PS_OK
+	}
+	return bigLo.Uint64(), bigHi.Uint64(), C.PS_OK
+}
+
+// enumerate visits the subsets of n items with ranks from lo to hi, returning PS_STOPPED if visit returned false
+func enumerate(n int, lo uint64, hi uint64, visit func(rank uint64, subset []int) bool) C.int {
+	if n > maxItems {
+		return C.PS_ERANGE
+	}
+	bigLo, bigHi := new(big.Int).SetUint64(lo), new(big.Int).SetUint64(hi)
+	for rank, subset := range powerset.EnumerateRange(n, bigLo, bigHi) {
+		if !visit(rank.Uint64(), subset) {
+			return C.PS_STOPPED
+		}
+	}
+	return C.PS_OK
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRankUnrank(t *testing.T) {
+	for rank := uint64(0); rank < 1<<6; rank++ {
+		subset, code := unrank(6, rank)
+		if code != 0 {
+			t.Fatalf("unexpected code %d", code)
+		}
+		if got, code := rankOf(6, subset); code != 0 || got != rank {
+			t.Fatalf("%v has rank %d, expected %d", subset, got, rank)
+		}
+	}
+
+	if _, code := unrank(3, 8); code == 0 {
+		t.Fatalf("expected an error for a rank past the last subset")
+	}
+	if _, code := rankOf(3, []int{1, 1}); code == 0 {
+		t.Fatalf("expected an error for a repeated index")
+	}
+	if subset, code := unrank(64, 1); code != 0 || !reflect.DeepEqual(subset, []int{63}) {
+		t.Fatalf("unexpected subset %v", subset)
+	}
+}
+
+func TestShardEnumerate(t *testing.T) {
+	ranks := []uint64{}
+	for shard := 0; shard < 3; shard++ {
+		lo, hi, code := shardOf(4, 3, shard)
+		if code != 0 {
+			t.Fatalf("unexpected code %d", code)
+		}
+		enumerate(4, lo, hi, func(rank uint64, subset []int) bool {
+			if r, _ := rankOf(4, subset); r != rank {
+				t.Fatalf("%v has rank %d, expected %d", subset, r, rank)
+			}
+			ranks = append(ranks, rank)
+			return true
+		})
+	}
+	for i, rank := range ranks {
+		if rank != uint64(i) {
+			t.Fatalf("expected the shards to cover every rank once, got %v", ranks)
+		}
+	}
+	if len(ranks) != 16 {
+		t.Fatalf("expected 16 ranks, got %d", len(ranks))
+	}
+
+	if lo, hi, _ := shardOf(1, 3, 0); lo <= hi {
+		t.Fatalf("expected an empty shard, got %d..%d", lo, hi)
+	}
+	if code := enumerate(4, 0, 15, func(uint64, []int) bool { return false }); code != 1 {
+		t.Fatalf("expected the enumeration to be stopped, got %d", code)
+	}
+}
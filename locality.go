@@ -0,0 +1,68 @@
+package powerset
+
+// localityBuffer reorders a stream of subsets for OrderLocality.  it fills up with window subsets, and from then on
+// each new subset lets out whichever subset in the window is closest to the previous one it let out
+type localityBuffer struct {
+	window  int
+	pending [][]int
+	last    []int
+
+	// counts the subsets in the window, if not nil
+	memory *memoryMeter
+}
+
+func newLocalityBuffer(window int, memory *memoryMeter) *localityBuffer {
+	if window <= 0 {
+		window = defaultLookahead
+	}
+	return &localityBuffer{window: window, pending: make([][]int, 0, window), memory: memory}
+}
+
+// push adds a subset to the window
+func (b *localityBuffer) push(subset []int) {
+	b.pending = append(b.pending, subset)
+	if b.memory != nil {
+		b.memory.add(subsetBytes(subset))
+	}
+}
+
+// ready returns the next subset in order while the window is full
+func (b *localityBuffer) ready() ([]int, bool) {
+	if len(b.pending) < b.window {
+		return nil, false
+	}
+	return b.pop()
+}
+
+// shrink halves the window, down to a single subset, which makes ready return the subsets that no longer fit
+func (b *localityBuffer) shrink() {
+	if b.window > 1 {
+		b.window /= 2
+	}
+}
+
+// pop removes and returns the subset in the window closest to the previous one, preferring the earliest on ties, so a
+// window of a single subset doesn't reorder anything.  ok is false once the window is empty
+func (b *localityBuffer) pop() ([]int, bool) {
+	if len(b.pending) == 0 {
+		return nil, false
+	}
+
+	best := 0
+	if b.last != nil {
+		bestDistance := HammingDistance(b.last, b.pending[0])
+		for i := 1; i < len(b.pending) && bestDistance > 1; i++ {
+			if d := HammingDistance(b.last, b.pending[i]); d < bestDistance {
+				best, bestDistance = i, d
+			}
+		}
+	}
+
+	subset := b.pending[best]
+	b.pending = append(b.pending[:best], b.pending[best+1:]...)
+	if b.memory != nil {
+		b.memory.add(-subsetBytes(subset))
+	}
+	b.last = subset
+	return subset, true
+}
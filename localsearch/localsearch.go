@@ -0,0 +1,172 @@
+// Package localsearch finds good subsets heuristically, for powersets too large to search exhaustively.  it works on
+// the same representation as the powerset package, a sorted slice of included indices, and scoring functions written
+// for powerset.ParetoFront or an exhaustive search can be used here unchanged.  larger scores are better
+package localsearch
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/amoffat/powerset"
+)
+
+// Score rates a subset, given as a sorted slice of its included indices.  larger scores are better
+type Score func(indices []int) float64
+
+// Result is the best subset a search found, along with its score
+type Result struct {
+	Indices []int
+	Score   float64
+}
+
+// HillClimb starts from the subset start and repeatedly moves to the best scoring subset in its Hamming ball of the
+// given radius, until no subset in the ball improves on the current one.  the result is a local optimum
+func HillClimb(lenItems int, score Score, start []int, radius int) Result {
+	return hillClimb(context.Background(), lenItems, score, start, radius)
+}
+
+// hillClimb is HillClimb, returning the current subset early once ctx is done.  ctx is checked before every round of
+// the ball is scored
+func hillClimb(ctx context.Context, lenItems int, score Score, start []int, radius int) Result {
+	current := Result{Indices: sorted(start)}
+	current.Score = score(current.Indices)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return current
+		default:
+		}
+
+		best := current
+		out, _ := powerset.HammingBall(lenItems, current.Indices, radius)
+		for neighbor := range out {
+			if s := score(neighbor); s > best.Score {
+				best = Result{Indices: neighbor, Score: s}
+			}
+		}
+
+		if best.Score <= current.Score {
+			return current
+		}
+		current = best
+	}
+}
+
+// AnnealConfig controls a simulated annealing run.  Rand must not be nil
+type AnnealConfig struct {
+	// the number of moves to attempt
+	Steps int
+
+	// the temperature decays geometrically from StartTemp to EndTemp over the run
+	StartTemp float64
+	EndTemp   float64
+
+	// each move flips between 1 and Radius random indices.  a Radius of zero is treated as 1
+	Radius int
+
+	Rand *rand.Rand
+}
+
+// Anneal runs simulated annealing from the subset start.  each step flips a few random indices, and moves that lower
+// the score are still accepted with a probability that shrinks as the temperature cools, which lets the search escape
+// the local optima that HillClimb gets stuck in.  returns the best subset seen during the run
+func Anneal(lenItems int, score Score, start []int, cfg AnnealConfig) Result {
+	return anneal(context.Background(), lenItems, score, start, cfg)
+}
+
+// anneal is Anneal, returning the best subset seen so far once ctx is done.  ctx is checked before every step
+func anneal(ctx context.Context, lenItems int, score Score, start []int, cfg AnnealConfig) Result {
+	radius := cfg.Radius
+	if radius < 1 {
+		radius = 1
+	}
+
+	included := make([]bool, lenItems)
+	for _, idx := range start {
+		included[idx] = true
+	}
+
+	current := score(toIndices(included))
+	best := Result{Indices: toIndices(included), Score: current}
+	if lenItems == 0 {
+		return best
+	}
+
+	temp := cfg.StartTemp
+	decay := 1.0
+	if cfg.Steps > 1 && cfg.StartTemp > 0 && cfg.EndTemp > 0 {
+		decay = math.Pow(cfg.EndTemp/cfg.StartTemp, 1/float64(cfg.Steps-1))
+	}
+
+	flipped := make([]int, 0, radius)
+	for step := 0; step < cfg.Steps; step++ {
+		select {
+		case <-ctx.Done():
+			return best
+		default:
+		}
+
+		flipped = flipped[:0]
+		numFlips := 1 + cfg.Rand.Intn(radius)
+		for i := 0; i < numFlips; i++ {
+			idx := cfg.Rand.Intn(lenItems)
+			included[idx] = !included[idx]
+			flipped = append(flipped, idx)
+		}
+
+		candidate := toIndices(included)
+		s := score(candidate)
+
+		if s >= current || (temp > 0 && cfg.Rand.Float64() < math.Exp((s-current)/temp)) {
+			current = s
+			if s > best.Score {
+				best = Result{Indices: candidate, Score: s}
+			}
+		} else {
+			// undo in reverse, in case the same index was flipped twice
+			for i := len(flipped) - 1; i >= 0; i-- {
+				included[flipped[i]] = !included[flipped[i]]
+			}
+		}
+
+		temp *= decay
+	}
+	return best
+}
+
+func toIndices(included []bool) []int {
+	indices := []int{}
+	for idx, in := range included {
+		if in {
+			indices = append(indices, idx)
+		}
+	}
+	return indices
+}
+
+func sorted(indices []int) []int {
+	s := append([]int{}, indices...)
+	sort.Ints(s)
+	return s
+}
+
+// HillClimber is a powerset.Searcher that runs HillClimb, for use in a powerset.Portfolio.  a local optimum proves
+// nothing, so its outcome is never decisive.  once ctx is done, it stops climbing after the round it's in
+func HillClimber(lenItems int, score Score, start []int, radius int) powerset.Searcher {
+	return powerset.SearcherFunc("hillclimb", func(ctx context.Context) (powerset.Outcome, error) {
+		result := hillClimb(ctx, lenItems, score, start, radius)
+		return powerset.Outcome{Scored: powerset.Scored(result)}, ctx.Err()
+	})
+}
+
+// Annealer is a powerset.Searcher that runs Anneal, for use in a powerset.Portfolio.  its outcome is never decisive.
+// once ctx is done, it stops after the step it's in
+func Annealer(lenItems int, score Score, start []int, cfg AnnealConfig) powerset.Searcher {
+	return powerset.SearcherFunc("anneal", func(ctx context.Context) (powerset.Outcome, error) {
+		result := anneal(ctx, lenItems, score, start, cfg)
+		return powerset.Outcome{Scored: powerset.Scored(result)}, ctx.Err()
+	})
+}
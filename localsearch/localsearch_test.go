@@ -0,0 +1,132 @@
+package localsearch
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/amoffat/powerset"
+)
+
+// rewards including even indices and penalizes odd ones, so the optimum is every even index
+func evenScore(indices []int) float64 {
+	total := 0.0
+	for _, idx := range indices {
+		if idx%2 == 0 {
+			total++
+		} else {
+			total--
+		}
+	}
+	return total
+}
+
+func TestHillClimb(t *testing.T) {
+	result := HillClimb(6, evenScore, []int{1, 3}, 1)
+
+	correct := []int{0, 2, 4}
+	if !reflect.DeepEqual(correct, result.Indices) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", result.Indices, correct)
+	}
+	if result.Score != 3 {
+		t.Fatalf("score %v != 3", result.Score)
+	}
+}
+
+func TestHillClimbLocalOptimum(t *testing.T) {
+	// only the full set scores well, so a radius of 1 can't see past the empty set's neighbors
+	allOrNothing := func(indices []int) float64 {
+		if len(indices) == 4 {
+			return 10
+		}
+		return -float64(len(indices))
+	}
+
+	result := HillClimb(4, allOrNothing, nil, 1)
+	if len(result.Indices) != 0 {
+		t.Fatalf("expected to stay at the empty set, got %v", result.Indices)
+	}
+
+	result = HillClimb(4, allOrNothing, nil, 4)
+	if len(result.Indices) != 4 {
+		t.Fatalf("expected the full set with a large radius, got %v", result.Indices)
+	}
+}
+
+func TestAnneal(t *testing.T) {
+	cfg := AnnealConfig{
+		Steps:     2000,
+		StartTemp: 2,
+		EndTemp:   0.01,
+		Rand:      rand.New(rand.NewSource(1)),
+	}
+	result := Anneal(8, evenScore, nil, cfg)
+
+	correct := []int{0, 2, 4, 6}
+	if !reflect.DeepEqual(correct, result.Indices) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", result.Indices, correct)
+	}
+}
+
+func TestAnnealReplayable(t *testing.T) {
+	run := func() Result {
+		cfg := AnnealConfig{Steps: 100, StartTemp: 1, EndTemp: 0.1, Radius: 2, Rand: rand.New(rand.NewSource(7))}
+		return Anneal(10, evenScore, []int{1}, cfg)
+	}
+	a, b := run(), run()
+	if !reflect.DeepEqual(a, b) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", a, b)
+	}
+}
+
+func TestPortfolioStrategies(t *testing.T) {
+	cfg := AnnealConfig{Steps: 500, StartTemp: 1, EndTemp: 0.01, Rand: rand.New(rand.NewSource(3))}
+	result, err := powerset.Portfolio(context.Background(), HillClimber(6, evenScore, nil, 1),
+		Annealer(6, evenScore, nil, cfg))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Score != 3 || result.Decisive {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if result.Winner != "hillclimb" {
+		t.Fatalf("expected the first of the tied strategies to win, got %v", result.Winner)
+	}
+}
+
+// a searcher that reports when it returns
+func returned(searcher powerset.Searcher, done chan<- string) powerset.Searcher {
+	return powerset.SearcherFunc(searcher.Name(), func(ctx context.Context) (powerset.Outcome, error) {
+		outcome, err := searcher.Search(ctx)
+		done <- searcher.Name()
+		return outcome, err
+	})
+}
+
+func TestPortfolioCancelsLocalSearch(t *testing.T) {
+	decisive := powerset.SearcherFunc("decisive", func(ctx context.Context) (powerset.Outcome, error) {
+		return powerset.Outcome{Decisive: true}, nil
+	})
+	// both of these would take far longer than the test allows if they ran to completion
+	cfg := AnnealConfig{Steps: 1 << 40, StartTemp: 1, EndTemp: 0.01, Rand: rand.New(rand.NewSource(3))}
+	done := make(chan string, 2)
+	result, err := powerset.Portfolio(context.Background(), decisive, returned(HillClimber(4000, evenScore, nil, 1),
+		done), returned(Annealer(4000, evenScore, nil, cfg), done))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Winner != "decisive" {
+		t.Fatalf("unexpected winner %v", result.Winner)
+	}
+
+	timeout := time.After(5 * time.Second)
+	for i := 0; i < 2; i++ {
+		select {
+		case <-done:
+		case <-timeout:
+			t.Fatalf("a local searcher kept running after it was cancelled")
+		}
+	}
+}
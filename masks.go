@@ -0,0 +1,76 @@
+package powerset
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+// Masks generates the powerset of up to 64 items as bit masks, where bit i is set when index i is included, which
+// costs no allocations per subset.  the subsets come out in the same order as FixedSize, and stop works the same way.
+// it panics if lenItems is more than 64
+func Masks(lenItems int) (<-chan uint64, func()) {
+	if lenItems > 64 {
+		panic(fmt.Sprintf("powerset: Masks can represent at most 64 items, got %d", lenItems))
+	}
+	out := make(chan uint64)
+	stopIn := make(chan bool)
+
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		defer close(out)
+		defer wg.Done()
+
+		// shifting by 64 gives 0, so the last rank of 64 items is every bit set
+		last := uint64(1)<<lenItems - 1
+		for rank := uint64(0); ; rank++ {
+			select {
+			case <-stopIn:
+				return
+			case out <- rankMask(rank, lenItems):
+			}
+			if rank == last {
+				return
+			}
+		}
+	}()
+
+	return out, makeStopper(stopIn, wg)
+}
+
+// BigMasks is Masks for any number of items, with each subset as a newly allocated *big.Int whose bit i is set when
+// index i is included, so universes of more than 64 items can still be consumed as compact bit sets
+func BigMasks(lenItems int) (<-chan *big.Int, func()) {
+	out := make(chan *big.Int)
+	stopIn := make(chan bool)
+
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		defer close(out)
+		defer wg.Done()
+
+		mask := new(big.Int)
+		for {
+			select {
+			case <-stopIn:
+				return
+			case out <- new(big.Int).Set(mask):
+			}
+
+			// adding one to the rank carries from the last index, which is the least significant bit of the rank
+			idx := lenItems - 1
+			for idx >= 0 && mask.Bit(idx) == 1 {
+				mask.SetBit(mask, idx, 0)
+				idx--
+			}
+			if idx < 0 {
+				return
+			}
+			mask.SetBit(mask, idx, 1)
+		}
+	}()
+
+	return out, makeStopper(stopIn, wg)
+}
@@ -0,0 +1,69 @@
+package powerset
+
+import (
+	"math/bits"
+	"testing"
+)
+
+func TestMasks(t *testing.T) {
+	fixed, _ := FixedSize(5)
+	masks, _ := Masks(5)
+	for subset := range fixed {
+		mask := <-masks
+		for idx, included := range subset {
+			if included != (mask&(1<<idx) != 0) {
+				t.Fatalf("mask %b doesn't match %v", mask, subset)
+			}
+		}
+	}
+	if _, ok := <-masks; ok {
+		t.Fatalf("expected no more masks")
+	}
+
+	empty, _ := Masks(0)
+	if mask, ok := <-empty; !ok || mask != 0 {
+		t.Fatalf("expected just the empty mask")
+	}
+	if _, ok := <-empty; ok {
+		t.Fatalf("expected no more masks")
+	}
+}
+
+func TestMasks64(t *testing.T) {
+	out, stop := Masks(64)
+	if mask := <-out; mask != 0 {
+		t.Fatalf("expected the empty mask first, got %b", mask)
+	}
+	if mask := <-out; mask != 1<<63 || bits.OnesCount64(mask) != 1 {
+		t.Fatalf("expected index 63 alone second, got %b", mask)
+	}
+	stop()
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected a panic")
+		}
+	}()
+	Masks(65)
+}
+
+func TestBigMasks(t *testing.T) {
+	masks, _ := Masks(6)
+	large, _ := BigMasks(6)
+	for mask := range masks {
+		got := <-large
+		if !got.IsUint64() || got.Uint64() != mask {
+			t.Fatalf("\n%v\n\n!=\n\n%v", got, mask)
+		}
+	}
+	if _, ok := <-large; ok {
+		t.Fatalf("expected no more masks")
+	}
+
+	out, stop := BigMasks(100)
+	<-out
+	if mask := <-out; mask.BitLen() != 100 || mask.Bit(99) != 1 {
+		t.Fatalf("expected index 99 alone second, got %v", mask)
+	}
+	stop()
+}
@@ -0,0 +1,45 @@
+package powerset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMaxSolutions(t *testing.T) {
+	out, _, _ := Family(Config{LenItems: 40}, WithMaxSolutions(3))
+
+	allValues := [][]int{}
+	for indices := range out {
+		allValues = append(allValues, indices)
+	}
+	correct := [][]int{
+		{},
+		{39},
+		{38},
+	}
+	if !reflect.DeepEqual(correct, allValues) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", allValues, correct)
+	}
+}
+
+func TestCallbackMaxSolutions(t *testing.T) {
+	calls := 0
+	visit := func(path Path, isLeaf bool, state interface{}, out chan<- interface{}) (bool, int, interface{}) {
+		calls++
+		if isLeaf {
+			out <- path
+		}
+		return false, 0, nil
+	}
+
+	count := 0
+	for range Callback(40, visit, nil, WithMaxSolutions(5)) {
+		count++
+	}
+	if count != 5 {
+		t.Fatalf("received %d solutions, expected 5", count)
+	}
+	if calls > 100 {
+		t.Fatalf("callback kept being called after the limit, %d calls", calls)
+	}
+}
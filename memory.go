@@ -0,0 +1,78 @@
+package powerset
+
+import (
+	"math"
+	"sync/atomic"
+	"unsafe"
+)
+
+// WithMemoryBudget keeps the approximate memory held by a search's buffered results, filters and caches under bytes, so
+// a long search degrades gracefully instead of running out of memory.  the Bloom filter of WithBloomDedup and the ring
+// of WithRingBuffer are sized to fit within the budget when the search starts, at the cost of more false positives and
+// less buffering, and while the search runs, recycled buffers are dropped and the OrderLocality window shrinks whenever
+// the budget is exceeded.  the budget doesn't cover subsets the consumer has received, or memory the Go runtime holds
+// on to, so it's a guide for sizing rather than a hard limit.  Stats.Memory reports the estimate
+func WithMemoryBudget(bytes uint64) Option {
+	return func(o *options) {
+		o.memoryBudget = bytes
+	}
+}
+
+// how much of the budget the structures sized up front may take, as fractions of it
+const (
+	bloomBudgetShare = 0.5
+	ringBudgetShare  = 0.25
+)
+
+// memoryMeter is the estimate of the memory a search holds.  it's updated by the search and read by Stats
+type memoryMeter struct {
+	budget uint64
+	used   atomic.Int64
+}
+
+func (m *memoryMeter) add(bytes int64) {
+	m.used.Add(bytes)
+}
+
+// over reports whether the search is holding more than its budget
+func (m *memoryMeter) over() bool {
+	return m.budget > 0 && m.used.Load() > int64(m.budget)
+}
+
+// the slice header and the backing array of a subset
+func subsetBytes(subset []int) int64 {
+	return int64(unsafe.Sizeof(subset)) + int64(cap(subset))*int64(unsafe.Sizeof(int(0)))
+}
+
+// share returns the part of the budget that a structure may take, or 0 if there's no budget
+func (m *memoryMeter) share(fraction float64) uint64 {
+	return uint64(float64(m.budget) * fraction)
+}
+
+// fitBloomFilter shrinks the false positive rate's demands until a filter for expected subsets fits in maxBytes, which
+// is a better outcome than no filter at all.  a maxBytes of 0 means no limit
+func fitBloomFilter(expected uint64, fpRate float64, maxBytes uint64) *bloomFilter {
+	bf := newBloomFilter(expected, fpRate)
+	if maxBytes == 0 || uint64(len(bf.bits))*8 <= maxBytes {
+		return bf
+	}
+
+	numBits := maxBytes * 8
+	if numBits < 64 {
+		numBits = 64
+	}
+	numHashes := int(math.Round(float64(numBits) / float64(expected) * math.Ln2))
+	if numHashes < 1 {
+		numHashes = 1
+	}
+	return &bloomFilter{bits: make([]uint64, (numBits+63)/64), numBits: numBits, numHashes: numHashes}
+}
+
+// fitRingSize shrinks a ring buffer's size until its slots fit in maxBytes, leaving it at least one slot
+func fitRingSize(size int, maxBytes uint64) int {
+	slot := uint64(unsafe.Sizeof([]int(nil)))
+	for size > 1 && maxBytes > 0 && uint64(size)*slot > maxBytes {
+		size /= 2
+	}
+	return size
+}
@@ -0,0 +1,64 @@
+package powerset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMemoryBudgetBloom(t *testing.T) {
+	_, ctl, _ := Start(Config{LenItems: 3}, WithBloomDedup(1_000_000, 1e-6))
+	if unbudgeted := ctl.Stats().Memory; unbudgeted < 1_000_000 {
+		t.Fatalf("expected a large filter without a budget, got %d bytes", unbudgeted)
+	}
+	ctl.Stop()
+
+	out, ctl, _ := Start(Config{LenItems: 3}, WithBloomDedup(1_000_000, 1e-6), WithMemoryBudget(4096))
+	if memory := ctl.Stats().Memory; memory > 2048 {
+		t.Fatalf("expected the filter to take half the budget, got %d bytes", memory)
+	}
+	count := 0
+	for range out {
+		count++
+	}
+	if count == 0 {
+		t.Fatalf("expected the shrunk filter to still let subsets through")
+	}
+}
+
+func TestMemoryBudgetLocality(t *testing.T) {
+	cfg := Config{LenItems: 12, MinSize: 3}
+	const budget = 4096
+
+	out, ctl, _ := Start(cfg, WithOrder(OrderLocality), WithLookahead(1024), WithMemoryBudget(budget))
+	allValues := [][]int{}
+	peak := uint64(0)
+	for subset := range out {
+		if memory := ctl.Stats().Memory; memory > peak {
+			peak = memory
+		}
+		allValues = append(allValues, subset)
+	}
+
+	// the window is only shrunk once it's over budget, so it can go over by a subset at a time
+	if peak > budget+budget/4 {
+		t.Fatalf("peaked at %d bytes, over the budget of %d", peak, budget)
+	}
+	if correct := collectFamily(t, cfg); !reflect.DeepEqual(sortedSubsets(allValues), sortedSubsets(correct)) {
+		t.Fatalf("expected every subset despite the shrinking window")
+	}
+	if ctl.Stats().Memory != 0 {
+		t.Fatalf("expected nothing to be held at the end, got %d bytes", ctl.Stats().Memory)
+	}
+}
+
+func TestMemoryBudgetRing(t *testing.T) {
+	if size := fitRingSize(1024, 24*100); size != 64 {
+		t.Fatalf("ring of %d, expected 64", size)
+	}
+	if size := fitRingSize(1024, 1); size != 1 {
+		t.Fatalf("ring of %d, expected 1", size)
+	}
+	if size := fitRingSize(1024, 0); size != 1024 {
+		t.Fatalf("ring of %d, expected no limit", size)
+	}
+}
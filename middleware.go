@@ -0,0 +1,100 @@
+package powerset
+
+import (
+	"sync"
+	"time"
+)
+
+// Middleware wraps a NodeCallback in another, for concerns like timing, logging or recovering from panics that would
+// otherwise be copied into every callback.  a middleware may do work before and after calling next, change its
+// arguments or its results, or not call it at all
+type Middleware func(next NodeCallback) NodeCallback
+
+// Chain wraps cb in every middleware, so that the first middleware is the outermost, and sees each node first
+func Chain(cb NodeCallback, mw ...Middleware) NodeCallback {
+	for i := len(mw) - 1; i >= 0; i-- {
+		cb = mw[i](cb)
+	}
+	return cb
+}
+
+// Timing measures how long the rest of the chain takes at each node, and passes it to record
+func Timing(record func(path Path, isLeaf bool, d time.Duration)) Middleware {
+	return func(next NodeCallback) NodeCallback {
+		return func(path Path, isLeaf bool, state interface{}, out chan<- interface{}) (bool, int, interface{}) {
+			start := time.Now()
+			terminate, parent, newState := next(path, isLeaf, state, out)
+			record(path, isLeaf, time.Since(start))
+			return terminate, parent, newState
+		}
+	}
+}
+
+// Recover stops a panic in the rest of the chain from crashing the traversal.  the recovered value is passed to
+// onPanic, and the node's subtree is terminated, continuing at its parent
+func Recover(onPanic func(path Path, recovered interface{})) Middleware {
+	return func(next NodeCallback) NodeCallback {
+		return func(path Path, isLeaf bool, state interface{}, out chan<- interface{}) (terminate bool, parent int,
+			newState interface{}) {
+
+			defer func() {
+				if r := recover(); r != nil {
+					onPanic(path, r)
+					terminate, parent, newState = true, len(path)-1, nil
+				}
+			}()
+			return next(path, isLeaf, state, out)
+		}
+	}
+}
+
+// Logging writes a line to logf for each node, with its path and what the rest of the chain returned.  log.Printf can
+// be passed as logf
+func Logging(logf func(format string, args ...interface{})) Middleware {
+	return func(next NodeCallback) NodeCallback {
+		return func(path Path, isLeaf bool, state interface{}, out chan<- interface{}) (bool, int, interface{}) {
+			terminate, parent, newState := next(path, isLeaf, state, out)
+			kind := "node"
+			if isLeaf {
+				kind = "leaf"
+			}
+			if terminate {
+				logf("powerset: %s %v terminated to %d", kind, path, parent)
+			} else {
+				logf("powerset: %s %v", kind, path)
+			}
+			return terminate, parent, newState
+		}
+	}
+}
+
+// Memoize remembers the results of the rest of the chain by key, and returns them again for any later node with the
+// same key without calling it, for callbacks that are expensive and reach equivalent nodes more than once.  key must
+// return comparable values, and the rest of the chain isn't called for a remembered node, so anything it would have
+// written to out isn't written again
+func Memoize(key func(path Path, isLeaf bool, state interface{}) interface{}) Middleware {
+	type result struct {
+		terminate bool
+		parent    int
+		state     interface{}
+	}
+	return func(next NodeCallback) NodeCallback {
+		mu := sync.Mutex{}
+		seen := map[interface{}]result{}
+		return func(path Path, isLeaf bool, state interface{}, out chan<- interface{}) (bool, int, interface{}) {
+			k := key(path, isLeaf, state)
+			mu.Lock()
+			r, ok := seen[k]
+			mu.Unlock()
+			if ok {
+				return r.terminate, r.parent, r.state
+			}
+
+			r.terminate, r.parent, r.state = next(path, isLeaf, state, out)
+			mu.Lock()
+			seen[k] = r
+			mu.Unlock()
+			return r.terminate, r.parent, r.state
+		}
+	}
+}
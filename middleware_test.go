@@ -0,0 +1,127 @@
+package powerset
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// a callback that writes every leaf to out
+func leafWriter(path Path, isLeaf bool, state interface{}, out chan<- interface{}) (bool, int, interface{}) {
+	if isLeaf {
+		out <- path.String()
+	}
+	return false, 0, nil
+}
+
+func collectCallback(lenItems int, cb NodeCallback) []interface{} {
+	values := []interface{}{}
+	for value := range Callback(lenItems, cb, nil) {
+		values = append(values, value)
+	}
+	return values
+}
+
+func TestChainOrder(t *testing.T) {
+	order := []string{}
+	tag := func(name string) Middleware {
+		return func(next NodeCallback) NodeCallback {
+			return func(path Path, isLeaf bool, state interface{}, out chan<- interface{}) (bool, int, interface{}) {
+				order = append(order, name+" before")
+				terminate, parent, newState := next(path, isLeaf, state, out)
+				order = append(order, name+" after")
+				return terminate, parent, newState
+			}
+		}
+	}
+	cb := func(Path, bool, interface{}, chan<- interface{}) (bool, int, interface{}) {
+		order = append(order, "callback")
+		return true, -1, nil
+	}
+
+	collectCallback(2, Chain(cb, tag("outer"), tag("inner")))
+	correct := []string{"outer before", "inner before", "callback", "inner after", "outer after"}
+	if !reflect.DeepEqual(order, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", order, correct)
+	}
+}
+
+func TestTiming(t *testing.T) {
+	nodes, leaves := 0, 0
+	timing := Timing(func(path Path, isLeaf bool, d time.Duration) {
+		nodes++
+		if isLeaf {
+			leaves++
+		}
+		if d < 0 {
+			t.Fatalf("negative duration %v", d)
+		}
+	})
+	collectCallback(3, Chain(leafWriter, timing))
+	if nodes != 15 || leaves != 8 {
+		t.Fatalf("expected 15 nodes and 8 leaves to be timed, got %d and %d", nodes, leaves)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	panicked := []string{}
+	cb := func(path Path, isLeaf bool, state interface{}, out chan<- interface{}) (bool, int, interface{}) {
+		// every subtree that includes index 0 panics
+		if len(path) > 0 && path[len(path)-1].Index == 0 && path[len(path)-1].Included {
+			panic("boom")
+		}
+		return leafWriter(path, isLeaf, state, out)
+	}
+	recoverer := Recover(func(path Path, recovered interface{}) {
+		panicked = append(panicked, fmt.Sprintf("%v: %v", path, recovered))
+	})
+
+	leaves := collectCallback(2, Chain(cb, recoverer))
+	if len(leaves) != 2 {
+		t.Fatalf("expected the 2 leaves without index 0, got %v", leaves)
+	}
+	if !reflect.DeepEqual(panicked, []string{"{0 true}: boom"}) {
+		t.Fatalf("unexpected panics %v", panicked)
+	}
+}
+
+func TestLogging(t *testing.T) {
+	lines := []string{}
+	logf := func(format string, args ...interface{}) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	}
+	cb := func(path Path, isLeaf bool, state interface{}, out chan<- interface{}) (bool, int, interface{}) {
+		return len(path) == 1, len(path) - 1, nil
+	}
+
+	collectCallback(2, Chain(cb, Logging(logf)))
+	correct := []string{
+		"powerset: node {}",
+		"powerset: node {0 false} terminated to 0",
+		"powerset: node {0 true} terminated to 0",
+	}
+	if !reflect.DeepEqual(lines, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", strings.Join(lines, "\n"), strings.Join(correct, "\n"))
+	}
+}
+
+func TestMemoize(t *testing.T) {
+	calls := 0
+	cb := func(path Path, isLeaf bool, state interface{}, out chan<- interface{}) (bool, int, interface{}) {
+		calls++
+		return leafWriter(path, isLeaf, state, out)
+	}
+	depth := Memoize(func(path Path, isLeaf bool, state interface{}) interface{} {
+		return len(path)
+	})
+
+	leaves := collectCallback(3, Chain(cb, depth))
+	if calls != 4 {
+		t.Fatalf("expected one call per depth, got %d", calls)
+	}
+	if len(leaves) != 1 {
+		t.Fatalf("expected only the first leaf to be written, got %v", leaves)
+	}
+}
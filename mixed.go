@@ -0,0 +1,198 @@
+package powerset
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DomainKind is the kind of values a position of a MixedProduct takes
+type DomainKind int
+
+const (
+	// exactly one of the domain's values
+	DomainChoice DomainKind = iota
+
+	// the domain's name, or nothing
+	DomainFlag
+
+	// any subset of the domain's values, within its size bounds
+	DomainSubset
+)
+
+// Domain is one position of a MixedProduct.  Choice, Flag and SubPowerset make the three kinds
+type Domain struct {
+	Name   string
+	Kind   DomainKind
+	Values []string
+
+	// the size bounds of a DomainSubset, with the same meaning as a Config's
+	MinSize int
+	MaxSize int
+}
+
+// Choice is a domain that takes exactly one of values
+func Choice(name string, values ...string) Domain {
+	return Domain{Name: name, Kind: DomainChoice, Values: values}
+}
+
+// Flag is a domain that is either on or off
+func Flag(name string) Domain {
+	return Domain{Name: name, Kind: DomainFlag}
+}
+
+// SubPowerset is a domain that takes any subset of values with between minSize and maxSize of them, where a maxSize
+// of zero means no upper bound
+func SubPowerset(name string, values []string, minSize int, maxSize int) Domain {
+	return Domain{Name: name, Kind: DomainSubset, Values: values, MinSize: minSize, MaxSize: maxSize}
+}
+
+// Product is the configuration space of a MixedProduct, encoded as a single family.  each value of each domain is an
+// item of Items: a flag is named after its domain, and the values of the other domains are named "domain=value".
+// constraints across domains can then be written with ParseConstraint over Items, like `"tls" -> "cipher=aes"`, and
+// the whole space is searched with a single Family, with all of its options
+type Product struct {
+	Domains []Domain
+	Items   *Items
+
+	// the index of the first item of each domain
+	offsets []int
+}
+
+// Assignment is a point of a Product's space.  assignment[i] is what domain i took: the chosen value of a choice,
+// the name of a flag that is on, or nothing if it's off, and the chosen values of a sub-powerset, in order
+type Assignment [][]string
+
+// MixedProduct combines domains into a single configuration space, which is the product of finite choices, optional
+// flags and sub-powersets
+func MixedProduct(domains []Domain) (*Product, error) {
+	p := &Product{Domains: domains, Items: &Items{index: map[string]int{}}}
+	for _, d := range domains {
+		p.offsets = append(p.offsets, p.Items.Len())
+		switch d.Kind {
+		case DomainChoice:
+			if len(d.Values) == 0 {
+				return nil, fmt.Errorf("powerset: choice %q has no values", d.Name)
+			}
+		case DomainFlag:
+			if _, err := p.Items.Add(d.Name); err != nil {
+				return nil, err
+			}
+			continue
+		case DomainSubset:
+			if d.MinSize < 0 || d.MaxSize < 0 || (d.MaxSize > 0 && d.MinSize > d.MaxSize) {
+				return nil, fmt.Errorf("powerset: sub-powerset %q has bad size bounds [%d, %d]", d.Name, d.MinSize,
+					d.MaxSize)
+			}
+		default:
+			return nil, fmt.Errorf("powerset: domain %q has unknown kind %d", d.Name, d.Kind)
+		}
+		for _, v := range d.Values {
+			if _, err := p.Items.Add(d.Name + "=" + v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return p, nil
+}
+
+// Config returns the Config of the product's space, with the rules of its domains, and constraints added
+func (p *Product) Config(constraints ...Constraint) (Config, error) {
+	cfg := Config{LenItems: p.Items.Len()}
+	for i, d := range p.Domains {
+		offset := p.offsets[i]
+		switch d.Kind {
+		case DomainChoice:
+			// at least one value, and no two
+			terms := []string{}
+			some := make([]string, len(d.Values))
+			for a := range d.Values {
+				some[a] = fmt.Sprint(offset + a)
+				for b := a + 1; b < len(d.Values); b++ {
+					terms = append(terms, fmt.Sprintf("!(%d & %d)", offset+a, offset+b))
+				}
+			}
+			terms = append([]string{"(" + strings.Join(some, " | ") + ")"}, terms...)
+			c, err := ParseConstraint(strings.Join(terms, " & "), nil)
+			if err != nil {
+				return Config{}, err
+			}
+			c.Name = "one " + d.Name
+			cfg.Constraints = append(cfg.Constraints, c)
+
+		case DomainSubset:
+			if d.MinSize == 0 && d.MaxSize == 0 {
+				continue
+			}
+			lo, hi, end := d.MinSize, d.MaxSize, offset+len(d.Values)
+			cfg.Constraints = append(cfg.Constraints, Constraint{
+				Name: "size of " + d.Name,
+				Allow: func(indices []int) bool {
+					size := 0
+					for _, idx := range indices {
+						if idx >= offset && idx < end {
+							size++
+						}
+					}
+					return size >= lo && (hi == 0 || size <= hi)
+				},
+			})
+		}
+	}
+	cfg.Constraints = append(cfg.Constraints, constraints...)
+	return cfg, cfg.Validate()
+}
+
+// Decode converts a member of the product's space, as sorted included indices, to what each domain took
+func (p *Product) Decode(indices []int) Assignment {
+	assignment := make(Assignment, len(p.Domains))
+	d := 0
+	for _, idx := range indices {
+		for d+1 < len(p.Domains) && idx >= p.offsets[d+1] {
+			d++
+		}
+		value := p.Items.Name(idx)
+		if p.Domains[d].Kind != DomainFlag {
+			value = p.Domains[d].Values[idx-p.offsets[d]]
+		}
+		assignment[d] = append(assignment[d], value)
+	}
+	return assignment
+}
+
+// Generate generates every point of the product's space that satisfies constraints, in the order of Family.
+// Generate understands the same options as Family, so the order can be changed with WithOrder, and so on
+func (p *Product) Generate(constraints []Constraint, opts ...Option) (<-chan Assignment, func(), error) {
+	cfg, err := p.Config(constraints...)
+	if err != nil {
+		return nil, nil, err
+	}
+	in, stopIn, err := Family(cfg, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := make(chan Assignment)
+	done := make(chan bool)
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		defer close(out)
+		defer wg.Done()
+
+		for indices := range in {
+			select {
+			case <-done:
+				return
+			case out <- p.Decode(indices):
+			}
+		}
+	}()
+
+	stop := func() {
+		stopIn()
+		close(done)
+		wg.Wait()
+	}
+	return out, stop, nil
+}
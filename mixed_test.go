@@ -0,0 +1,97 @@
+package powerset
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func collectProduct(t *testing.T, p *Product, constraints []Constraint, opts ...Option) []Assignment {
+	out, stop, err := p.Generate(constraints, opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer stop()
+
+	points := []Assignment{}
+	for a := range out {
+		points = append(points, a)
+	}
+	return points
+}
+
+func TestMixedProduct(t *testing.T) {
+	p, err := MixedProduct([]Domain{
+		Choice("level", "low", "high"),
+		Flag("tls"),
+		SubPowerset("codec", []string{"gzip", "br", "zstd"}, 1, 2),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	points := collectProduct(t, p, nil)
+	// 2 levels, 2 tls settings, and 6 codec sets of one or two
+	if len(points) != 24 {
+		t.Fatalf("expected 24 points, got %d: %v", len(points), points)
+	}
+	seen := map[string]bool{}
+	for _, a := range points {
+		if len(a[0]) != 1 || len(a[1]) > 1 || len(a[2]) < 1 || len(a[2]) > 2 {
+			t.Fatalf("bad point %v", a)
+		}
+		key := ""
+		for _, values := range a {
+			key += "|" + strings.Join(values, ",")
+		}
+		if seen[key] {
+			t.Fatalf("duplicate point %v", a)
+		}
+		seen[key] = true
+	}
+}
+
+func TestMixedProductConstraints(t *testing.T) {
+	p, err := MixedProduct([]Domain{
+		Flag("tls"),
+		Choice("cipher", "none", "aes"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c := mustParse(t, `tls <-> "cipher=aes"`, p.Items)
+	points := collectProduct(t, p, []Constraint{c})
+	correct := []Assignment{
+		{nil, {"none"}},
+		{{"tls"}, {"aes"}},
+	}
+	if !reflect.DeepEqual(points, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", points, correct)
+	}
+}
+
+func TestMixedProductDecode(t *testing.T) {
+	p, _ := MixedProduct([]Domain{
+		SubPowerset("a", []string{"x", "y"}, 0, 0),
+		Flag("b"),
+		Choice("c", "p", "q", "r"),
+	})
+	got := p.Decode([]int{0, 1, 4})
+	correct := Assignment{{"x", "y"}, nil, {"q"}}
+	if !reflect.DeepEqual(got, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", got, correct)
+	}
+}
+
+func TestMixedProductInvalid(t *testing.T) {
+	for _, domains := range [][]Domain{
+		{Choice("a")},
+		{Flag("a"), Flag("a")},
+		{SubPowerset("a", []string{"x"}, 2, 1)},
+	} {
+		if _, err := MixedProduct(domains); err == nil {
+			t.Fatalf("expected an error for %v", domains)
+		}
+	}
+}
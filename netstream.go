@@ -0,0 +1,230 @@
+package powerset
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"sync"
+	"time"
+)
+
+// the types of StreamFrame
+const (
+	FrameSolution  = "solution"
+	FrameHeartbeat = "heartbeat"
+	FrameDone      = "done"
+)
+
+// StreamFrame is one JSON message that a StreamSink writes to its clients.  a solution frame carries a subset and its
+// rank, as a decimal string since ranks outgrow JSON numbers, a heartbeat frame is sent whenever the stream has been
+// idle for the heartbeat interval, and a done frame ends the stream.  Emitted is the number of solutions the search had
+// produced when the frame was written
+type StreamFrame struct {
+	Type    string    `json:"type"`
+	Rank    string    `json:"rank,omitempty"`
+	Subset  []int     `json:"subset,omitempty"`
+	Emitted int       `json:"emitted"`
+	Time    time.Time `json:"time"`
+}
+
+// streamHello is the first message a client sends, with the rank of the last solution it received, or nothing to
+// start from the beginning
+type streamHello struct {
+	After string `json:"after"`
+}
+
+type streamed struct {
+	rank   string
+	subset []int
+}
+
+// StreamSink streams the solutions of a search to network clients as JSON, so detached monitoring tools can attach to
+// a search that's already running.  it's a Sink: every subset added is kept, in order, and sent to every client that
+// is attached, and a client that attaches later, or reconnects after losing its connection, is first sent every
+// solution after the one it last received, using solutions' ranks as the cursor.  since the whole history is kept for
+// clients that resume, the sink holds every solution in memory
+type StreamSink struct {
+	lenItems  int
+	heartbeat time.Duration
+
+	mu      sync.Mutex
+	history []streamed
+	index   map[string]int
+	closed  bool
+
+	// closed and replaced whenever the history grows or the sink is closed, to wake up the clients
+	changed chan struct{}
+}
+
+// NewStreamSink creates a sink for the subsets of lenItems items, whose clients are sent a heartbeat frame whenever
+// heartbeat passes without a solution
+func NewStreamSink(lenItems int, heartbeat time.Duration) *StreamSink {
+	return &StreamSink{
+		lenItems:  lenItems,
+		heartbeat: heartbeat,
+		index:     map[string]int{},
+		changed:   make(chan struct{}),
+	}
+}
+
+// Add streams a solution to the attached clients
+func (s *StreamSink) Add(subset []int) {
+	rank := Rank(subset, s.lenItems).String()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.index[rank] = len(s.history)
+	s.history = append(s.history, streamed{rank: rank, subset: append([]int{}, subset...)})
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// Close marks the end of the search, so every client is sent a done frame once it has every solution
+func (s *StreamSink) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.closed {
+		s.closed = true
+		close(s.changed)
+		s.changed = make(chan struct{})
+	}
+}
+
+// Listen serves every connection accepted from l, until l is closed
+func (s *StreamSink) Listen(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go s.Serve(conn)
+	}
+}
+
+// Serve streams to a single client over conn, until the stream is done or the connection fails, and closes conn
+func (s *StreamSink) Serve(conn net.Conn) error {
+	defer conn.Close()
+
+	var hello streamHello
+	if err := json.NewDecoder(conn).Decode(&hello); err != nil {
+		return fmt.Errorf("powerset: bad stream hello: %w", err)
+	}
+	s.mu.Lock()
+	pos := 0
+	if hello.After != "" {
+		if i, ok := s.index[hello.After]; ok {
+			pos = i + 1
+		}
+	}
+	s.mu.Unlock()
+
+	enc := json.NewEncoder(conn)
+	timer := time.NewTimer(s.heartbeat)
+	defer timer.Stop()
+	for {
+		s.mu.Lock()
+		batch, closed, changed, emitted := s.history[pos:], s.closed, s.changed, len(s.history)
+		s.mu.Unlock()
+
+		for i, sol := range batch {
+			frame := StreamFrame{Type: FrameSolution, Rank: sol.rank, Subset: sol.subset, Emitted: pos + i + 1,
+				Time: time.Now()}
+			if err := enc.Encode(frame); err != nil {
+				return err
+			}
+		}
+		pos += len(batch)
+		if closed {
+			return enc.Encode(StreamFrame{Type: FrameDone, Emitted: emitted, Time: time.Now()})
+		}
+
+		if len(batch) > 0 {
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(s.heartbeat)
+		}
+		select {
+		case <-changed:
+		case <-timer.C:
+			if err := enc.Encode(StreamFrame{Type: FrameHeartbeat, Emitted: emitted, Time: time.Now()}); err != nil {
+				return err
+			}
+			timer.Reset(s.heartbeat)
+		}
+	}
+}
+
+// FollowStream attaches to a StreamSink with dial and calls handle with every solution, in order, until the stream is
+// done, handle returns an error, or ctx is done.  a connection that fails, or that's silent for longer than timeout,
+// which should be a few of the sink's heartbeat intervals, is redialed after a short pause, and resumes after the last
+// solution handle was called with, so no solution is handled twice or skipped
+func FollowStream(ctx context.Context, dial func() (net.Conn, error), timeout time.Duration,
+	handle func(rank *big.Int, subset []int) error) error {
+
+	cursor := ""
+	pause := 10 * time.Millisecond
+	for {
+		done, err := followOnce(ctx, dial, timeout, &cursor, handle)
+		if done || ctx.Err() != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pause):
+		}
+		if pause < time.Second {
+			pause *= 2
+		}
+	}
+}
+
+// followOnce follows a single connection, returning true when there's nothing to reconnect for: the stream is done,
+// or handle returned an error
+func followOnce(ctx context.Context, dial func() (net.Conn, error), timeout time.Duration, cursor *string,
+	handle func(rank *big.Int, subset []int) error) (bool, error) {
+
+	conn, err := dial()
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if err := json.NewEncoder(conn).Encode(streamHello{After: *cursor}); err != nil {
+		return false, err
+	}
+	dec := json.NewDecoder(conn)
+	for {
+		conn.SetReadDeadline(time.Now().Add(timeout))
+		var frame StreamFrame
+		if err := dec.Decode(&frame); err != nil {
+			return false, err
+		}
+
+		switch frame.Type {
+		case FrameDone:
+			return true, nil
+		case FrameSolution:
+			rank, ok := new(big.Int).SetString(frame.Rank, 10)
+			if !ok {
+				return true, fmt.Errorf("powerset: bad rank %q in stream", frame.Rank)
+			}
+			if err := handle(rank, frame.Subset); err != nil {
+				return true, err
+			}
+			*cursor = frame.Rank
+		}
+	}
+}
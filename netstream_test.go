@@ -0,0 +1,102 @@
+package powerset
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func listenStream(t *testing.T, sink *StreamSink) net.Listener {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("can't listen: %v", err)
+	}
+	go sink.Listen(l)
+	t.Cleanup(func() { l.Close() })
+	return l
+}
+
+func TestStreamSinkResume(t *testing.T) {
+	sink := NewStreamSink(4, 20*time.Millisecond)
+	l := listenStream(t, sink)
+	out, _, _ := Family(Config{LenItems: 4, MinSize: 2})
+	Drain(out, sink)
+
+	var mu sync.Mutex
+	var conns []net.Conn
+	dial := func() (net.Conn, error) {
+		conn, err := net.Dial("tcp", l.Addr().String())
+		mu.Lock()
+		conns = append(conns, conn)
+		mu.Unlock()
+		return conn, err
+	}
+
+	got := [][]int{}
+	done := make(chan error)
+	go func() {
+		done <- FollowStream(context.Background(), dial, time.Second, func(rank *big.Int, subset []int) error {
+			if rank.Cmp(Rank(subset, 4)) != 0 {
+				t.Errorf("%v has rank %v", subset, rank)
+			}
+			got = append(got, subset)
+			// drop the connection once, after the second solution
+			if len(got) == 2 {
+				mu.Lock()
+				conns[0].Close()
+				mu.Unlock()
+			}
+			return nil
+		})
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	sink.Add([]int{0})
+	sink.Close()
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	correct := collectFamily(t, Config{LenItems: 4, MinSize: 2})
+	correct = append(correct, []int{0})
+	if !reflect.DeepEqual(got, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", got, correct)
+	}
+	if len(conns) < 2 {
+		t.Fatalf("expected a reconnect, got %d connections", len(conns))
+	}
+}
+
+func TestStreamSinkHeartbeat(t *testing.T) {
+	sink := NewStreamSink(3, 5*time.Millisecond)
+	l := listenStream(t, sink)
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer conn.Close()
+	json.NewEncoder(conn).Encode(streamHello{})
+
+	dec := json.NewDecoder(conn)
+	var frame StreamFrame
+	if err := dec.Decode(&frame); err != nil || frame.Type != FrameHeartbeat {
+		t.Fatalf("expected a heartbeat, got %+v, %v", frame, err)
+	}
+
+	sink.Add([]int{1, 2})
+	for frame.Type == FrameHeartbeat {
+		if err := dec.Decode(&frame); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	correct := StreamFrame{Type: FrameSolution, Rank: "3", Subset: []int{1, 2}, Emitted: 1, Time: frame.Time}
+	if !reflect.DeepEqual(frame, correct) {
+		t.Fatalf("\n%+v\n\n!=\n\n%+v", frame, correct)
+	}
+}
@@ -0,0 +1,129 @@
+package powerset
+
+// FirstInFamily returns the first member of the family described by cfg, in the order of Family, or false if the
+// family is empty, cfg is invalid or its Order isn't OrderCanonical
+func FirstInFamily(cfg Config) ([]int, bool) {
+	if cfg.canonicalOnly("FirstInFamily") != nil {
+		return nil, false
+	}
+	fam, err := cfg.compile()
+	if err != nil || fam.empty {
+		return nil, false
+	}
+	in, ok := fam.complete(make([]bool, fam.lenItems), 0, 0)
+	if !ok {
+		return nil, false
+	}
+	return fam.nextMember(in, false)
+}
+
+// NextInFamily returns the member of the family described by cfg that comes after cur in the order of Family, or
+// false if cur is the last, so a family can be iterated without keeping a generator running between steps.  cur may be
+// in any order, and doesn't have to be a member itself.  the size bounds and the required and forbidden indices are
+// solved directly, jumping straight to the next subset that satisfies them, and Constraints are then checked on each
+// of those in turn.  false is also returned when cfg is invalid, its Order isn't OrderCanonical or cur isn't a subset
+// of its items
+func NextInFamily(cfg Config, cur []int) ([]int, bool) {
+	if cfg.canonicalOnly("NextInFamily") != nil {
+		return nil, false
+	}
+	fam, err := cfg.compile()
+	if err != nil || fam.empty {
+		return nil, false
+	}
+	sorted, err := sortSubset(fam.lenItems, cur)
+	if err != nil {
+		return nil, false
+	}
+
+	in := make([]bool, fam.lenItems)
+	for _, idx := range sorted {
+		in[idx] = true
+	}
+	return fam.nextMember(in, true)
+}
+
+// nextMember returns the first member of the family at or after in, or strictly after it if after is true.  in is
+// overwritten
+func (fam *family) nextMember(in []bool, after bool) ([]int, bool) {
+	for {
+		if after {
+			next, ok := fam.nextByRules(in)
+			if !ok {
+				return nil, false
+			}
+			in = next
+		}
+		after = true
+
+		indices := []int{}
+		for idx, isIn := range in {
+			if isIn {
+				indices = append(indices, idx)
+			}
+		}
+		if ok, _ := fam.contains(indices); ok {
+			return indices, true
+		}
+	}
+}
+
+// nextByRules returns the first subset after in that satisfies the size bounds and the required and forbidden
+// indices.  in the order of Family, a subset's successors keep a prefix of it, then include an index it excludes, then
+// continue as early as possible.  keeping a longer prefix comes sooner, so the last excluded index whose prefix can
+// still be completed is the one to include
+func (fam *family) nextByRules(in []bool) ([]bool, bool) {
+	// how long a prefix of in breaks none of the rules, and how many indices it includes
+	valid, counts := 0, make([]int, fam.lenItems+1)
+	for valid < fam.lenItems {
+		m := fam.members[valid]
+		if (m == required && !in[valid]) || (m == forbidden && in[valid]) {
+			break
+		}
+		counts[valid+1] = counts[valid]
+		if in[valid] {
+			counts[valid+1]++
+		}
+		valid++
+	}
+
+	for p := fam.lenItems - 1; p >= 0; p-- {
+		if p > valid || in[p] || fam.members[p] == forbidden || counts[p]+1 > fam.maxSize {
+			continue
+		}
+		next := make([]bool, fam.lenItems)
+		copy(next, in[:p])
+		next[p] = true
+		if next, ok := fam.complete(next, p+1, counts[p]+1); ok {
+			return next, true
+		}
+	}
+	return nil, false
+}
+
+// complete decides the indices from `from` on, for the earliest subset with the first indices of in that satisfies
+// the rules.  that subset includes the required indices, and as few others as MinSize allows, as late as possible.
+// count is how many of the first indices are included
+func (fam *family) complete(in []bool, from int, count int) ([]bool, bool) {
+	count += fam.requiredFrom[from]
+	if count > fam.maxSize {
+		return nil, false
+	}
+	need := fam.minSize - count
+	if need > fam.availFrom[from]-fam.requiredFrom[from] {
+		return nil, false
+	}
+
+	for idx := fam.lenItems - 1; idx >= from; idx-- {
+		switch fam.members[idx] {
+		case required:
+			in[idx] = true
+		case free:
+			in[idx] = need > 0
+			need--
+		default:
+			in[idx] = false
+		}
+	}
+	return in, true
+}
@@ -0,0 +1,117 @@
+package powerset
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// iterates a family with FirstInFamily and NextInFamily
+func iterateFamily(cfg Config) [][]int {
+	all := [][]int{}
+	cur, ok := FirstInFamily(cfg)
+	for ok {
+		all = append(all, cur)
+		cur, ok = NextInFamily(cfg, cur)
+	}
+	return all
+}
+
+func TestNextInFamily(t *testing.T) {
+	evenSum := Constraint{
+		Name: "even sum",
+		Allow: func(indices []int) bool {
+			sum := 0
+			for _, idx := range indices {
+				sum += idx
+			}
+			return sum%2 == 0
+		},
+	}
+	parsed, _ := ParseConstraint("1 -> !2", nil)
+
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		lenItems := rng.Intn(7)
+		cfg := Config{LenItems: lenItems}
+		if lenItems > 0 {
+			cfg.MinSize = rng.Intn(lenItems + 1)
+			cfg.MaxSize = rng.Intn(lenItems + 1)
+			if cfg.MaxSize > 0 && cfg.MaxSize < cfg.MinSize {
+				cfg.MinSize, cfg.MaxSize = cfg.MaxSize, cfg.MinSize
+			}
+			for idx := 0; idx < lenItems; idx++ {
+				switch rng.Intn(6) {
+				case 0:
+					cfg.Required = append(cfg.Required, idx)
+				case 1:
+					cfg.Forbidden = append(cfg.Forbidden, idx)
+				}
+			}
+		}
+		if rng.Intn(2) == 0 {
+			cfg.Constraints = append(cfg.Constraints, evenSum)
+		}
+		if lenItems > 2 && rng.Intn(2) == 0 {
+			cfg.Constraints = append(cfg.Constraints, parsed)
+		}
+
+		all := iterateFamily(cfg)
+		if correct := collectFamily(t, cfg); !reflect.DeepEqual(all, correct) {
+			t.Fatalf("%+v: \n%v\n\n!=\n\n%v", cfg, all, correct)
+		}
+	}
+}
+
+func TestNextInFamilyNotMember(t *testing.T) {
+	cfg := Config{LenItems: 4, MinSize: 2, MaxSize: 2}
+
+	// {1, 2, 3} isn't a member, and the first one after it has 0 and 3
+	next, ok := NextInFamily(cfg, []int{3, 2, 1})
+	if !ok || !reflect.DeepEqual(next, []int{0, 3}) {
+		t.Fatalf("unexpected next member %v, %v", next, ok)
+	}
+	if _, ok := NextInFamily(cfg, []int{0, 1}); ok {
+		t.Fatalf("expected {0, 1} to be the last member")
+	}
+	if _, ok := NextInFamily(cfg, []int{4}); ok {
+		t.Fatalf("expected an out of range subset to have no next member")
+	}
+	if _, ok := FirstInFamily(Config{LenItems: 2, Required: []int{0}, Forbidden: []int{0}}); ok {
+		t.Fatalf("expected an empty family to have no first member")
+	}
+}
+
+func TestNextInFamilyHuge(t *testing.T) {
+	// the first few members of a family far too large to walk up to
+	cfg := Config{LenItems: 200, MinSize: 199, Required: []int{0}}
+	first, ok := FirstInFamily(cfg)
+	if !ok || len(first) != 199 || first[0] != 0 || first[1] != 2 {
+		t.Fatalf("unexpected first member %v", first)
+	}
+	second, ok := NextInFamily(cfg, first)
+	if !ok || len(second) != 199 || second[1] != 1 || second[2] != 3 {
+		t.Fatalf("unexpected second member %v", second)
+	}
+}
+
+func TestInFamilyShardAndOrder(t *testing.T) {
+	cfg := Config{LenItems: 4, Shard: &ShardSpec{Count: 4, Index: 1}}
+	if first, ok := FirstInFamily(cfg); !ok || !reflect.DeepEqual(first, []int{1}) {
+		t.Fatalf("unexpected first member %v, %v", first, ok)
+	}
+	if next, ok := NextInFamily(cfg, []int{1, 2}); !ok || !reflect.DeepEqual(next, []int{1, 2, 3}) {
+		t.Fatalf("unexpected next member %v, %v", next, ok)
+	}
+	if next, ok := NextInFamily(cfg, []int{1, 2, 3}); ok {
+		t.Fatalf("the last member of the shard has no successor, got %v", next)
+	}
+
+	cfg.Order = OrderGray
+	if _, ok := FirstInFamily(cfg); ok {
+		t.Fatalf("FirstInFamily should reject OrderGray")
+	}
+	if _, ok := NextInFamily(cfg, []int{1}); ok {
+		t.Fatalf("NextInFamily should reject OrderGray")
+	}
+}
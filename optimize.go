@@ -0,0 +1,85 @@
+package powerset
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrNoSolution is returned by Optimize when the family is empty and no incumbent was given, and by SampleFamily when
+// the family is empty
+var ErrNoSolution = errors.New("powerset: no subset in the family")
+
+// Scored is a subset along with its score
+type Scored struct {
+	Indices []int
+	Score   float64
+}
+
+// WithIncumbent starts Optimize from a known subset and its score, for example the result of a heuristic search from
+// the localsearch package.  branch-and-bound prunes every subtree whose bound can't beat the incumbent, so a strong
+// incumbent from the start skips far more of the tree than discovering one during the search.  the incumbent is
+// trusted to be a member of the family and to have the given score
+func WithIncumbent(indices []int, score float64) Option {
+	return func(o *options) {
+		o.incumbent = &Scored{Indices: append([]int{}, indices...), Score: score}
+	}
+}
+
+// Optimize finds the subset of the family described by cfg with the largest score, using branch-and-bound.  give it a
+// bound with WithObjectiveBounds to prune subtrees whose bound is no better than the best subset found so far, and a
+// starting point with WithIncumbent.  if nothing in the family beats the incumbent, the incumbent is returned.  ties
+// are won by the subset found first in canonical order, so cfg's Order must be OrderCanonical.  if the search is
+// cancelled through WithContext, the best subset found so far is returned along with the context's error
+func Optimize(cfg Config, score func([]int) float64, opts ...Option) (Scored, error) {
+	if err := cfg.canonicalOnly("Optimize"); err != nil {
+		return Scored{}, err
+	}
+	fam, err := cfg.compile()
+	if err != nil {
+		return Scored{}, err
+	}
+	o := buildOptions(opts)
+
+	found := false
+	best := Scored{Score: math.Inf(-1)}
+	if o.incumbent != nil {
+		found = true
+		best = *o.incumbent
+	}
+
+	// whether the walk gave up on part of the tree because the context was cancelled
+	cancelled := false
+	visit := func(indices []int) bool {
+		if o.cancelled() {
+			cancelled = true
+			return false
+		}
+		if s := score(indices); !found || s > best.Score {
+			found = true
+			best = Scored{Indices: append([]int{}, indices...), Score: s}
+		}
+		return true
+	}
+
+	var bound func([]int, int) float64
+	if len(o.objectiveBounds) > 0 {
+		bound = o.objectiveBounds[0]
+	}
+	prune := func(included []int, next int) bool {
+		if o.cancelled() {
+			cancelled = true
+			return true
+		}
+		return bound != nil && found && bound(included, next) <= best.Score
+	}
+
+	// a context cancelled once the walk is over doesn't make its result any less optimal
+	if !fam.walk(visit, prune) || cancelled {
+		return best, o.ctx.Err()
+	}
+
+	if !found {
+		return Scored{}, ErrNoSolution
+	}
+	return best, nil
+}
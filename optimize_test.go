@@ -0,0 +1,125 @@
+package powerset
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var optimizeValues = []float64{5, -2, 3, -1, 4, 2}
+
+func optimizeScore(indices []int) float64 {
+	total := 0.0
+	for _, idx := range indices {
+		total += optimizeValues[idx]
+	}
+	return total
+}
+
+// the best reachable score adds every positive value that hasn't been decided yet
+func optimizeBound(calls *int) func([]int, int) float64 {
+	return func(included []int, next int) float64 {
+		*calls++
+		total := optimizeScore(included)
+		for idx := next; idx < len(optimizeValues); idx++ {
+			if optimizeValues[idx] > 0 {
+				total += optimizeValues[idx]
+			}
+		}
+		return total
+	}
+}
+
+func TestOptimize(t *testing.T) {
+	best, err := Optimize(Config{LenItems: len(optimizeValues)}, optimizeScore)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	correct := Scored{Indices: []int{0, 2, 4, 5}, Score: 14}
+	if !reflect.DeepEqual(correct, best) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", best, correct)
+	}
+}
+
+func TestOptimizeConstrained(t *testing.T) {
+	best, _ := Optimize(Config{LenItems: len(optimizeValues), MaxSize: 2}, optimizeScore)
+
+	correct := Scored{Indices: []int{0, 4}, Score: 9}
+	if !reflect.DeepEqual(correct, best) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", best, correct)
+	}
+}
+
+func TestOptimizeIncumbentPrunes(t *testing.T) {
+	cfg := Config{LenItems: len(optimizeValues)}
+
+	cold := 0
+	coldBest, _ := Optimize(cfg, optimizeScore, WithObjectiveBounds(optimizeBound(&cold)))
+
+	warm := 0
+	warmBest, _ := Optimize(cfg, optimizeScore, WithObjectiveBounds(optimizeBound(&warm)),
+		WithIncumbent([]int{0, 2, 4, 5}, 14))
+
+	if coldBest.Score != warmBest.Score {
+		t.Fatalf("warm start changed the optimum: %v != %v", warmBest, coldBest)
+	}
+	if warm >= cold {
+		t.Fatalf("expected a warm start to evaluate fewer bounds, %d >= %d", warm, cold)
+	}
+}
+
+func TestOptimizeIncumbentKept(t *testing.T) {
+	best, _ := Optimize(Config{LenItems: 3, MaxSize: 1}, optimizeScore, WithIncumbent([]int{0, 2, 4}, 100))
+	if best.Score != 100 {
+		t.Fatalf("expected the incumbent to win, got %v", best)
+	}
+}
+
+func TestOptimizeEmpty(t *testing.T) {
+	_, err := Optimize(Config{LenItems: 2, MinSize: 3}, optimizeScore)
+	if err != ErrNoSolution {
+		t.Fatalf("expected ErrNoSolution, got %v", err)
+	}
+}
+
+// a search that finishes before it sees its context cancelled returns its optimal result, and one that doesn't returns
+// the context's error
+func TestOptimizeCancelledAfterWalk(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	leaves := 0
+	score := func(indices []int) float64 {
+		if leaves++; leaves == 1<<len(optimizeValues) {
+			cancel()
+		}
+		return optimizeScore(indices)
+	}
+	best, err := Optimize(Config{LenItems: len(optimizeValues)}, score, WithContext(ctx))
+	if err != nil || best.Score != 14 {
+		t.Fatalf("expected the optimum without an error, got %v, %v", best, err)
+	}
+
+	if _, err := Optimize(Config{LenItems: len(optimizeValues)}, optimizeScore, WithContext(ctx)); !errors.Is(err,
+		context.Canceled) {
+
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestOptimizeShardAndOrder(t *testing.T) {
+	// the first of 2 shards excludes index 0, so the best subset of the whole family isn't in it
+	cfg := Config{LenItems: len(optimizeValues), Shard: &ShardSpec{Count: 2, Index: 0}}
+	best, err := Optimize(cfg, optimizeScore)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(best.Indices, []int{2, 4, 5}) || best.Score != 9 {
+		t.Fatalf("unexpected best %+v", best)
+	}
+
+	cfg.Order = OrderGray
+	if _, err := Optimize(cfg, optimizeScore); err == nil {
+		t.Fatalf("expected an error for OrderGray")
+	}
+}
@@ -0,0 +1,182 @@
+package powerset
+
+import (
+	"context"
+	"math/big"
+	"time"
+)
+
+// Option configures the optional behavior of a generator.  each generator documents the options it understands, and
+// ignores the rest
+type Option func(*options)
+
+type options struct {
+	objectiveBounds []func(included []int, next int) float64
+	profileLabel    string
+	incumbent       *Scored
+	ctx             context.Context
+
+	bloomExpected uint64
+	bloomFPRate   float64
+	canonicalize  func([]int) []int
+	rateLimit     float64
+	background    bool
+	sizeStats     bool
+	timing        bool
+	quotaPerSize  int
+	maxSolutions  int
+	minSize       int
+	maxSize       int
+	startRank     *big.Int
+
+	solutionDeadline time.Duration
+
+	maxDepth    int
+	frontier    chan<- Path
+	prefixCache *prefixCache
+
+	order     Order
+	lookahead int
+
+	ringSize     int
+	transport    Transport
+	ownership    Ownership
+	memoryBudget uint64
+
+	branchBuffer int
+	slowConsumer SlowConsumerPolicy
+
+	solver Solver
+
+	score          func([]int) float64
+	scoreReservoir int
+
+	targetSum  *targetSum
+	autoEngine bool
+	adaptive   *adaptivePruning
+
+	journalPath     string
+	interchangeable [][]int
+
+	retries    int
+	backoff    time.Duration
+	maxBackoff time.Duration
+}
+
+// WithContext makes a search give up when ctx is cancelled or its deadline passes
+func WithContext(ctx context.Context) Option {
+	return func(o *options) {
+		o.ctx = ctx
+	}
+}
+
+// WithBloomDedup suppresses subsets that have already been emitted, using a Bloom filter sized for the expected number
+// of distinct subsets at the false positive rate fpRate.  this is for searches whose branches can emit the same subset
+// more than once, like after canonicalization, when the output is too large to remember exactly.  memory is bounded by
+// the size of the filter, at the cost of a false positive occasionally suppressing a subset that was never emitted, at
+// roughly fpRate once the filter holds expected subsets
+func WithBloomDedup(expected uint64, fpRate float64) Option {
+	return func(o *options) {
+		o.bloomExpected = expected
+		o.bloomFPRate = fpRate
+	}
+}
+
+// WithCanonicalizer maps every subset to the representative of its equivalence class before it is deduplicated,
+// hashed or emitted.  users with domain specific equivalences, like rotations or reflections of board positions, can
+// return the same representative for every member of a class, and combined with WithBloomDedup each class is emitted
+// once.  fn receives a copy of the subset that it may modify, and its result is sorted before it is emitted
+func WithCanonicalizer(fn func([]int) []int) Option {
+	return func(o *options) {
+		o.canonicalize = fn
+	}
+}
+
+// WithRateLimit limits emissions to perSecond subsets per second, for enumerations that feed downstream APIs or queues
+// that would otherwise be overwhelmed.  subsets are never dropped: the producer blocks until it's allowed to emit the
+// next one, so the search itself slows to the rate.  a rate of zero or less means no limit
+func WithRateLimit(perSecond float64) Option {
+	return func(o *options) {
+		o.rateLimit = perSecond
+	}
+}
+
+// WithBackground runs the search at a lower priority, for embedding it in a latency sensitive service.  the search
+// periodically yields to the scheduler, and backs off with short sleeps whenever other goroutines were waiting to run,
+// so it degrades gracefully under load and runs at close to full speed when the program is otherwise idle
+func WithBackground() Option {
+	return func(o *options) {
+		o.background = true
+	}
+}
+
+// WithQuotaPerSize emits at most q subsets of each size, for a sample of the family that's balanced across sizes.
+// once a size has filled its quota, subtrees that can only contain subsets of full sizes aren't explored at all, and
+// the search ends when every size is full.  with WithCanonicalizer, the quota applies to the size of the canonical
+// subsets, and subtrees are no longer skipped, since canonicalizing can change a subset's size
+func WithQuotaPerSize(q int) Option {
+	return func(o *options) {
+		o.quotaPerSize = q
+	}
+}
+
+// WithMaxSolutions cleanly terminates a search after k results have been emitted, and closes its output channel,
+// which is simpler and less fragile than counting results on the consumer's side and calling stop.  in Callback mode,
+// every value the callback writes to its output channel counts as a result, and the callback isn't called again once
+// the k-th has been delivered
+func WithMaxSolutions(k int) Option {
+	return func(o *options) {
+		o.maxSolutions = k
+	}
+}
+
+// WithSolutionDeadline aborts a search if d passes without a new solution being emitted, counting from the start of
+// the search.  this is for interactive tools that want to detect that the rest of the search space is barren, rather
+// than waiting for it to be exhausted.  an aborted search closes its output channel and reports ErrSolutionDeadline
+// from its Control's Err.  time spent paused doesn't count against the deadline
+func WithSolutionDeadline(d time.Duration) Option {
+	return func(o *options) {
+		o.solutionDeadline = d
+	}
+}
+
+// WithMaxDepth stops Callback from descending below depth d, where the root is at depth 0.  the callback is still
+// called on the nodes at depth d, and each of them that it doesn't terminate is written to frontier as a partial Path
+// instead of being explored.  the frontier subtrees can then be handed to other machines, or explored with different
+// logic, and finished with FromFrontier.  Callback closes frontier when it finishes, and frontier must be read
+// concurrently with the output channel, since the traversal blocks on both
+func WithMaxDepth(d int, frontier chan<- Path) Option {
+	return func(o *options) {
+		o.maxDepth = d
+		o.frontier = frontier
+	}
+}
+
+// cancelled reports whether the search's context, if it has one, is done
+func (o *options) cancelled() bool {
+	if o.ctx == nil {
+		return false
+	}
+	select {
+	case <-o.ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+// done returns the Done channel of the search's context, or nil, which is never ready, if it has none
+func (o *options) done() <-chan struct{} {
+	if o.ctx == nil {
+		return nil
+	}
+	return o.ctx.Done()
+}
+
+func buildOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
@@ -0,0 +1,104 @@
+package powerset
+
+// Order is the order in which Start and Family emit the members of a family
+type Order int
+
+const (
+	// OrderCanonical is the default order, the same as FixedSize
+	OrderCanonical Order = iota
+
+	// OrderZigZag alternates between the smallest and the largest remaining subset sizes, emitting every subset of size
+	// 0, then of size n, then 1, then n-1 and so on, each size in canonical order.  consumers probing both extremes of
+	// the family see them early, instead of waiting for the canonical order to reach the large subsets
+	OrderZigZag
+
+	// OrderGray is the reflected Gray code order, in which consecutive subsets of the full powerset differ by a single
+	// index.  the members of a family are emitted in the same relative order, so they can differ by more when the
+	// family skips some subsets
+	OrderGray
+
+	// OrderLocality keeps consecutive subsets as similar as possible, for consumers whose cost of evaluating a subset
+	// is proportional to its symmetric difference from the previous one.  it reads ahead of the Gray code order by a
+	// bounded window, set with WithLookahead, and always emits the subset in the window closest to the previous one.
+	// where OrderGray jumps over the subsets a family skips, this usually finds a closer subset within the window
+	OrderLocality
+
+	// OrderLexicographic is the lexicographic order of the sorted index slices, {}, {0}, {0, 1}, {0, 1, 2} and so on,
+	// which tools outside of this package often expect.  only VariableSize supports it, and Start returns an error
+	OrderLexicographic
+
+	// OrderBySize is the banker's sequence, which emits every subset of size 0, then of size 1, then 2 and so on, each
+	// size in canonical order.  a search that ends at the first solution it finds ends at one of the smallest
+	OrderBySize
+
+	// OrderColex is the colexicographic order, which compares subsets by the largest index they differ in, so the
+	// subsets of the first k items all come before any subset including item k.  it's the order of most combinatorial
+	// ranking schemes, and the canonical order with the indices reversed.  only FixedSize and Combinations support it,
+	// and Start returns an error
+	OrderColex
+)
+
+// the default window of OrderLocality
+const defaultLookahead = 64
+
+// WithOrder makes Start and Family, or the generators that document it, emit subsets in the given order.  orders other
+// than OrderCanonical that group subsets by size walk the powerset tree once per size, pruned to that size, so they
+// visit more nodes in total
+func WithOrder(order Order) Option {
+	return func(o *options) {
+		o.order = order
+	}
+}
+
+// WithLookahead sets how many subsets OrderLocality reads ahead to find the one closest to the previous subset.  a
+// larger window finds closer subsets, at the cost of memory and a linear scan of the window for every subset
+func WithLookahead(window int) Option {
+	return func(o *options) {
+		o.lookahead = window
+	}
+}
+
+// a pass is one walk of the powerset tree in an order that takes several.  size is the only subset size the pass
+// emits, or -1 if it emits every size
+type pass struct {
+	fam  *family
+	size int
+}
+
+// passes returns the walks that emit the family in the given order
+func (fam *family) passes(order Order) []pass {
+	if (order != OrderZigZag && order != OrderBySize) || fam.empty {
+		return []pass{{fam, -1}}
+	}
+	if order == OrderBySize {
+		passes := make([]pass, 0, fam.lenItems+1)
+		for k := 0; k <= fam.lenItems; k++ {
+			passes = append(passes, pass{fam.withSize(k), k})
+		}
+		return passes
+	}
+
+	// sizes outside of the family's size range get an empty pass, so their subsets are still counted as pruned
+	lo, hi := 0, fam.lenItems
+	passes := make([]pass, 0, hi-lo+1)
+	for lo <= hi {
+		passes = append(passes, pass{fam.withSize(lo), lo})
+		if lo != hi {
+			passes = append(passes, pass{fam.withSize(hi), hi})
+		}
+		lo++
+		hi--
+	}
+	return passes
+}
+
+// withSize returns a copy of the family restricted to subsets of exactly size k
+func (fam *family) withSize(k int) *family {
+	restricted := *fam
+	restricted.minSize = k
+	restricted.maxSize = k
+	if lo, hi := fam.sizeRange(); k < lo || k > hi {
+		restricted.empty = true
+	}
+	return &restricted
+}
@@ -0,0 +1,229 @@
+package powerset
+
+import (
+	"math/big"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestOrderZigZag(t *testing.T) {
+	allValues := collectFamily(t, Config{LenItems: 3}, WithOrder(OrderZigZag))
+	correct := [][]int{
+		{},
+		{0, 1, 2},
+		{2},
+		{1},
+		{0},
+		{1, 2},
+		{0, 2},
+		{0, 1},
+	}
+	if !reflect.DeepEqual(allValues, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", allValues, correct)
+	}
+}
+
+func TestOrderZigZagBounds(t *testing.T) {
+	cfg := Config{LenItems: 5, MinSize: 1, MaxSize: 4, Required: []int{0}}
+	allValues := collectFamily(t, cfg, WithOrder(OrderZigZag))
+
+	sizes := []int{}
+	for _, indices := range allValues {
+		if len(sizes) == 0 || sizes[len(sizes)-1] != len(indices) {
+			sizes = append(sizes, len(indices))
+		}
+	}
+	if correct := []int{1, 4, 2, 3}; !reflect.DeepEqual(sizes, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", sizes, correct)
+	}
+	if canonical := collectFamily(t, cfg); len(allValues) != len(canonical) {
+		t.Fatalf("generated %d subsets, expected %d", len(allValues), len(canonical))
+	}
+}
+
+func TestOrderZigZagSizeStats(t *testing.T) {
+	out, ctl, _ := Start(Config{LenItems: 6, Forbidden: []int{2}}, WithOrder(OrderZigZag), WithSizeStats())
+	for range out {
+	}
+
+	for k, size := range ctl.Stats().BySize {
+		total := new(big.Int).Add(size.Pruned, new(big.Int).SetUint64(size.Emitted))
+		if total.Cmp(binomial(6, k)) != 0 {
+			t.Fatalf("size %d: emitted %d and pruned %v, expected C(6, %d) in total", k, size.Emitted, size.Pruned, k)
+		}
+	}
+}
+
+// the total Hamming distance between consecutive subsets
+func totalChange(allValues [][]int) int {
+	total := 0
+	for i := 1; i < len(allValues); i++ {
+		total += HammingDistance(allValues[i-1], allValues[i])
+	}
+	return total
+}
+
+func sortedSubsets(allValues [][]int) [][]int {
+	sorted := append([][]int{}, allValues...)
+	sort.Slice(sorted, func(i, j int) bool { return lessSubset(sorted[i], sorted[j]) })
+	return sorted
+}
+
+func TestOrderGray(t *testing.T) {
+	allValues := collectFamily(t, Config{LenItems: 3}, WithOrder(OrderGray))
+	correct := [][]int{
+		{},
+		{2},
+		{1, 2},
+		{1},
+		{0, 1},
+		{0, 1, 2},
+		{0, 2},
+		{0},
+	}
+	if !reflect.DeepEqual(allValues, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", allValues, correct)
+	}
+
+	allValues = collectFamily(t, Config{LenItems: 10}, WithOrder(OrderGray))
+	if len(allValues) != 1024 || totalChange(allValues) != 1023 {
+		t.Fatalf("expected 1024 subsets that each differ by one index, got %d with %d changes", len(allValues),
+			totalChange(allValues))
+	}
+}
+
+func TestOrderLocality(t *testing.T) {
+	thirds := Constraint{Name: "thirds", Allow: func(indices []int) bool { return len(indices)%3 == 0 }}
+	cfg := Config{LenItems: 8, Constraints: []Constraint{thirds}}
+	gray := collectFamily(t, cfg, WithOrder(OrderGray))
+	local := collectFamily(t, cfg, WithOrder(OrderLocality))
+
+	if !reflect.DeepEqual(sortedSubsets(local), sortedSubsets(gray)) {
+		t.Fatalf("expected the same subsets in a different order")
+	}
+	if totalChange(local) >= totalChange(gray) {
+		t.Fatalf("expected fewer changes than the Gray code order, got %d and %d", totalChange(local),
+			totalChange(gray))
+	}
+
+	window := collectFamily(t, cfg, WithOrder(OrderLocality), WithLookahead(1))
+	if !reflect.DeepEqual(window, gray) {
+		t.Fatalf("expected a window of one subset to be the Gray code order")
+	}
+}
+
+func TestOrderLexicographic(t *testing.T) {
+	out, _ := VariableSize(3, WithOrder(OrderLexicographic))
+	got := [][]int{}
+	for subset := range out {
+		got = append(got, subset)
+	}
+	correct := [][]int{{}, {0}, {0, 1}, {0, 1, 2}, {0, 2}, {1}, {1, 2}, {2}}
+	if !reflect.DeepEqual(got, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", got, correct)
+	}
+
+	// the same subsets as any other order, sorted the way sort.Slice would sort them
+	out, _ = VariableSize(7, WithOrder(OrderLexicographic), WithMinSize(2), WithMaxSize(4))
+	got = [][]int{}
+	for subset := range out {
+		got = append(got, subset)
+	}
+	all, _ := VariableSize(7, WithMinSize(2), WithMaxSize(4))
+	correct = [][]int{}
+	for subset := range all {
+		sort.Ints(subset)
+		correct = append(correct, subset)
+	}
+	sort.Slice(correct, func(i, j int) bool { return lexLess(correct[i], correct[j]) })
+	if !reflect.DeepEqual(got, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", got, correct)
+	}
+
+	if _, _, err := Start(Config{LenItems: 3}, WithOrder(OrderLexicographic)); err == nil {
+		t.Fatalf("expected Start to reject OrderLexicographic")
+	}
+}
+
+// a start rank is in canonical order, so it can't start a lexicographic walk
+func TestOrderLexicographicStartRank(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected a panic")
+		}
+	}()
+	cursor := NewCursor(3)
+	cursor.Visited([]int{0})
+	VariableSize(3, WithOrder(OrderLexicographic), cursor.Option())
+}
+
+func lexLess(a []int, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+func TestOrderBySize(t *testing.T) {
+	allValues := collectFamily(t, Config{LenItems: 3}, WithOrder(OrderBySize))
+	correct := [][]int{
+		{},
+		{2},
+		{1},
+		{0},
+		{1, 2},
+		{0, 2},
+		{0, 1},
+		{0, 1, 2},
+	}
+	if !reflect.DeepEqual(allValues, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", allValues, correct)
+	}
+}
+
+// the first solution of a search by size is one of the smallest, and reaching it skips the larger sizes
+func TestOrderBySizeFirstSolution(t *testing.T) {
+	items, _ := NewItems("a", "b", "c", "d", "e", "f", "g", "h")
+	cfg := Config{LenItems: 8, MinSize: 1, Constraints: []Constraint{mustParse(t, "(a & b) | (c & d & e)", items)}}
+	out, ctl, _ := Start(cfg, WithOrder(OrderBySize), WithMaxSolutions(1))
+	first := <-out
+	for range out {
+	}
+	if !reflect.DeepEqual(first, []int{0, 1}) {
+		t.Fatalf("expected the smallest solution first, got %v", first)
+	}
+	if ctl.Stats().Nodes >= 1<<8 {
+		t.Fatalf("expected the search to end in the small sizes, visited %d nodes", ctl.Stats().Nodes)
+	}
+
+	all := collectFamily(t, Config{LenItems: 6, MaxSize: 4, Required: []int{1}}, WithOrder(OrderBySize))
+	for i := 1; i < len(all); i++ {
+		if len(all[i]) < len(all[i-1]) {
+			t.Fatalf("%v came after %v", all[i], all[i-1])
+		}
+	}
+}
+
+// the colexicographic order of the powerset counts up in binary with index 0 as the least significant bit
+func TestOrderColex(t *testing.T) {
+	out, _ := FixedSize(4, WithOrder(OrderColex))
+	rank := 0
+	for subset := range out {
+		for idx, included := range subset {
+			if included != (rank&(1<<idx) != 0) {
+				t.Fatalf("subset %d is %v", rank, subset)
+			}
+		}
+		rank++
+	}
+	if rank != 16 {
+		t.Fatalf("expected 16 subsets, got %d", rank)
+	}
+
+	if _, _, err := Start(Config{LenItems: 3}, WithOrder(OrderColex)); err == nil {
+		t.Fatalf("expected Start to reject OrderColex")
+	}
+}
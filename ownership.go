@@ -0,0 +1,135 @@
+package powerset
+
+// Ownership governs whether the subsets a search emits are safe to retain.  the zero value is Copy
+type Ownership struct {
+	borrow bool
+	free   func(subset []int)
+}
+
+var (
+	// Copy emits every subset in a slice of its own, which the consumer may keep and modify.  this is the default
+	Copy = Ownership{}
+
+	// Borrow emits subsets in a small set of slices that the search reuses, so emitting costs no allocations.  a
+	// borrowed slice is only valid until the consumer receives the next subset, and must be copied to be kept
+	Borrow = Ownership{borrow: true}
+)
+
+// Recycle is Borrow, but calls free with each borrowed slice just before the search takes it back to reuse it, so a
+// consumer that hands slices on to other code can drop those references in time.  free is called from the search's
+// goroutine
+func Recycle(free func(subset []int)) Ownership {
+	return Ownership{borrow: true, free: free}
+}
+
+// WithOwnership sets the Ownership of the subsets that Start and Family emit
+func WithOwnership(own Ownership) Option {
+	return func(o *options) {
+		o.ownership = own
+	}
+}
+
+// Borrowed is a value that belongs to the search that emitted it, which keeps consumers from mistaking it for their
+// own.  it's only valid until the consumer receives the next value, and Clone makes a copy that the consumer owns
+type Borrowed[T any] struct {
+	value T
+	clone func(T) T
+}
+
+// Value returns the borrowed value, which must not be retained
+func (b Borrowed[T]) Value() T {
+	return b.value
+}
+
+// Clone returns a copy of the value that's safe to retain
+func (b Borrowed[T]) Clone() T {
+	return b.clone(b.value)
+}
+
+func cloneInts(indices []int) []int {
+	return append([]int{}, indices...)
+}
+
+// StartBorrowed is Start with the Borrow ownership, unless a Recycle ownership is given, whose output channel makes
+// the borrowing explicit in its type.  WithRingBuffer doesn't apply, since the output is always a channel
+func StartBorrowed(cfg Config, opts ...Option) (<-chan Borrowed[[]int], *Control, error) {
+	o := buildOptions(opts)
+	if !o.ownership.borrow {
+		opts = append(opts, WithOwnership(Borrow))
+	}
+
+	out := make(chan Borrowed[[]int])
+	_, ctl, err := start(cfg, opts, out)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, ctl, nil
+}
+
+// bufferPool hands out the slices that borrowed subsets are emitted in, and takes them back once the consumer must
+// have moved on from them.  a subset sent on a channel is finished with once the next one has been received, and one
+// pushed to a ring buffer once the ring has made room for as many more as it holds, so the pool keeps the last lag
+// subsets it sent
+type bufferPool struct {
+	free   [][]int
+	sent   [][]int
+	lag    int
+	onFree func([]int)
+
+	// counts the free buffers, which are a cache that can be flushed, if not nil
+	memory *memoryMeter
+}
+
+func newBufferPool(lag int, onFree func([]int)) *bufferPool {
+	return &bufferPool{lag: lag, onFree: onFree}
+}
+
+// fill copies indices into a free buffer
+func (pool *bufferPool) fill(indices []int) []int {
+	var buffer []int
+	if n := len(pool.free); n > 0 {
+		buffer = pool.free[n-1]
+		pool.free = pool.free[:n-1]
+		pool.count(buffer, -1)
+	}
+
+	// buffers are as large as the slice being copied can grow, so they're only allocated once
+	if cap(buffer) < len(indices) {
+		buffer = make([]int, 0, cap(indices))
+	}
+	return append(buffer[:0], indices...)
+}
+
+// reject takes back a buffer that was filled but will never be sent
+func (pool *bufferPool) reject(buffer []int) {
+	pool.free = append(pool.free, buffer)
+	pool.count(buffer, 1)
+}
+
+// wasSent records that a buffer was sent, and takes back the buffers the consumer is finished with
+func (pool *bufferPool) wasSent(buffer []int) {
+	pool.sent = append(pool.sent, buffer)
+	for len(pool.sent) > pool.lag {
+		done := pool.sent[0]
+		pool.sent = pool.sent[1:]
+		if pool.onFree != nil {
+			pool.onFree(done)
+		}
+		pool.free = append(pool.free, done)
+		pool.count(done, 1)
+	}
+}
+
+// flush drops the free buffers, to give their memory back
+func (pool *bufferPool) flush() {
+	for _, buffer := range pool.free {
+		pool.count(buffer, -1)
+	}
+	pool.free = nil
+}
+
+func (pool *bufferPool) count(buffer []int, sign int64) {
+	if pool.memory != nil {
+		pool.memory.add(sign * subsetBytes(buffer))
+	}
+}
@@ -0,0 +1,99 @@
+package powerset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOwnershipBorrow(t *testing.T) {
+	cfg := Config{LenItems: 6, MaxSize: 3}
+	out, _, _ := Family(cfg, WithOwnership(Borrow))
+
+	allValues := [][]int{}
+	buffers := map[*int]bool{}
+	for subset := range out {
+		if len(subset) > 0 {
+			buffers[&subset[0]] = true
+		}
+		allValues = append(allValues, append([]int{}, subset...))
+	}
+
+	if len(buffers) > 2 {
+		t.Fatalf("expected borrowed subsets to share 2 buffers, got %d", len(buffers))
+	}
+	if correct := collectFamily(t, cfg); !reflect.DeepEqual(allValues, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", allValues, correct)
+	}
+}
+
+func TestOwnershipBorrowHeld(t *testing.T) {
+	cfg := Config{LenItems: 6, MaxSize: 3}
+	for _, opts := range [][]Option{
+		{WithOrder(OrderLocality), WithLookahead(4)},
+		{WithQuotaPerSize(10), WithBloomDedup(100, 0.0001)},
+	} {
+		correct := collectFamily(t, cfg, opts...)
+
+		out, _, _ := Family(cfg, append(opts, WithOwnership(Borrow))...)
+		allValues := [][]int{}
+		for subset := range out {
+			allValues = append(allValues, append([]int{}, subset...))
+		}
+		if !reflect.DeepEqual(allValues, correct) {
+			t.Fatalf("\n%v\n\n!=\n\n%v", allValues, correct)
+		}
+	}
+}
+
+func TestOwnershipCopy(t *testing.T) {
+	out, _, _ := Family(Config{LenItems: 3}, WithOwnership(Copy))
+	allValues := [][]int{}
+	for subset := range out {
+		allValues = append(allValues, subset)
+	}
+	if !reflect.DeepEqual(allValues, collectFamily(t, Config{LenItems: 3})) {
+		t.Fatalf("expected copied subsets to be safe to retain, got %v", allValues)
+	}
+}
+
+func TestOwnershipRecycle(t *testing.T) {
+	freed := 0
+	out, _, _ := Family(Config{LenItems: 4}, WithOwnership(Recycle(func(subset []int) { freed++ })))
+	received := 0
+	for range out {
+		received++
+	}
+
+	// every subset but the last is taken back before the search ends
+	if freed != received-1 {
+		t.Fatalf("freed %d of %d subsets, expected %d", freed, received, received-1)
+	}
+}
+
+func TestOwnershipRingBuffer(t *testing.T) {
+	cfg := Config{LenItems: 8, MinSize: 2}
+	_, ctl, _ := Start(cfg, WithOwnership(Borrow), WithRingBuffer(8))
+	allValues := [][]int{}
+	for subset, ok := ctl.Next(); ok; subset, ok = ctl.Next() {
+		allValues = append(allValues, append([]int{}, subset...))
+	}
+	if correct := collectFamily(t, cfg); !reflect.DeepEqual(allValues, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", allValues, correct)
+	}
+}
+
+func TestStartBorrowed(t *testing.T) {
+	cfg := Config{LenItems: 5, Required: []int{1}}
+	out, _, err := StartBorrowed(cfg, WithRingBuffer(4))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	allValues := [][]int{}
+	for borrowed := range out {
+		allValues = append(allValues, borrowed.Clone())
+	}
+	if correct := collectFamily(t, cfg); !reflect.DeepEqual(allValues, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", allValues, correct)
+	}
+}
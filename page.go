@@ -0,0 +1,37 @@
+package powerset
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Page returns the subsets of n items on page pageIndex, counting from zero, of the powerset split into pages of
+// pageSize subsets in the order of FixedSize.  each page is computed from the ranks of its subsets, so a web UI can
+// serve any page of a powerset without keeping iteration state between requests.  the last page may be short, and a
+// page past it is an error
+func Page(n int, pageSize int, pageIndex *big.Int) ([][]int, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("powerset: n must not be negative, got %d", n)
+	}
+	if pageSize < 1 {
+		return nil, fmt.Errorf("powerset: page size must be positive, got %d", pageSize)
+	}
+	if pageIndex.Sign() < 0 {
+		return nil, fmt.Errorf("powerset: page index must not be negative, got %v", pageIndex)
+	}
+
+	total := new(big.Int).Lsh(bigOne, uint(n))
+	rank := new(big.Int).Mul(pageIndex, big.NewInt(int64(pageSize)))
+	if rank.Cmp(total) >= 0 {
+		pages := new(big.Int).Add(total, big.NewInt(int64(pageSize-1)))
+		pages.Div(pages, big.NewInt(int64(pageSize)))
+		return nil, fmt.Errorf("powerset: page %v is past the last of %v pages", pageIndex, pages)
+	}
+
+	page := [][]int{}
+	for i := 0; i < pageSize && rank.Cmp(total) < 0; i++ {
+		page = append(page, Unrank(rank, n))
+		rank.Add(rank, bigOne)
+	}
+	return page, nil
+}
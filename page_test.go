@@ -0,0 +1,62 @@
+package powerset
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestPage(t *testing.T) {
+	all := [][]int{}
+	for _, subset := range Enumerate64(4) {
+		all = append(all, subset)
+	}
+
+	for pageIndex := 0; pageIndex < 6; pageIndex++ {
+		page, err := Page(4, 3, big.NewInt(int64(pageIndex)))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		end := pageIndex*3 + 3
+		if end > len(all) {
+			end = len(all)
+		}
+		if correct := all[pageIndex*3 : end]; !reflect.DeepEqual(page, correct) {
+			t.Fatalf("page %d: \n%v\n\n!=\n\n%v", pageIndex, page, correct)
+		}
+	}
+
+	if _, err := Page(4, 3, big.NewInt(6)); err == nil {
+		t.Fatalf("expected an error for a page past the last")
+	}
+}
+
+func TestPageHuge(t *testing.T) {
+	// the second to last page of a powerset far too big to iterate
+	pageIndex := new(big.Int).Lsh(bigOne, 198)
+	pageIndex.Sub(pageIndex, big.NewInt(2))
+	page, err := Page(200, 4, pageIndex)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page) != 4 {
+		t.Fatalf("expected a full page, got %d subsets", len(page))
+	}
+	// its last rank is every bit set but the one for index 197
+	last := page[3]
+	if len(last) != 199 || last[196] != 196 || last[197] != 198 {
+		t.Fatalf("unexpected last subset of the page %v", last)
+	}
+}
+
+func TestPageErrors(t *testing.T) {
+	if _, err := Page(-1, 3, big.NewInt(0)); err == nil {
+		t.Fatalf("expected an error for a negative n")
+	}
+	if _, err := Page(3, 0, big.NewInt(0)); err == nil {
+		t.Fatalf("expected an error for an empty page size")
+	}
+	if _, err := Page(3, 3, big.NewInt(-1)); err == nil {
+		t.Fatalf("expected an error for a negative page")
+	}
+}
@@ -0,0 +1,99 @@
+package powerset
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// the number of subsets in each batch sent by ParallelFixedSize
+const parallelBatchSize = 1024
+
+// ParallelFixedSize generates the same subsets as FixedSize, using several goroutines, for CPU bound consumers that
+// don't care about order.  rank space is split into contiguous intervals that the workers take turns claiming, and each
+// worker counts through its interval on its own, with no coordination beyond claiming the next one.  subsets are sent
+// in batches of up to 1024, to keep the channel from becoming the bottleneck, and the subsets within a batch are in
+// order, but batches from different workers are interleaved arbitrarily.  workers defaults to GOMAXPROCS if it isn't
+// positive.  ParallelFixedSize understands WithProfileLabel, and each worker is its own shard
+func ParallelFixedSize(lenItems int, workers int, opts ...Option) (<-chan [][]bool, func()) {
+	o := buildOptions(opts)
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	out := make(chan [][]bool, workers)
+	stopIn := make(chan bool)
+
+	// the intervals are the subtrees below the first prefixBits indices, with enough of them per worker to keep the
+	// workers balanced when the consumer takes longer with some batches than others
+	prefixBits := 0
+	for prefixBits < lenItems && prefixBits < 62 && 1<<prefixBits < workers*16 {
+		prefixBits++
+	}
+	numIntervals := uint64(1) << prefixBits
+	var nextInterval atomic.Uint64
+
+	workersDone := new(sync.WaitGroup)
+	workersDone.Add(workers)
+	for w := 0; w < workers; w++ {
+		go o.profiled("ParallelFixedSize", w, func() {
+			defer workersDone.Done()
+			for {
+				interval := nextInterval.Add(1) - 1
+				if interval >= numIntervals || !countInterval(lenItems, prefixBits, interval, out, stopIn) {
+					return
+				}
+			}
+		})
+	}
+
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		workersDone.Wait()
+		close(out)
+	}()
+
+	stop := makeStopper(stopIn, wg)
+	return out, stop
+}
+
+// countInterval sends every subset whose first prefixBits indices are the binary digits of prefix, in rank order,
+// counting through the rest of the indices.  returns false if it was stopped
+func countInterval(lenItems int, prefixBits int, prefix uint64, out chan<- [][]bool, stopIn <-chan bool) bool {
+	subset := make([]bool, lenItems)
+	for i := 0; i < prefixBits; i++ {
+		subset[i] = prefix&(1<<(prefixBits-1-i)) != 0
+	}
+
+	batch := make([][]bool, 0, parallelBatchSize)
+	send := func() bool {
+		select {
+		case <-stopIn:
+			return false
+		case out <- batch:
+			batch = make([][]bool, 0, parallelBatchSize)
+			return true
+		}
+	}
+
+	for {
+		batch = append(batch, append([]bool{}, subset...))
+		if len(batch) == parallelBatchSize && !send() {
+			return false
+		}
+
+		// increment the suffix as a binary number whose least significant bit is the last index
+		idx := lenItems - 1
+		for idx >= prefixBits && subset[idx] {
+			subset[idx] = false
+			idx--
+		}
+		if idx < prefixBits {
+			break
+		}
+		subset[idx] = true
+	}
+
+	return len(batch) == 0 || send()
+}
@@ -0,0 +1,48 @@
+package powerset
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestParallelFixedSize(t *testing.T) {
+	for _, lenItems := range []int{0, 1, 5, 12} {
+		for _, workers := range []int{1, 3, 0} {
+			out, _ := ParallelFixedSize(lenItems, workers)
+			allValues := [][]bool{}
+			for batch := range out {
+				allValues = append(allValues, batch...)
+			}
+
+			// order the subsets by rank
+			rank := func(subset []bool) uint64 {
+				r := uint64(0)
+				for _, in := range subset {
+					r <<= 1
+					if in {
+						r |= 1
+					}
+				}
+				return r
+			}
+			sort.Slice(allValues, func(i, j int) bool { return rank(allValues[i]) < rank(allValues[j]) })
+
+			serial, _ := FixedSize(lenItems)
+			correct := [][]bool{}
+			for subset := range serial {
+				correct = append(correct, subset)
+			}
+			if !reflect.DeepEqual(allValues, correct) {
+				t.Fatalf("%d items, %d workers:\n%v\n\n!=\n\n%v", lenItems, workers, allValues, correct)
+			}
+		}
+	}
+}
+
+func TestParallelFixedSizeStop(t *testing.T) {
+	out, stop := ParallelFixedSize(70, 4)
+	<-out
+	<-out
+	stop()
+}
@@ -0,0 +1,132 @@
+package powerset
+
+import (
+	"math"
+	"sync"
+)
+
+// ParetoPoint is a subset on the Pareto front, along with its score for each objective
+type ParetoPoint struct {
+	Indices []int
+	Scores  []float64
+}
+
+// dominates reports whether a is at least as good as b for every objective and strictly better for at least one.
+// larger scores are better
+func dominates(a, b []float64) bool {
+	better := false
+	for i := range a {
+		if a[i] < b[i] {
+			return false
+		}
+		if a[i] > b[i] {
+			better = true
+		}
+	}
+	return better
+}
+
+// covers reports whether a is at least as good as b for every objective
+func covers(a, b []float64) bool {
+	for i := range a {
+		if a[i] < b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// WithObjectiveBounds gives ParetoFront an optimistic bound for each of its objectives, or Optimize a bound for its
+// score.  a bound is called at an internal node of the powerset tree with the indices included so far and the next
+// index to decide, and must return a value at least as large as the objective's score for any subset below that node.
+// when a subset already found is at least as good as every bound, the node's subtree is skipped.  objectives without a
+// bound are never used to prune
+func WithObjectiveBounds(bounds ...func(included []int, next int) float64) Option {
+	return func(o *options) {
+		o.objectiveBounds = bounds
+	}
+}
+
+// ParetoFront searches the powerset of lenItems items for the subsets that aren't dominated by any other subset, where
+// each subset is scored by every one of the objectives and larger scores are better.  the non-dominated set is
+// maintained during the traversal, which lets WithObjectiveBounds prune subtrees that can't improve on it.  once the
+// traversal has finished, the final front is written to the output channel in the order it was found.  subsets with
+// identical scores are represented on the front by the first one found
+func ParetoFront(lenItems int, objectives []func([]int) float64, opts ...Option) (<-chan ParetoPoint, func(), error) {
+	fam, err := Config{LenItems: lenItems}.compile()
+	if err != nil {
+		return nil, nil, err
+	}
+	o := buildOptions(opts)
+
+	out := make(chan ParetoPoint)
+	stopIn := make(chan bool)
+
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		defer close(out)
+		defer wg.Done()
+
+		front := []ParetoPoint{}
+		optimistic := make([]float64, len(objectives))
+
+		visit := func(indices []int) bool {
+			select {
+			case <-stopIn:
+				return false
+			default:
+			}
+
+			scores := make([]float64, len(objectives))
+			for i, objective := range objectives {
+				scores[i] = objective(indices)
+			}
+
+			kept := front[:0]
+			for _, point := range front {
+				if covers(point.Scores, scores) {
+					return true
+				}
+				if !dominates(scores, point.Scores) {
+					kept = append(kept, point)
+				}
+			}
+			front = append(kept, ParetoPoint{Indices: append([]int{}, indices...), Scores: scores})
+			return true
+		}
+
+		prune := func(included []int, next int) bool {
+			if len(o.objectiveBounds) == 0 {
+				return false
+			}
+			for i := range optimistic {
+				optimistic[i] = math.Inf(1)
+				if i < len(o.objectiveBounds) && o.objectiveBounds[i] != nil {
+					optimistic[i] = o.objectiveBounds[i](included, next)
+				}
+			}
+			for _, point := range front {
+				if covers(point.Scores, optimistic) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if !fam.walk(visit, prune) {
+			return
+		}
+
+		for _, point := range front {
+			select {
+			case <-stopIn:
+				return
+			case out <- point:
+			}
+		}
+	}()
+
+	stop := makeStopper(stopIn, wg)
+	return out, stop, nil
+}
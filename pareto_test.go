@@ -0,0 +1,126 @@
+package powerset
+
+import (
+	"reflect"
+	"testing"
+)
+
+// a small knapsack-style problem: maximize value while minimizing weight
+var paretoValues = []float64{4, 2, 3, 1, 5}
+var paretoWeights = []float64{3, 1, 2, 2, 4}
+
+func paretoObjectives() []func([]int) float64 {
+	value := func(indices []int) float64 {
+		total := 0.0
+		for _, idx := range indices {
+			total += paretoValues[idx]
+		}
+		return total
+	}
+	weight := func(indices []int) float64 {
+		total := 0.0
+		for _, idx := range indices {
+			total -= paretoWeights[idx]
+		}
+		return total
+	}
+	return []func([]int) float64{value, weight}
+}
+
+func collectFront(t *testing.T, opts ...Option) []ParetoPoint {
+	out, _, err := ParetoFront(len(paretoValues), paretoObjectives(), opts...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	front := []ParetoPoint{}
+	for point := range out {
+		front = append(front, point)
+	}
+	return front
+}
+
+// bruteForceFront finds the front by comparing every subset against every other subset
+func bruteForceFront(objectives []func([]int) float64) [][]float64 {
+	out, _ := VariableSize(len(paretoValues))
+	all := [][]float64{}
+	for indices := range out {
+		scores := []float64{}
+		for _, objective := range objectives {
+			scores = append(scores, objective(indices))
+		}
+		all = append(all, scores)
+	}
+
+	front := [][]float64{}
+	for i, a := range all {
+		dominated := false
+		for j, b := range all {
+			if dominates(b, a) || (j < i && reflect.DeepEqual(a, b)) {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			front = append(front, a)
+		}
+	}
+	return front
+}
+
+func frontScores(front []ParetoPoint) map[[2]float64]bool {
+	scores := map[[2]float64]bool{}
+	for _, point := range front {
+		scores[[2]float64{point.Scores[0], point.Scores[1]}] = true
+	}
+	return scores
+}
+
+func TestParetoFront(t *testing.T) {
+	front := collectFront(t)
+
+	correct := map[[2]float64]bool{}
+	for _, scores := range bruteForceFront(paretoObjectives()) {
+		correct[[2]float64{scores[0], scores[1]}] = true
+	}
+
+	if !reflect.DeepEqual(correct, frontScores(front)) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", frontScores(front), correct)
+	}
+	if len(front) != len(correct) {
+		t.Fatalf("front has duplicate scores: %v", front)
+	}
+}
+
+func TestParetoFrontBounds(t *testing.T) {
+	// the best value reachable includes everything that's left, while the best weight includes nothing more
+	valueBound := func(included []int, next int) float64 {
+		total := 0.0
+		for _, idx := range included {
+			total += paretoValues[idx]
+		}
+		for idx := next; idx < len(paretoValues); idx++ {
+			total += paretoValues[idx]
+		}
+		return total
+	}
+	weightBound := func(included []int, next int) float64 {
+		total := 0.0
+		for _, idx := range included {
+			total -= paretoWeights[idx]
+		}
+		return total
+	}
+
+	unbounded := frontScores(collectFront(t))
+	bounded := frontScores(collectFront(t, WithObjectiveBounds(valueBound, weightBound)))
+	if !reflect.DeepEqual(unbounded, bounded) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", bounded, unbounded)
+	}
+}
+
+func TestParetoFrontStop(t *testing.T) {
+	out, stop, _ := ParetoFront(len(paretoValues), paretoObjectives())
+	<-out
+	stop()
+}
@@ -0,0 +1,167 @@
+package powerset
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+)
+
+// Outcome is the result of a single search strategy.  Decisive means the strategy either proved its subset is optimal
+// or found a subset that satisfies the problem outright, so there's nothing left for other strategies to find
+type Outcome struct {
+	Scored
+	Decisive bool
+}
+
+// Searcher is a search strategy that can run as part of a Portfolio.  Search must return promptly once ctx is cancelled
+type Searcher interface {
+	Name() string
+	Search(ctx context.Context) (Outcome, error)
+}
+
+type searcherFunc struct {
+	name   string
+	search func(context.Context) (Outcome, error)
+}
+
+func (s searcherFunc) Name() string                                { return s.name }
+func (s searcherFunc) Search(ctx context.Context) (Outcome, error) { return s.search(ctx) }
+
+// SearcherFunc adapts a plain function into a named Searcher
+func SearcherFunc(name string, search func(ctx context.Context) (Outcome, error)) Searcher {
+	return searcherFunc{name: name, search: search}
+}
+
+// Exhaustive is a Searcher that runs Optimize over the whole family.  an exhaustive search that runs to completion has
+// proven its result optimal, so its outcome is decisive
+func Exhaustive(cfg Config, score func([]int) float64, opts ...Option) Searcher {
+	return SearcherFunc("exhaustive", func(ctx context.Context) (Outcome, error) {
+		best, err := Optimize(cfg, score, append(opts, WithContext(ctx))...)
+		if err != nil {
+			return Outcome{}, err
+		}
+		return Outcome{Scored: best, Decisive: true}, nil
+	})
+}
+
+// Satisfy is a Searcher that looks for any member of the family, which is decisive as soon as one is found.  its score
+// is always zero.  it's for problems where the Constraints are the whole problem.  the member found is the first in
+// canonical order, so cfg's Order must be OrderCanonical
+func Satisfy(cfg Config) Searcher {
+	return SearcherFunc("satisfy", func(ctx context.Context) (Outcome, error) {
+		if err := cfg.canonicalOnly("Satisfy"); err != nil {
+			return Outcome{}, err
+		}
+		fam, err := cfg.compile()
+		if err != nil {
+			return Outcome{}, err
+		}
+
+		var outcome Outcome
+		fam.walk(func(indices []int) bool {
+			outcome = Outcome{Scored: Scored{Indices: append([]int{}, indices...)}, Decisive: true}
+			return false
+		}, func([]int, int) bool {
+			return ctx.Err() != nil
+		})
+
+		if !outcome.Decisive {
+			if err := ctx.Err(); err != nil {
+				return Outcome{}, err
+			}
+			return Outcome{}, ErrNoSolution
+		}
+		return outcome, nil
+	})
+}
+
+// Randomized is a Searcher that scores samples random subsets of the family's items and keeps the best member of the
+// family among them.  it never proves anything, so its outcome is never decisive
+func Randomized(cfg Config, score func([]int) float64, samples int, rng *rand.Rand) Searcher {
+	return SearcherFunc("randomized", func(ctx context.Context) (Outcome, error) {
+		fam, err := cfg.compile()
+		if err != nil {
+			return Outcome{}, err
+		}
+
+		var outcome Outcome
+		found := false
+		for i := 0; i < samples && ctx.Err() == nil; i++ {
+			indices := []int{}
+			for idx := 0; idx < fam.lenItems; idx++ {
+				if rng.Intn(2) == 1 {
+					indices = append(indices, idx)
+				}
+			}
+			if ok, _ := fam.contains(indices); !ok {
+				continue
+			}
+			if s := score(indices); !found || s > outcome.Score {
+				found = true
+				outcome.Scored = Scored{Indices: indices, Score: s}
+			}
+		}
+
+		if !found {
+			return Outcome{}, ErrNoSolution
+		}
+		return outcome, nil
+	})
+}
+
+// PortfolioResult is the outcome of the strategy that won a Portfolio
+type PortfolioResult struct {
+	Outcome
+	Winner string
+}
+
+type portfolioEntry struct {
+	name    string
+	outcome Outcome
+	err     error
+}
+
+// Portfolio runs every strategy concurrently.  as soon as one of them returns a decisive outcome, the rest are
+// cancelled and that strategy wins.  otherwise, once every strategy has finished, the highest scoring outcome wins,
+// with ties going to the strategy listed first.  an error is only returned if every strategy failed, in which case it
+// is the first strategy's error
+func Portfolio(ctx context.Context, strategies ...Searcher) (PortfolioResult, error) {
+	if len(strategies) == 0 {
+		return PortfolioResult{}, errors.New("powerset: Portfolio needs at least one strategy")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan int, len(strategies))
+	entries := make([]portfolioEntry, len(strategies))
+	for i, strategy := range strategies {
+		go func(i int, strategy Searcher) {
+			outcome, err := strategy.Search(ctx)
+			entries[i] = portfolioEntry{name: strategy.Name(), outcome: outcome, err: err}
+			results <- i
+		}(i, strategy)
+	}
+
+	for range strategies {
+		i := <-results
+		if entries[i].err == nil && entries[i].outcome.Decisive {
+			cancel()
+			return PortfolioResult{Outcome: entries[i].outcome, Winner: entries[i].name}, nil
+		}
+	}
+
+	winner := -1
+	for i, entry := range entries {
+		if entry.err != nil {
+			continue
+		}
+		if winner == -1 || entry.outcome.Score > entries[winner].outcome.Score {
+			winner = i
+		}
+	}
+	if winner == -1 {
+		return PortfolioResult{}, entries[0].err
+	}
+	return PortfolioResult{Outcome: entries[winner].outcome, Winner: entries[winner].name}, nil
+}
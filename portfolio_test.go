@@ -0,0 +1,115 @@
+package powerset
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// a strategy that never finishes on its own, to check that the winner cancels it
+func blockingSearcher(cancelled chan<- bool) Searcher {
+	return SearcherFunc("blocking", func(ctx context.Context) (Outcome, error) {
+		<-ctx.Done()
+		cancelled <- true
+		return Outcome{}, ctx.Err()
+	})
+}
+
+func TestPortfolioDecisiveWins(t *testing.T) {
+	cfg := Config{LenItems: len(optimizeValues)}
+	cancelled := make(chan bool, 1)
+
+	result, err := Portfolio(context.Background(), blockingSearcher(cancelled), Exhaustive(cfg, optimizeScore))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Winner != "exhaustive" || result.Score != 14 || !result.Decisive {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatalf("losing strategy was never cancelled")
+	}
+}
+
+func TestPortfolioBestScoreWins(t *testing.T) {
+	fixed := func(name string, score float64) Searcher {
+		return SearcherFunc(name, func(ctx context.Context) (Outcome, error) {
+			return Outcome{Scored: Scored{Score: score}}, nil
+		})
+	}
+	failing := SearcherFunc("failing", func(ctx context.Context) (Outcome, error) {
+		return Outcome{Scored: Scored{Score: 100}}, errors.New("broken")
+	})
+
+	result, err := Portfolio(context.Background(), fixed("low", 1), failing, fixed("high", 5), fixed("tie", 5))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Winner != "high" {
+		t.Fatalf("expected high to win, got %+v", result)
+	}
+}
+
+func TestPortfolioAllFail(t *testing.T) {
+	_, err := Portfolio(context.Background(), Satisfy(Config{LenItems: 2, MinSize: 3}))
+	if err != ErrNoSolution {
+		t.Fatalf("expected ErrNoSolution, got %v", err)
+	}
+
+	if _, err := Portfolio(context.Background()); err == nil {
+		t.Fatalf("expected an error without strategies")
+	}
+}
+
+func TestSatisfy(t *testing.T) {
+	cfg := Config{LenItems: 4, MinSize: 2, Required: []int{3}}
+	outcome, err := Satisfy(cfg).Search(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !outcome.Decisive || len(outcome.Indices) != 2 || outcome.Indices[1] != 3 {
+		t.Fatalf("unexpected outcome %+v", outcome)
+	}
+}
+
+func TestRandomized(t *testing.T) {
+	cfg := Config{LenItems: len(optimizeValues), MaxSize: 2}
+	outcome, err := Randomized(cfg, optimizeScore, 500, rand.New(rand.NewSource(1))).Search(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if outcome.Decisive || outcome.Score != 9 {
+		t.Fatalf("unexpected outcome %+v", outcome)
+	}
+}
+
+func TestOptimizeCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Optimize(Config{LenItems: 20}, optimizeScore, WithContext(ctx))
+	if err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestSatisfyShardAndOrder(t *testing.T) {
+	cfg := Config{LenItems: 4, Shard: &ShardSpec{Count: 4, Index: 1}}
+	outcome, err := Satisfy(cfg).Search(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(outcome.Indices) != 1 || outcome.Indices[0] != 1 {
+		t.Fatalf("unexpected outcome %+v", outcome)
+	}
+
+	cfg.Order = OrderGray
+	if _, err := Satisfy(cfg).Search(context.Background()); err == nil {
+		t.Fatalf("expected an error for OrderGray")
+	}
+}
@@ -2,9 +2,12 @@ package powerset
 
 import (
 	"container/list"
+	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 // PathNode represents a node in a pathway through the powerset tree.  going left through the tree means the index at
@@ -48,18 +51,132 @@ func ValidatePath(path Path, check Path) bool {
 	return true
 }
 
-// Callback generates the powerset but at each leaf node call the callback
-func Callback(lenItems int, cb NodeCallback, state interface{}) <-chan interface{} {
+// Callback generates the powerset but at each leaf node call the callback.  Callback understands WithMaxDepth,
+// WithMaxSolutions, WithPrefixCache, WithStartRank, WithProfileLabel and WithContext.  once the context is done, the
+// callback isn't called again and Callback's own sends give up, and a callback that sends with Emit doesn't block on a
+// consumer that has gone away
+func Callback(lenItems int, cb NodeCallback, state interface{}, opts ...Option) <-chan interface{} {
+	listCb := func(path *list.List, included []int, fixed []bool, isLeaf bool, state interface{},
+		out chan<- interface{}) (bool, int, interface{}) {
+
+		return cb(llToPath(path), isLeaf, state, out)
+	}
+	return callback(lenItems, listCb, state, opts...)
+}
+
+// listCallback is the callback of callback, which is given the traversal's own list of the path, the indices the path
+// includes in ascending order, and its fixed size view of the path, to convert to whatever its NodeCallback needs.
+// fixed is only up to date for the indices the path has decided
+type listCallback func(path *list.List, included []int, fixed []bool, isLeaf bool, state interface{},
+	out chan<- interface{}) (bool, int, interface{})
+
+func callback(lenItems int, cb listCallback, state interface{}, opts ...Option) <-chan interface{} {
+	o := buildOptions(opts)
 	indices := list.New()
 	path := list.New()
 	out := make(chan interface{})
-	wrappedCb := func(indices *list.List, isLeaf bool, state interface{}) (bool, int, interface{}) {
-		return cb(llToPath(indices), isLeaf, state, out)
+
+	// with a solution limit, the callback writes to a proxy channel that counts what it forwards to out, and once the
+	// limit is reached the callback is no longer called and the traversal terminates completely
+	var halted atomic.Bool
+	cbOut := chan<- interface{}(out)
+	forwarded := make(chan bool)
+	if o.maxSolutions > 0 {
+		proxy := make(chan interface{})
+		cbOut = proxy
+		go forwardSolutions(proxy, out, o.maxSolutions, &halted, forwarded, o.done())
+	} else {
+		close(forwarded)
 	}
-	go powerSetCallback(0, lenItems, indices, wrappedCb, path, state, out)
+
+	// every node that's visited comes through wrappedCb, even when its callback is cached, so it keeps the fixed size
+	// view and the included indices up to date from the index that each node decides.  the indices that a node's
+	// ancestors decided are the ones below its own, so anything from its index up was left over from another branch
+	fixed := make([]bool, lenItems)
+	included := make([]int, 0, lenItems)
+	userCb := internalCallback(func(path *list.List, isLeaf bool, state interface{}) (bool, int, interface{}) {
+		return cb(path, included, fixed, isLeaf, state, cbOut)
+	})
+	if o.prefixCache != nil {
+		userCb = o.prefixCache.wrap(userCb)
+	}
+	wrappedCb := func(path *list.List, isLeaf bool, state interface{}) (bool, int, interface{}) {
+		if front := path.Front(); front != nil {
+			node := front.Value.(*PathNode)
+			fixed[node.Index] = node.Included
+			for len(included) > 0 && included[len(included)-1] >= node.Index {
+				included = included[:len(included)-1]
+			}
+			if node.Included {
+				included = append(included, node.Index)
+			}
+		} else {
+			included = included[:0]
+		}
+		if halted.Load() || o.cancelled() {
+			return true, -1, nil
+		}
+		if o.startRank != nil {
+			// a node whose last leaf comes before the start rank is pruned by stopping back to its parent
+			if _, hi := Path(llToPath(path)).RankInterval(lenItems); hi.Cmp(o.startRank) < 0 {
+				return true, path.Len() - 1, nil
+			}
+		}
+		return userCb(path, isLeaf, state)
+	}
+	go o.profiled("Callback", 0, func() {
+		defer close(out)
+		if o.frontier != nil {
+			defer close(o.frontier)
+		}
+		powerSetCallback(0, lenItems, indices, wrappedCb, path, state, o)
+		if cbOut != out {
+			close(cbOut)
+		}
+		<-forwarded
+	})
 	return out
 }
 
+// forwards the first limit values from proxy to out, setting halted once the limit is reached or done is closed.
+// anything received after that is discarded, so a callback that was already sending when we halted doesn't block
+// forever
+func forwardSolutions(proxy <-chan interface{}, out chan<- interface{}, limit int, halted *atomic.Bool,
+	forwarded chan<- bool, done <-chan struct{}) {
+
+	defer close(forwarded)
+	count := 0
+	for value := range proxy {
+		if count == limit {
+			continue
+		}
+		select {
+		case out <- value:
+		case <-done:
+			halted.Store(true)
+			count = limit
+			continue
+		}
+		count++
+		if count == limit {
+			halted.Store(true)
+		}
+	}
+}
+
+// Emit sends value on out, the output channel of a callback, unless ctx is done first, in which case it returns the
+// context's error and value is dropped.  a callback that sends with a plain out <- value blocks forever once its
+// consumer stops reading, where one that sends with Emit, and gives up when it returns an error, ends cleanly when
+// the consumer cancels ctx
+func Emit(ctx context.Context, out chan<- interface{}, value interface{}) error {
+	select {
+	case out <- value:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // convert a linked list to a fixed size array of booleans where the indices contained in the linkedlist are true in the
 // fixed array, otherwise false
 func llToIndicesFixed(lenItems int, indices *list.List) []bool {
@@ -99,8 +216,10 @@ func llToPath(indices *list.List) []*PathNode {
 }
 
 // FixedSize generates a powerset of fixed size items.  each item returned on the output channel has a length of
-// lenItems and each element is either true or false, indicating that the index is included in the combination
-func FixedSize(lenItems int) (<-chan []bool, func()) {
+// lenItems and each element is either true or false, indicating that the index is included in the combination.
+// FixedSize understands WithMinSize, WithMaxSize, WithStartRank and WithOrder(OrderColex)
+func FixedSize(lenItems int, opts ...Option) (<-chan []bool, func()) {
+	o := buildOptions(opts)
 	out := make(chan []bool)
 	indicesOut := make(chan *list.List)
 	stopIn := make(chan bool)
@@ -108,7 +227,7 @@ func FixedSize(lenItems int) (<-chan []bool, func()) {
 
 	wg := new(sync.WaitGroup)
 	wg.Add(2)
-	go powerSet(0, lenItems, indices, indicesOut, wg, stopIn)
+	go powerSet(0, lenItems, indices, indicesOut, wg, stopIn, o.sizeBounds(lenItems), o.seek(lenItems))
 
 	go func() {
 		defer close(out)
@@ -116,6 +235,9 @@ func FixedSize(lenItems int) (<-chan []bool, func()) {
 
 		for indices := range indicesOut {
 			unpackedIndices := llToIndicesFixed(lenItems, indices)
+			if o.order == OrderColex {
+				slices.Reverse(unpackedIndices)
+			}
 			select {
 			case <-stopIn:
 				break
@@ -130,8 +252,19 @@ func FixedSize(lenItems int) (<-chan []bool, func()) {
 }
 
 // VariableSize generates a variable size powerset.  each slice returned on the output channel is a variable size slice
-// containing the index numbers othemselves of the items included in each combination
-func VariableSize(lenItems int) (<-chan []int, func()) {
+// containing the index numbers othemselves of the items included in each combination.  VariableSize understands
+// WithMinSize, WithMaxSize, WithStartRank and WithOrder(OrderLexicographic), which emits the subsets as sorted slices
+// in lexicographic order instead.  a start rank is a position in the canonical order, so VariableSize panics if it's
+// given both WithStartRank and OrderLexicographic
+func VariableSize(lenItems int, opts ...Option) (<-chan []int, func()) {
+	o := buildOptions(opts)
+	if o.order == OrderLexicographic {
+		if o.startRank != nil {
+			panic("powerset: VariableSize can't start OrderLexicographic at a rank of the canonical order")
+		}
+		return lexicographic(lenItems, o.sizeBounds(lenItems))
+	}
+
 	out := make(chan []int)
 	indicesOut := make(chan *list.List)
 	stopIn := make(chan bool)
@@ -139,7 +272,7 @@ func VariableSize(lenItems int) (<-chan []int, func()) {
 
 	wg := sync.WaitGroup{}
 	wg.Add(2)
-	go powerSet(0, lenItems, indices, indicesOut, &wg, stopIn)
+	go powerSet(0, lenItems, indices, indicesOut, &wg, stopIn, o.sizeBounds(lenItems), o.seek(lenItems))
 
 	go func() {
 		defer close(out)
@@ -160,6 +293,76 @@ func VariableSize(lenItems int) (<-chan []int, func()) {
 	return out, stop
 }
 
+// Of generates the powerset of items themselves, with the same order and early termination as VariableSize, so there
+// are no indices to translate back into your own data.  each slice returned on the output channel is a new slice
+// holding the included items in the order they appear in items
+func Of[T any](items []T) (<-chan []T, func()) {
+	in, stopIn := VariableSize(len(items))
+	out := make(chan []T)
+	done := make(chan bool)
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer close(out)
+		defer wg.Done()
+
+		for indices := range in {
+			// VariableSize lists the highest index first
+			subset := make([]T, len(indices))
+			for i, idx := range indices {
+				subset[len(indices)-1-i] = items[idx]
+			}
+			select {
+			case <-done:
+				return
+			case out <- subset:
+			}
+		}
+	}()
+
+	stop := func() {
+		stopIn()
+		close(done)
+		wg.Wait()
+	}
+	return out, stop
+}
+
+// FixedSizeCtx is FixedSize, stopped as soon as ctx is cancelled or its deadline passes, which closes the output
+// channel.  the returned stop function can still be called to stop it earlier, and calling it after ctx is done is
+// harmless
+func FixedSizeCtx(ctx context.Context, lenItems int, opts ...Option) (<-chan []bool, func()) {
+	out, stop := FixedSize(lenItems, opts...)
+	return out, stopWhenDone(ctx, stop)
+}
+
+// VariableSizeCtx is VariableSize, stopped as soon as ctx is done, like FixedSizeCtx
+func VariableSizeCtx(ctx context.Context, lenItems int, opts ...Option) (<-chan []int, func()) {
+	out, stop := VariableSize(lenItems, opts...)
+	return out, stopWhenDone(ctx, stop)
+}
+
+// CallbackCtx is Callback with WithContext(ctx): once ctx is done, the callback isn't called again and the output
+// channel is closed.  a callback that sends on its output channel should send with Emit, so it doesn't block on a
+// consumer that has gone away
+func CallbackCtx(ctx context.Context, lenItems int, cb NodeCallback, state interface{},
+	opts ...Option) <-chan interface{} {
+
+	return Callback(lenItems, cb, state, append(opts, WithContext(ctx))...)
+}
+
+// stopWhenDone returns a stop function that can be called more than once, and that is called when ctx is done
+func stopWhenDone(ctx context.Context, stop func()) func() {
+	var once sync.Once
+	stopOnce := func() { once.Do(stop) }
+	release := context.AfterFunc(ctx, stopOnce)
+	return func() {
+		release()
+		stopOnce()
+	}
+}
+
 // returns a closure that stops and waits for a goroutine to finish
 func makeStopper(in chan<- bool, wg *sync.WaitGroup) func() {
 	stop := func() {
@@ -179,14 +382,19 @@ func copyLL(l *list.List) *list.List {
 	return newList
 }
 
-// the internal mechanism for generating a powerset
-func powerSet(n int, k int, indices *list.List, out chan<- *list.List, wg *sync.WaitGroup, stopIn <-chan bool) bool {
+// the internal mechanism for generating a powerset.  subtrees that can't produce a subset within bounds are skipped
+func powerSet(n int, k int, indices *list.List, out chan<- *list.List, wg *sync.WaitGroup, stopIn <-chan bool,
+	bounds sizeBounds, seek *seekRank) bool {
+
 	if n == 0 {
 		defer close(out)
 		defer wg.Done()
 	}
 
 	done := false
+	if !bounds.reachable(n, k, indices.Len()) || !seek.reachable() {
+		return done
+	}
 
 	if n == k {
 		select {
@@ -201,10 +409,10 @@ func powerSet(n int, k int, indices *list.List, out chan<- *list.List, wg *sync.
 	case <-stopIn:
 		return true
 	default:
-		done = powerSet(n+1, k, indices, out, wg, stopIn)
+		done = powerSet(n+1, k, indices, out, wg, stopIn, bounds, seek.next(n, false))
 		if !done {
 			rightPushed := indices.PushFront(n)
-			done = powerSet(n+1, k, indices, out, wg, stopIn)
+			done = powerSet(n+1, k, indices, out, wg, stopIn, bounds, seek.next(n, true))
 			indices.Remove(rightPushed)
 		}
 	}
@@ -213,19 +421,56 @@ func powerSet(n int, k int, indices *list.List, out chan<- *list.List, wg *sync.
 	return done
 }
 
+// lexicographic generates the subsets within bounds in the lexicographic order of their sorted index slices.  each
+// subset is followed by the subsets that extend it with larger indices, the smallest first
+func lexicographic(lenItems int, bounds sizeBounds) (<-chan []int, func()) {
+	out := make(chan []int)
+	stopIn := make(chan bool)
+
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		defer close(out)
+		defer wg.Done()
+
+		subset := make([]int, 0, lenItems)
+		var recurse func(from int) bool
+		recurse = func(from int) bool {
+			if len(subset) >= bounds.lo {
+				select {
+				case <-stopIn:
+					return false
+				case out <- append([]int{}, subset...):
+				}
+			}
+			if len(subset) == bounds.hi {
+				return true
+			}
+			// the subset can only reach bounds.lo while enough indices are left to extend it with
+			for idx := from; len(subset)+lenItems-idx >= bounds.lo && idx < lenItems; idx++ {
+				subset = append(subset, idx)
+				cont := recurse(idx + 1)
+				subset = subset[:len(subset)-1]
+				if !cont {
+					return false
+				}
+			}
+			return true
+		}
+		recurse(0)
+	}()
+
+	return out, makeStopper(stopIn, wg)
+}
+
 // internal function that creates a powerset but calls a callback at each node, including the leaves.  if the callback
 // returns true for "done", we stop
 func powerSetCallback(n int, k int, indices *list.List, cb internalCallback, path *list.List,
-	state interface{}, out chan<- interface{}) (bool, int) {
+	state interface{}, o *options) (bool, int) {
 
 	stopNode := 0
-	isRoot := n == 0
 	isLeaf := n == k
 
-	if isRoot {
-		defer close(out)
-	}
-
 	var stop bool
 	stop, stopNode, state = cb(path, isLeaf, state)
 
@@ -239,8 +484,24 @@ func powerSetCallback(n int, k int, indices *list.List, cb internalCallback, pat
 		return false, 0
 	}
 
+	// we're as deep as we're allowed to go, so this node's subtree is left for someone else to explore
+	if o.frontier != nil && n == o.maxDepth {
+		select {
+		case o.frontier <- llToPath(path):
+		case <-o.done():
+		}
+		return false, 0
+	}
+
+	return powerSetChildren(n, k, indices, cb, path, state, o)
+}
+
+// explores both children of the internal node at depth n, whose callback has already been called and returned state
+func powerSetChildren(n int, k int, indices *list.List, cb internalCallback, path *list.List,
+	state interface{}, o *options) (bool, int) {
+
 	leftPathPushed := path.PushFront(&PathNode{Index: n, Included: false})
-	stop, stopNode = powerSetCallback(n+1, k, indices, cb, path, state, out)
+	stop, stopNode := powerSetCallback(n+1, k, indices, cb, path, state, o)
 	path.Remove(leftPathPushed)
 
 	// if our left branch told us to stop, let's figure out what we need to do
@@ -252,7 +513,7 @@ func powerSetCallback(n int, k int, indices *list.List, cb internalCallback, pat
 
 	rightIndexPushed := indices.PushFront(n)
 	rightPathPushed := path.PushFront(&PathNode{Index: n, Included: true})
-	stop, stopNode = powerSetCallback(n+1, k, indices, cb, path, state, out)
+	stop, stopNode = powerSetCallback(n+1, k, indices, cb, path, state, o)
 
 	path.Remove(rightPathPushed)
 	indices.Remove(rightIndexPushed)
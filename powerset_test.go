@@ -2,9 +2,11 @@ package powerset
 
 import (
 	"container/list"
+	"context"
 	"reflect"
 	"strconv"
 	"testing"
+	"time"
 )
 
 func TestVarSize(t *testing.T) {
@@ -365,3 +367,177 @@ func TestPathStringEmpty(t *testing.T) {
 		t.Fatalf("\n%v\n!=\n%v", out, correct)
 	}
 }
+
+func TestCallbackMaxDepth(t *testing.T) {
+	visited := []Path{}
+	visit := func(path Path, isLeaf bool, state interface{}, out chan<- interface{}) (bool, int, interface{}) {
+		visited = append(visited, path)
+		return false, 0, nil
+	}
+
+	frontier := make(chan Path)
+	out := Callback(3, visit, nil, WithMaxDepth(2, frontier))
+
+	partials := []Path{}
+	for path := range frontier {
+		partials = append(partials, path)
+	}
+	for range out {
+	}
+
+	correctPartials := []Path{
+		{{1, false}, {0, false}},
+		{{1, true}, {0, false}},
+		{{1, false}, {0, true}},
+		{{1, true}, {0, true}},
+	}
+	if len(partials) != len(correctPartials) {
+		t.Fatalf("got %d frontier paths, expected %d", len(partials), len(correctPartials))
+	}
+	for i, path := range partials {
+		if !ValidatePath(path, correctPartials[i]) {
+			t.Fatalf("frontier %d: %v != %v", i, path, correctPartials[i])
+		}
+	}
+
+	// the root, both depth 1 nodes, and the 4 frontier nodes themselves
+	if len(visited) != 7 {
+		t.Fatalf("callback called %d times, expected 7", len(visited))
+	}
+}
+
+func TestCallbackMaxDepthPruned(t *testing.T) {
+	visit := func(path Path, isLeaf bool, state interface{}, out chan<- interface{}) (bool, int, interface{}) {
+		// terminate every node that includes index 0 back to its parent
+		if len(path) == 1 && path[0].Included {
+			return true, 0, nil
+		}
+		return false, 0, nil
+	}
+
+	frontier := make(chan Path)
+	out := Callback(3, visit, nil, WithMaxDepth(2, frontier))
+
+	count := 0
+	for range frontier {
+		count++
+	}
+	for range out {
+	}
+	if count != 2 {
+		t.Fatalf("expected the pruned subtree to leave 2 frontier paths, got %d", count)
+	}
+}
+
+func TestEmit(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	out := make(chan interface{}, 1)
+	if err := Emit(ctx, out, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cancel()
+	if err := Emit(ctx, out, 2); err != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if value := <-out; value != 1 {
+		t.Fatalf("unexpected value %v", value)
+	}
+}
+
+// a consumer that stops reading and cancels doesn't leave the callback blocked
+func TestCallbackContext(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithMaxSolutions(100)}} {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		cb := func(path Path, isLeaf bool, state interface{}, out chan<- interface{}) (bool, int, interface{}) {
+			calls++
+			if isLeaf && Emit(ctx, out, len(path)) != nil {
+				return true, -1, nil
+			}
+			return false, 0, nil
+		}
+		out := Callback(30, cb, nil, append(opts, WithContext(ctx))...)
+		<-out
+		cancel()
+
+		done := make(chan bool)
+		go func() {
+			for range out {
+			}
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("the callback didn't end")
+		}
+		if calls > 1000 {
+			t.Fatalf("expected the walk to end soon after the cancel, got %d calls", calls)
+		}
+	}
+}
+
+func TestOf(t *testing.T) {
+	out, _ := Of([]string{"a", "b", "c"})
+	correct := [][]string{{}, {"c"}, {"b"}, {"b", "c"}, {"a"}, {"a", "c"}, {"a", "b"}, {"a", "b", "c"}}
+
+	all := [][]string{}
+	for subset := range out {
+		all = append(all, subset)
+	}
+	if !reflect.DeepEqual(all, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", all, correct)
+	}
+}
+
+func TestOfStop(t *testing.T) {
+	out, stop := Of(make([]int, 30))
+	<-out
+	<-out
+	stop()
+}
+
+func TestFixedSizeCtx(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	out, stop := FixedSizeCtx(ctx, 40)
+	<-out
+	cancel()
+	for range out {
+	}
+	stop()
+}
+
+func TestVariableSizeCtx(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	out, _ := VariableSizeCtx(ctx, 40)
+	<-out
+	// the deadline closes the channel without anyone calling stop
+	for range out {
+	}
+
+	out, stop := VariableSizeCtx(context.Background(), 3)
+	count := 0
+	for range out {
+		count++
+	}
+	stop()
+	if count != 8 {
+		t.Fatalf("expected 8 subsets, got %d", count)
+	}
+}
+
+func TestCallbackCtx(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cb := func(path Path, isLeaf bool, state interface{}, out chan<- interface{}) (bool, int, interface{}) {
+		if isLeaf && Emit(ctx, out, len(path)) != nil {
+			return true, -1, nil
+		}
+		return false, 0, nil
+	}
+	out := CallbackCtx(ctx, 40, cb, nil)
+	<-out
+	cancel()
+	for range out {
+	}
+}
@@ -0,0 +1,152 @@
+// Package powersettest helps test code that drives or implements powerset's callback traversal.  RandomCallback makes
+// a callback that exercises every legal callback behavior, and decides each node from the seed and the node's path
+// alone, so a run can be replayed exactly, even by a traversal that visits the nodes in another order
+package powersettest
+
+import (
+	"fmt"
+
+	"github.com/amoffat/powerset"
+)
+
+// Option configures how often a RandomCallback does each of its behaviors
+type Option func(*options)
+
+type options struct {
+	prune     float64
+	backtrack float64
+	abort     float64
+	minDepth  int
+}
+
+// Prune sets the probability that a node prunes its own subtree.  the default is 0.1
+func Prune(p float64) Option {
+	return func(o *options) {
+		o.prune = p
+	}
+}
+
+// Backtrack sets the probability that a node abandons the rest of the subtree of its parent, or of an ancestor above
+// that, picked uniformly.  the default is 0.05
+func Backtrack(p float64) Option {
+	return func(o *options) {
+		o.backtrack = p
+	}
+}
+
+// Abort sets the probability that a node terminates the whole traversal.  the default is 0.001
+func Abort(p float64) Option {
+	return func(o *options) {
+		o.abort = p
+	}
+}
+
+// MinDepth makes the callback continue at every node above depth d, and only behave randomly at depth d and below.  a
+// traversal split with powerset.WithMaxDepth calls the callback on the whole top of the tree before anything below it,
+// so a node there can be visited that a single traversal would already have abandoned.  with a frontier above d the
+// two traversals see the same leaves
+func MinDepth(d int) Option {
+	return func(o *options) {
+		o.minDepth = d
+	}
+}
+
+// Leaf is sent on the output channel by a RandomCallback for every leaf it doesn't prune
+type Leaf struct {
+	// the path to the leaf, as formatted by Path.String
+	Path string
+}
+
+// StateMismatch is sent on the output channel by a RandomCallback when a node is given a state other than the one
+// its parent returned, which means the traversal didn't thread states correctly
+type StateMismatch struct {
+	Path          string
+	Got, Expected interface{}
+}
+
+func (m StateMismatch) Error() string {
+	return fmt.Sprintf("powersettest: node %s was given state %v, expected %v", m.Path, m.Got, m.Expected)
+}
+
+// RandomCallback returns a callback that randomly continues, prunes, backtracks to an ancestor or aborts at each node,
+// and sends a Leaf for every leaf it reaches.  its decisions only depend on seed and the path to the node, so a run is
+// replayable, and a traversal that honors the callback's decisions can be checked against powerset.Callback by
+// comparing the leaves they send.  each node returns a state derived from its path, and a node given any other state
+// sends a StateMismatch.  the root must be given the initial state, which is nil
+func RandomCallback(seed int64, opts ...Option) powerset.NodeCallback {
+	o := &options{prune: 0.1, backtrack: 0.05, abort: 0.001}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return func(path powerset.Path, isLeaf bool, state interface{}, out chan<- interface{}) (bool, int, interface{}) {
+		h := pathHash(uint64(seed), path)
+		if expected := parentState(seed, path); state != expected {
+			out <- StateMismatch{Path: path.String(), Got: state, Expected: expected}
+		}
+
+		depth := len(path)
+		r := unit(mix(h))
+		switch {
+		case depth < o.minDepth:
+		case r < o.abort:
+			return true, -1, nil
+		case r < o.abort+o.backtrack && depth >= 2:
+			// an ancestor strictly above the parent, at a depth from 0 to depth-2
+			return true, int(mix(h^1) % uint64(depth-1)), nil
+		case r < o.abort+o.backtrack+o.prune && depth >= 1:
+			return true, depth - 1, nil
+		}
+
+		if isLeaf {
+			out <- Leaf{Path: path.String()}
+		}
+		return false, 0, nodeState(h)
+	}
+}
+
+// StateAt returns the state that the node at the end of path returns for its children when it continues, so that a
+// traversal split with powerset.WithMaxDepth can be resumed with powerset.FromFrontier
+func StateAt(seed int64, path powerset.Path) interface{} {
+	return nodeState(pathHash(uint64(seed), path))
+}
+
+// the state a node returns for its children, which is never nil, so it can't be mistaken for the root's
+func nodeState(h uint64) interface{} {
+	return h | 1
+}
+
+// parentState is the state the node at the end of path should be given, which is the one its parent returned, or nil
+// for the root
+func parentState(seed int64, path powerset.Path) interface{} {
+	if len(path) == 0 {
+		return nil
+	}
+	return StateAt(seed, path[1:])
+}
+
+// pathHash hashes the decisions on path, which is deepest first, from the root down
+func pathHash(seed uint64, path powerset.Path) uint64 {
+	h := mix(seed)
+	for i := len(path) - 1; i >= 0; i-- {
+		decision := uint64(path[i].Index) << 1
+		if path[i].Included {
+			decision |= 1
+		}
+		h = mix(h ^ decision)
+	}
+	return h
+}
+
+// mix is the splitmix64 finalizer
+func mix(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// unit maps a hash to [0, 1)
+func unit(x uint64) float64 {
+	return float64(x>>11) / (1 << 53)
+}
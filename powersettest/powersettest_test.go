@@ -0,0 +1,78 @@
+package powersettest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/amoffat/powerset"
+)
+
+func collect(t *testing.T, out <-chan interface{}) []string {
+	leaves := []string{}
+	for value := range out {
+		switch value := value.(type) {
+		case Leaf:
+			leaves = append(leaves, value.Path)
+		case StateMismatch:
+			t.Fatalf("%v", value)
+		}
+	}
+	return leaves
+}
+
+func TestRandomCallbackReplayable(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		first := collect(t, powerset.Callback(8, RandomCallback(seed), nil))
+		second := collect(t, powerset.Callback(8, RandomCallback(seed), nil))
+		if !reflect.DeepEqual(first, second) {
+			t.Fatalf("seed %d: \n%v\n\n!=\n\n%v", seed, first, second)
+		}
+	}
+}
+
+// a traversal split at a frontier and resumed with FromFrontier sees exactly the leaves of a single traversal,
+// whatever the callback does below the frontier
+func TestRandomCallbackFrontier(t *testing.T) {
+	short := 0
+	for seed := int64(0); seed < 200; seed++ {
+		cb := RandomCallback(seed, Abort(0.01), MinDepth(4))
+		full := collect(t, powerset.Callback(8, cb, nil))
+		if len(full) < 1<<8 {
+			short++
+		}
+
+		frontier := make(chan powerset.Path, 1<<8)
+		top := collect(t, powerset.Callback(8, cb, nil, powerset.WithMaxDepth(3, frontier)))
+		partials := []powerset.Path{}
+		states := []interface{}{}
+		for partial := range frontier {
+			partials = append(partials, partial)
+			states = append(states, StateAt(seed, partial))
+		}
+		// all of the leaves are below the frontier
+		if len(top) != 0 {
+			t.Fatalf("seed %d: leaves above the frontier %v", seed, top)
+		}
+
+		split := collect(t, powerset.FromFrontier(8, partials, cb, states...))
+		if !reflect.DeepEqual(split, full) {
+			t.Fatalf("seed %d: \n%v\n\n!=\n\n%v", seed, split, full)
+		}
+	}
+	if short == 0 {
+		t.Fatalf("expected some traversals to be cut short")
+	}
+}
+
+func TestRandomCallbackStateMismatch(t *testing.T) {
+	out := powerset.Callback(3, RandomCallback(1, Prune(0), Backtrack(0), Abort(0)), "wrong")
+	mismatches := 0
+	for value := range out {
+		if _, ok := value.(StateMismatch); ok {
+			mismatches++
+		}
+	}
+	if mismatches != 1 {
+		t.Fatalf("expected the root to report a mismatch, got %d", mismatches)
+	}
+}
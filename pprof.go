@@ -0,0 +1,33 @@
+package powerset
+
+import (
+	"context"
+	"runtime/pprof"
+	"strconv"
+)
+
+// WithProfileLabel names the goroutines of a search in CPU profiles.  every goroutine that a generator starts to do
+// the work of a search is tagged with the pprof labels "powerset", with the name, and "shard", with the number of the
+// worker, so the profile of a program running several searches attributes their time to the right one.  without this
+// option, the name is the generator's, like "Family" or "ParallelFixedSize"
+func WithProfileLabel(name string) Option {
+	return func(o *options) {
+		o.profileLabel = name
+	}
+}
+
+// profiled runs fn with the goroutine's pprof labels set for a search, named after the generator unless it was given
+// a name with WithProfileLabel
+func (o *options) profiled(generator string, shard int, fn func()) {
+	name := generator
+	if o.profileLabel != "" {
+		name = o.profileLabel
+	}
+	ctx := o.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	pprof.Do(ctx, pprof.Labels("powerset", name, "shard", strconv.Itoa(shard)), func(context.Context) {
+		fn()
+	})
+}
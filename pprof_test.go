@@ -0,0 +1,48 @@
+package powerset
+
+import (
+	"bytes"
+	"runtime/pprof"
+	"strings"
+	"testing"
+)
+
+// goroutineLabels returns the goroutine profile, which lists the labels of every goroutine
+func goroutineLabels(t *testing.T) string {
+	buf := &bytes.Buffer{}
+	if err := pprof.Lookup("goroutine").WriteTo(buf, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return buf.String()
+}
+
+func TestProfileLabel(t *testing.T) {
+	profile := ""
+	cb := func(path Path, isLeaf bool, state interface{}, out chan<- interface{}) (bool, int, interface{}) {
+		profile = goroutineLabels(t)
+		return true, -1, nil
+	}
+	for range Callback(3, cb, nil, WithProfileLabel("queens")) {
+	}
+	if !strings.Contains(profile, `"powerset":"queens"`) || !strings.Contains(profile, `"shard":"0"`) {
+		t.Fatalf("expected the search's labels in the goroutine profile:\n%s", profile)
+	}
+}
+
+func TestProfileLabelDefault(t *testing.T) {
+	profile := ""
+	slow := Constraint{
+		Name: "profile",
+		Allow: func([]int) bool {
+			profile = goroutineLabels(t)
+			return true
+		},
+	}
+	out, stop, _ := Family(Config{LenItems: 1, Constraints: []Constraint{slow}})
+	for range out {
+	}
+	stop()
+	if !strings.Contains(profile, `"powerset":"Family"`) {
+		t.Fatalf("expected the generator's name in the goroutine profile:\n%s", profile)
+	}
+}
@@ -0,0 +1,100 @@
+package powerset
+
+import (
+	"container/list"
+	"sync"
+)
+
+// WithPrefixCache remembers what the callback of Callback, or of Subtree.Resume, returned at the last size internal
+// nodes, keyed by their paths, so a traversal that revisits a node, like one resumed from a checkpoint or an imported
+// subtree, takes the remembered decision and state instead of calling the callback again.  the cache belongs to the
+// returned Option, so passing the same Option to several traversals shares it between them, and they may run
+// concurrently.  this is only correct for a callback whose decision and state at a node depend on nothing but the
+// node's path, and that doesn't send at internal nodes, since a remembered node sends nothing.  leaves are never
+// cached.  the least recently used node is forgotten once the cache is full
+func WithPrefixCache(size int) Option {
+	cache := newPrefixCache(size)
+	return func(o *options) {
+		o.prefixCache = cache
+	}
+}
+
+// what a callback returned at an internal node
+type prefixResult struct {
+	key      string
+	stop     bool
+	stopNode int
+	state    interface{}
+}
+
+// prefixCache is a least recently used cache of callback results, with the most recently used at the front of order
+type prefixCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+func newPrefixCache(size int) *prefixCache {
+	return &prefixCache{size: size, order: list.New(), entries: map[string]*list.Element{}}
+}
+
+func (c *prefixCache) get(key string) (prefixResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return prefixResult{}, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(prefixResult), true
+}
+
+func (c *prefixCache) put(r prefixResult) {
+	if c.size < 1 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[r.key]; ok {
+		elem.Value = r
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[r.key] = c.order.PushFront(r)
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(prefixResult).key)
+	}
+}
+
+// wrap returns cb, answering from the cache at the internal nodes it remembers
+func (c *prefixCache) wrap(cb internalCallback) internalCallback {
+	return func(path *list.List, isLeaf bool, state interface{}) (bool, int, interface{}) {
+		if isLeaf {
+			return cb(path, isLeaf, state)
+		}
+		key := prefixKey(path)
+		if r, ok := c.get(key); ok {
+			return r.stop, r.stopNode, r.state
+		}
+		stop, stopNode, state := cb(path, isLeaf, state)
+		c.put(prefixResult{key: key, stop: stop, stopNode: stopNode, state: state})
+		return stop, stopNode, state
+	}
+}
+
+// prefixKey encodes a path, whose nodes decide the indices from 0 down, as its depth followed by a bit per decision
+func prefixKey(path *list.List) string {
+	depth := path.Len()
+	key := make([]byte, 4+(depth+7)/8)
+	key[0], key[1], key[2], key[3] = byte(depth), byte(depth>>8), byte(depth>>16), byte(depth>>24)
+	for elem := path.Front(); elem != nil; elem = elem.Next() {
+		node := elem.Value.(*PathNode)
+		if node.Included {
+			key[4+node.Index/8] |= 1 << (node.Index % 8)
+		}
+	}
+	return string(key)
+}
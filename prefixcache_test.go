@@ -0,0 +1,78 @@
+package powerset
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+// countingCallback sends every leaf whose path doesn't include both index 0 and 1, and counts its calls at internal
+// nodes
+func countingCallback(calls *int) NodeCallback {
+	return func(path Path, isLeaf bool, state interface{}, out chan<- interface{}) (bool, int, interface{}) {
+		if !isLeaf {
+			*calls++
+			if len(path) == 2 && path[0].Included && path[1].Included {
+				return true, 1, nil
+			}
+			return false, 0, len(path)
+		}
+		out <- path.String()
+		return false, 0, nil
+	}
+}
+
+func TestWithPrefixCache(t *testing.T) {
+	calls := 0
+	correct := []interface{}{}
+	for leaf := range Callback(5, countingCallback(&calls), nil) {
+		correct = append(correct, leaf)
+	}
+	uncached := calls
+
+	cache := WithPrefixCache(1 << 10)
+	for run := 0; run < 2; run++ {
+		calls = 0
+		got := []interface{}{}
+		for leaf := range Callback(5, countingCallback(&calls), nil, cache) {
+			got = append(got, leaf)
+		}
+		if !reflect.DeepEqual(got, correct) {
+			t.Fatalf("\n%v\n\n!=\n\n%v", got, correct)
+		}
+		if run == 0 && calls != uncached {
+			t.Fatalf("expected %d calls on the first run, got %d", uncached, calls)
+		}
+		if run == 1 && calls != 0 {
+			t.Fatalf("expected the second run to be answered from the cache, got %d calls", calls)
+		}
+	}
+
+	// a resumed subtree shares the cache too
+	calls = 0
+	sub := Subtree{
+		LenItems: 5,
+		Path:     Path{{Index: 1, Included: true}, {Index: 0, Included: false}},
+		Position: big.NewInt(12),
+	}
+	for range sub.Resume(countingCallback(&calls), 2, cache) {
+	}
+	if calls != 0 {
+		t.Fatalf("expected the resumed subtree to be answered from the cache, got %d calls", calls)
+	}
+}
+
+func TestPrefixCacheEvicts(t *testing.T) {
+	c := newPrefixCache(2)
+	for _, key := range []string{"a", "b", "a", "c"} {
+		c.put(prefixResult{key: key})
+	}
+	if _, ok := c.get("b"); ok {
+		t.Fatalf("expected the least recently used entry to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.get(key); !ok {
+			t.Fatalf("expected %q to be cached", key)
+		}
+	}
+}
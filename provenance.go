@@ -0,0 +1,61 @@
+package powerset
+
+import "math/big"
+
+// Provenance is a solution together with where it came from in the powerset tree
+type Provenance struct {
+	// the sorted indices of the items included in the solution
+	Subset []int
+
+	// the decision for every index on the way down to the solution's leaf, deepest first, like the Paths of Callback
+	Path Path
+
+	// the solution's position in the order of FixedSize, which Page and the other rank based functions take
+	Rank *big.Int
+}
+
+// StartWithProvenance is Start, but each solution is emitted with the Path and rank that lead to it, so downstream
+// systems can audit or replay a solution, or resume from it, without recomputing them from the subset.  every
+// solution is a copy the consumer owns, so WithOwnership doesn't apply, and neither do WithRingBuffer or WithTransport
+func StartWithProvenance(cfg Config, opts ...Option) (<-chan Provenance, *Control, error) {
+	out := make(chan Provenance)
+	t := &provenanceTransport{out: out, lenItems: cfg.LenItems}
+	opts = append(opts, WithOwnership(Copy), WithTransport(t))
+	_, ctl, err := Start(cfg, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, ctl, nil
+}
+
+// PathOf returns the Path to the leaf of subset in the powerset tree of lenItems items, deepest first
+func PathOf(subset []int, lenItems int) Path {
+	included := make([]bool, lenItems)
+	for _, idx := range subset {
+		included[idx] = true
+	}
+	path := make(Path, lenItems)
+	for idx := 0; idx < lenItems; idx++ {
+		path[lenItems-1-idx] = &PathNode{Index: idx, Included: included[idx]}
+	}
+	return path
+}
+
+type provenanceTransport struct {
+	out      chan<- Provenance
+	lenItems int
+}
+
+func (t *provenanceTransport) Send(subset []int, stop <-chan bool) bool {
+	p := Provenance{Subset: subset, Path: PathOf(subset, t.lenItems), Rank: Rank(subset, t.lenItems)}
+	select {
+	case <-stop:
+		return false
+	case t.out <- p:
+		return true
+	}
+}
+
+func (t *provenanceTransport) Close() {
+	close(t.out)
+}
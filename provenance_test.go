@@ -0,0 +1,53 @@
+package powerset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStartWithProvenance(t *testing.T) {
+	cfg := Config{LenItems: 5, MinSize: 1, MaxSize: 3, Forbidden: []int{2}}
+	correct := collectFamily(t, cfg)
+
+	out, _, err := StartWithProvenance(cfg, WithOwnership(Borrow))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := [][]int{}
+	for p := range out {
+		got = append(got, p.Subset)
+		if !reflect.DeepEqual(Unrank(p.Rank, 5), p.Subset) {
+			t.Fatalf("rank %v doesn't lead to %v", p.Rank, p.Subset)
+		}
+		if lo, hi := p.Path.RankInterval(5); lo.Cmp(p.Rank) != 0 || hi.Cmp(p.Rank) != 0 {
+			t.Fatalf("path %v doesn't lead to %v", p.Path, p.Subset)
+		}
+	}
+	if !reflect.DeepEqual(got, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", got, correct)
+	}
+}
+
+// the paths are the same ones Callback goes through to reach the leaves
+func TestPathOf(t *testing.T) {
+	out := Callback(3, func(path Path, isLeaf bool, state interface{}, out chan<- interface{}) (bool, int, interface{}) {
+		if isLeaf {
+			out <- path.String()
+		}
+		return false, 0, nil
+	}, nil)
+
+	all, _ := VariableSize(3)
+	for subset := range all {
+		correct := <-out
+		if got := PathOf(subset, 3).String(); got != correct {
+			t.Fatalf("\n%v\n\n!=\n\n%v", got, correct)
+		}
+	}
+}
+
+func TestStartWithProvenanceInvalid(t *testing.T) {
+	if _, _, err := StartWithProvenance(Config{LenItems: 2, Required: []int{3}}); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
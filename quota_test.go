@@ -0,0 +1,41 @@
+package powerset
+
+import "testing"
+
+func TestQuotaPerSize(t *testing.T) {
+	out, ctl, _ := Start(Config{LenItems: 6}, WithQuotaPerSize(2), WithSizeStats())
+
+	perSize := map[int]int{}
+	for indices := range out {
+		perSize[len(indices)]++
+	}
+
+	// sizes 0 and 6 only have a single subset each
+	for size := 0; size <= 6; size++ {
+		correct := 2
+		if size == 0 || size == 6 {
+			correct = 1
+		}
+		if perSize[size] != correct {
+			t.Fatalf("size %d: %d subsets emitted, expected %d", size, perSize[size], correct)
+		}
+	}
+
+	stats := ctl.Stats()
+	if stats.Suppressed >= 1<<6-12 {
+		t.Fatalf("full sizes should have been pruned rather than generated, %d were suppressed", stats.Suppressed)
+	}
+}
+
+func TestQuotaPerSizeEndsEarly(t *testing.T) {
+	// every size fills up long before the 2^30 subsets could be generated
+	out, _, _ := Family(Config{LenItems: 30, MaxSize: 2}, WithQuotaPerSize(3))
+
+	count := 0
+	for range out {
+		count++
+	}
+	if count != 7 {
+		t.Fatalf("%d subsets emitted, expected 7", count)
+	}
+}
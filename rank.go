@@ -0,0 +1,216 @@
+package powerset
+
+import (
+	"fmt"
+	"iter"
+	"math/big"
+)
+
+// the rank of a subset is its position, starting from zero, in the order that FixedSize, VariableSize and the leaves of
+// Callback produce subsets.  index 0 is decided first at the top of the powerset tree, and excluding comes before
+// including, so a subset's rank is the binary number whose most significant of lenItems bits is index 0 and whose bits
+// are set for the included indices
+
+// LeafCount returns the number of leaves in the subtree of the powerset of lenItems items below the node this path
+// leads to, which is the number of subsets that terminating at this node skips
+func (path Path) LeafCount(lenItems int) *big.Int {
+	return new(big.Int).Lsh(bigOne, uint(lenItems-len(path)))
+}
+
+// RankInterval returns the ranks of the first and last leaves below the node this path leads to, in the powerset of
+// lenItems items.  every subset below the node has a rank between lo and hi, inclusive, so a callback can map the
+// subtree it prunes to a contiguous range of rank space
+func (path Path) RankInterval(lenItems int) (lo, hi *big.Int) {
+	lo = new(big.Int)
+	for _, node := range path {
+		if node.Included {
+			lo.SetBit(lo, lenItems-1-node.Index, 1)
+		}
+	}
+	hi = new(big.Int).Add(lo, path.LeafCount(lenItems))
+	hi.Sub(hi, bigOne)
+	return lo, hi
+}
+
+// RankedCallback is a NodeCallback that is also given the rank of each leaf, and a nil rank at internal nodes
+type RankedCallback func(path Path, isLeaf bool, rank *big.Int, state interface{}, out chan<- interface{}) (bool, int,
+	interface{})
+
+// CallbackWithRank is Callback, passing the callback the rank of every leaf it's called at, so it can key caches,
+// partition its output and report progress by leaf without counting leaves itself.  a leaf's rank is its position in
+// the canonical order even when the callback prunes the tree, and each rank is newly allocated
+func CallbackWithRank(lenItems int, cb RankedCallback, state interface{}, opts ...Option) <-chan interface{} {
+	ranked := func(path Path, isLeaf bool, state interface{}, out chan<- interface{}) (bool, int, interface{}) {
+		var rank *big.Int
+		if isLeaf {
+			rank, _ = path.RankInterval(lenItems)
+		}
+		return cb(path, isLeaf, rank, state, out)
+	}
+	return Callback(lenItems, ranked, state, opts...)
+}
+
+// Rank returns the rank of a subset of n items, given by its included indices in any order, so it can be checkpointed
+// or handed to another process as a single number.  it panics if an index isn't from 0 to n-1
+func Rank(subset []int, n int) *big.Int {
+	rank := new(big.Int)
+	for _, idx := range subset {
+		if idx < 0 || idx >= n {
+			panic(fmt.Sprintf("powerset: index %d is out of range for %d items", idx, n))
+		}
+		rank.SetBit(rank, n-1-idx, 1)
+	}
+	return rank
+}
+
+// Unrank returns the sorted included indices of the subset of n items with the given rank, which undoes Rank.  only
+// the low n bits of the rank are read, so a rank of 2^n or more wraps around
+func Unrank(rank *big.Int, n int) []int {
+	subset := []int{}
+	for idx := 0; idx < n; idx++ {
+		if rank.Bit(n-1-idx) == 1 {
+			subset = append(subset, idx)
+		}
+	}
+	return subset
+}
+
+// Enumerate yields every subset of n items as sorted included indices, paired with its rank, in rank order, which is
+// the order of FixedSize.  each rank and subset is newly allocated, so they can be kept, to find the subset again later
+// by its rank
+func Enumerate(n int) iter.Seq2[*big.Int, []int] {
+	return func(yield func(*big.Int, []int) bool) {
+		rank := new(big.Int)
+		enumerate(n, func(subset []int) bool {
+			ok := yield(new(big.Int).Set(rank), append([]int{}, subset...))
+			rank.Add(rank, bigOne)
+			return ok
+		})
+	}
+}
+
+// Enumerate64 is Enumerate with uint64 ranks, which avoids allocating a big.Int per subset.  it panics if n is more
+// than 64, whose ranks don't fit
+func Enumerate64(n int) iter.Seq2[uint64, []int] {
+	if n > 64 {
+		panic(fmt.Sprintf("powerset: Enumerate64 can rank at most 64 items, got %d", n))
+	}
+	return func(yield func(uint64, []int) bool) {
+		rank := uint64(0)
+		enumerate(n, func(subset []int) bool {
+			ok := yield(rank, append([]int{}, subset...))
+			rank++
+			return ok
+		})
+	}
+}
+
+// EnumerateRange is Enumerate, restricted to the subsets whose ranks are from lo to hi, inclusive, so a shard of the
+// powerset can be enumerated on its own without counting up to it
+func EnumerateRange(n int, lo *big.Int, hi *big.Int) iter.Seq2[*big.Int, []int] {
+	return func(yield func(*big.Int, []int) bool) {
+		if lo.Sign() < 0 || lo.Cmp(hi) > 0 || lo.BitLen() > n {
+			return
+		}
+		rank := new(big.Int).Set(lo)
+		in := make([]bool, n)
+		for idx := range in {
+			in[idx] = rank.Bit(n-1-idx) == 1
+		}
+		for {
+			subset := []int{}
+			for idx, included := range in {
+				if included {
+					subset = append(subset, idx)
+				}
+			}
+			if !yield(new(big.Int).Set(rank), subset) || rank.Cmp(hi) >= 0 {
+				return
+			}
+
+			// adding one to the rank carries from the last index, which is the least significant bit
+			idx := n - 1
+			for idx >= 0 && in[idx] {
+				in[idx] = false
+				idx--
+			}
+			if idx < 0 {
+				return
+			}
+			in[idx] = true
+			rank.Add(rank, bigOne)
+		}
+	}
+}
+
+// Shard returns the ranks, from lo to hi inclusive, of shard number shard when the powerset of n items is split into
+// shards contiguous intervals of rank space.  the shards differ in size by at most one subset, and together cover the
+// powerset exactly once.  a shard is empty, with lo greater than hi, when there are more shards than subsets
+func Shard(n int, shards int, shard int) (lo, hi *big.Int, err error) {
+	if n < 0 {
+		return nil, nil, fmt.Errorf("powerset: n must not be negative, got %d", n)
+	}
+	if shards < 1 || shard < 0 || shard >= shards {
+		return nil, nil, fmt.Errorf("powerset: shard %d of %d doesn't exist", shard, shards)
+	}
+	total := new(big.Int).Lsh(bigOne, uint(n))
+	lo = new(big.Int).Mul(total, big.NewInt(int64(shard)))
+	lo.Div(lo, big.NewInt(int64(shards)))
+	hi = new(big.Int).Mul(total, big.NewInt(int64(shard+1)))
+	hi.Div(hi, big.NewInt(int64(shards)))
+	hi.Sub(hi, bigOne)
+	return lo, hi, nil
+}
+
+// the rule reported for the subtrees outside of Config.Shard
+const shardRule = "Shard"
+
+// rankShard is the range of ranks of a Config.Shard
+type rankShard struct {
+	lenItems int
+	lo, hi   *big.Int
+}
+
+func newRankShard(lenItems int, spec ShardSpec) *rankShard {
+	// the spec was validated with the Config
+	lo, hi, _ := Shard(lenItems, spec.Count, spec.Index)
+	return &rankShard{lenItems: lenItems, lo: lo, hi: hi}
+}
+
+// overlaps reports whether the subtree that includes included and has decided the indices before next has any leaves
+// in the shard
+func (s *rankShard) overlaps(included []int, next int) bool {
+	lo := Rank(included, s.lenItems)
+	hi := new(big.Int).Lsh(bigOne, uint(s.lenItems-next))
+	hi.Add(hi, lo)
+	hi.Sub(hi, bigOne)
+	return hi.Cmp(s.lo) >= 0 && lo.Cmp(s.hi) <= 0
+}
+
+// constraint rejects the leaves outside of the shard, for the walks that don't prune
+func (s *rankShard) constraint() Constraint {
+	return Constraint{Name: shardRule, Allow: func(indices []int) bool {
+		rank := Rank(indices, s.lenItems)
+		return rank.Cmp(s.lo) >= 0 && rank.Cmp(s.hi) <= 0
+	}}
+}
+
+// enumerate visits every subset of n items in rank order, deciding index 0 first and excluding before including,
+// until visit returns false.  the slice passed to visit is reused between calls
+func enumerate(n int, visit func(subset []int) bool) {
+	subset := make([]int, 0, n)
+	var recurse func(idx int) bool
+	recurse = func(idx int) bool {
+		if idx == n {
+			return visit(subset)
+		}
+		if !recurse(idx + 1) {
+			return false
+		}
+		subset = append(subset, idx)
+		ok := recurse(idx + 1)
+		subset = subset[:len(subset)-1]
+		return ok
+	}
+	recurse(0)
+}
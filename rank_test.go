@@ -0,0 +1,225 @@
+package powerset
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestPathLeafCount(t *testing.T) {
+	if count := (Path{}).LeafCount(3); count.Cmp(big.NewInt(8)) != 0 {
+		t.Fatalf("root leaf count %v != 8", count)
+	}
+	if count := (Path{{1, true}, {0, false}}).LeafCount(3); count.Cmp(big.NewInt(2)) != 0 {
+		t.Fatalf("leaf count %v != 2", count)
+	}
+	if count := (Path{{2, true}, {1, true}, {0, false}}).LeafCount(3); count.Cmp(big.NewInt(1)) != 0 {
+		t.Fatalf("leaf leaf count %v != 1", count)
+	}
+
+	// far past what fits in a uint64
+	correct := new(big.Int).Lsh(big.NewInt(1), 100)
+	if count := (Path{{0, true}}).LeafCount(101); count.Cmp(correct) != 0 {
+		t.Fatalf("leaf count %v != %v", count, correct)
+	}
+}
+
+// every leaf the Callback visits should land at the rank its position in the traversal says it has
+func TestPathRankInterval(t *testing.T) {
+	rank := int64(0)
+	visit := func(path Path, isLeaf bool, state interface{}, out chan<- interface{}) (bool, int, interface{}) {
+		lo, hi := path.RankInterval(4)
+		if isLeaf {
+			if lo.Cmp(big.NewInt(rank)) != 0 || hi.Cmp(lo) != 0 {
+				t.Fatalf("leaf %v: interval [%v, %v], expected rank %d", path, lo, hi, rank)
+			}
+			rank++
+		} else {
+			width := new(big.Int).Sub(hi, lo)
+			width.Add(width, bigOne)
+			if width.Cmp(path.LeafCount(4)) != 0 || lo.Cmp(big.NewInt(rank)) != 0 {
+				t.Fatalf("node %v: interval [%v, %v] at rank %d", path, lo, hi, rank)
+			}
+		}
+		return false, 0, nil
+	}
+	for range Callback(4, visit, nil) {
+	}
+	if rank != 16 {
+		t.Fatalf("visited %d leaves", rank)
+	}
+}
+
+func TestEnumerate(t *testing.T) {
+	out, _ := FixedSize(5)
+	correct := [][]int{}
+	for included := range out {
+		subset := []int{}
+		for idx, isIn := range included {
+			if isIn {
+				subset = append(subset, idx)
+			}
+		}
+		correct = append(correct, subset)
+	}
+
+	kept := [][]int{}
+	i := int64(0)
+	for rank, subset := range Enumerate(5) {
+		if rank.Cmp(big.NewInt(i)) != 0 {
+			t.Fatalf("subset %d has rank %v", i, rank)
+		}
+		kept = append(kept, subset)
+		i++
+	}
+	if !reflect.DeepEqual(kept, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", kept, correct)
+	}
+
+	kept = [][]int{}
+	for rank, subset := range Enumerate64(5) {
+		if rank != uint64(len(kept)) {
+			t.Fatalf("subset %d has rank %v", len(kept), rank)
+		}
+		kept = append(kept, subset)
+	}
+	if !reflect.DeepEqual(kept, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", kept, correct)
+	}
+}
+
+func TestEnumerateBreak(t *testing.T) {
+	seen := 0
+	for rank, subset := range Enumerate(100) {
+		if seen == 3 {
+			if rank.Int64() != 3 || !reflect.DeepEqual(subset, []int{98, 99}) {
+				t.Fatalf("unexpected fourth subset %v %v", rank, subset)
+			}
+			break
+		}
+		seen++
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected a panic")
+		}
+	}()
+	Enumerate64(65)
+}
+
+func TestEnumerateRange(t *testing.T) {
+	all := [][]int{}
+	for _, subset := range Enumerate(5) {
+		all = append(all, subset)
+	}
+	for _, bounds := range [][2]int64{{0, 31}, {3, 17}, {31, 31}, {0, 0}, {20, 10}} {
+		got := [][]int{}
+		next := bounds[0]
+		for rank, subset := range EnumerateRange(5, big.NewInt(bounds[0]), big.NewInt(bounds[1])) {
+			if rank.Int64() != next {
+				t.Fatalf("expected rank %d, got %v", next, rank)
+			}
+			next++
+			got = append(got, subset)
+		}
+		correct := [][]int{}
+		if bounds[0] <= bounds[1] {
+			correct = all[bounds[0] : bounds[1]+1]
+		}
+		if !reflect.DeepEqual(got, correct) {
+			t.Fatalf("%v: \n%v\n\n!=\n\n%v", bounds, got, correct)
+		}
+	}
+}
+
+func TestShard(t *testing.T) {
+	for _, shards := range []int{1, 3, 7, 8, 40} {
+		next := big.NewInt(0)
+		for shard := 0; shard < shards; shard++ {
+			lo, hi, err := Shard(5, shards, shard)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if lo.Cmp(next) != 0 {
+				t.Fatalf("shard %d of %d starts at %v, expected %v", shard, shards, lo, next)
+			}
+			size := new(big.Int).Sub(hi, lo).Int64() + 1
+			if min := int64(32 / shards); size < min || size > min+1 {
+				t.Fatalf("shard %d of %d has %d subsets", shard, shards, size)
+			}
+			next.Add(hi, bigOne)
+		}
+		if next.Int64() != 32 {
+			t.Fatalf("%d shards cover %v subsets, expected 32", shards, next)
+		}
+	}
+
+	if _, _, err := Shard(5, 4, 4); err == nil {
+		t.Fatalf("expected an error for a shard that doesn't exist")
+	}
+}
+
+func TestCallbackWithRank(t *testing.T) {
+	cb := func(path Path, isLeaf bool, rank *big.Int, state interface{}, out chan<- interface{}) (bool, int,
+		interface{}) {
+
+		if !isLeaf {
+			if rank != nil {
+				t.Errorf("internal node %v was given rank %v", path, rank)
+			}
+			// skip every subtree that includes index 1
+			if len(path) == 2 && path[0].Included {
+				return true, 1, nil
+			}
+			return false, 0, nil
+		}
+		out <- rank.Int64()
+		return false, 0, nil
+	}
+
+	got := []interface{}{}
+	for rank := range CallbackWithRank(4, cb, nil) {
+		got = append(got, rank)
+	}
+	// the ranks whose bit for index 1 is clear
+	correct := []interface{}{int64(0), int64(1), int64(2), int64(3), int64(8), int64(9), int64(10), int64(11)}
+	if !reflect.DeepEqual(got, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", got, correct)
+	}
+}
+
+// Rank and Unrank should agree with the order VariableSize produces subsets in, far past what fits in a uint64 too
+func TestRankUnrank(t *testing.T) {
+	out, _ := VariableSize(5)
+	rank := int64(0)
+	for subset := range out {
+		if r := Rank(subset, 5); r.Cmp(big.NewInt(rank)) != 0 {
+			t.Fatalf("%v has rank %v, expected %d", subset, r, rank)
+		}
+		rank++
+	}
+	for rank := int64(0); rank < 32; rank++ {
+		subset := Unrank(big.NewInt(rank), 5)
+		if r := Rank(subset, 5); r.Cmp(big.NewInt(rank)) != 0 {
+			t.Fatalf("%v from rank %d ranks back to %v", subset, rank, r)
+		}
+	}
+
+	large := new(big.Int).Lsh(bigOne, 99)
+	large.SetBit(large, 0, 1)
+	subset := Unrank(large, 100)
+	if !reflect.DeepEqual(subset, []int{0, 99}) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", subset, []int{0, 99})
+	}
+	if r := Rank([]int{99, 0}, 100); r.Cmp(large) != 0 {
+		t.Fatalf("rank %v != %v", r, large)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected an out of range index to panic")
+		}
+	}()
+	Rank([]int{5}, 5)
+}
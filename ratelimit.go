@@ -0,0 +1,31 @@
+package powerset
+
+import "time"
+
+// tokenBucket paces emissions to a steady rate.  it holds a single token, so emissions are spaced evenly rather than
+// arriving in bursts
+type tokenBucket struct {
+	interval time.Duration
+	next     time.Time
+}
+
+func newTokenBucket(perSecond float64) *tokenBucket {
+	return &tokenBucket{interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+// take blocks until a token is available, returning false without taking one if stopIn is closed first
+func (tb *tokenBucket) take(stopIn <-chan bool) bool {
+	now := time.Now()
+	if wait := tb.next.Sub(now); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-stopIn:
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+		now = tb.next
+	}
+	tb.next = now.Add(tb.interval)
+	return true
+}
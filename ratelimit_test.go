@@ -0,0 +1,42 @@
+package powerset
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	start := time.Now()
+	out, _, _ := Family(Config{LenItems: 4}, WithRateLimit(200))
+
+	count := 0
+	for range out {
+		count++
+	}
+	elapsed := time.Since(start)
+
+	// 16 subsets at 200 per second are spaced 5ms apart, after the first which goes out immediately
+	if count != 16 {
+		t.Fatalf("rate limiting dropped subsets, got %d", count)
+	}
+	if elapsed < 70*time.Millisecond {
+		t.Fatalf("16 subsets at 200/s took only %v", elapsed)
+	}
+}
+
+func TestRateLimitStop(t *testing.T) {
+	out, stop, _ := Family(Config{LenItems: 10}, WithRateLimit(0.1))
+	<-out
+
+	// the producer is now waiting 10 seconds for its next token, and stopping must interrupt that
+	done := make(chan bool)
+	go func() {
+		stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("stop didn't interrupt the rate limiter")
+	}
+}
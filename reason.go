@@ -0,0 +1,79 @@
+package powerset
+
+import "context"
+
+// Reason is why a search ended
+type Reason int
+
+const (
+	// ReasonRunning means the search hasn't ended
+	ReasonRunning Reason = iota
+
+	// ReasonCompleted means every member of the family was visited
+	ReasonCompleted
+
+	// ReasonStopped means the search was stopped with Stop
+	ReasonStopped
+
+	// ReasonCancelled means the context given to WithContext was cancelled or passed its deadline
+	ReasonCancelled
+
+	// ReasonMaxSolutions means the search emitted as many subsets as WithMaxSolutions allowed
+	ReasonMaxSolutions
+
+	// ReasonDeadline means WithSolutionDeadline passed without a new solution
+	ReasonDeadline
+
+	// ReasonJournal means the journal of WithJournal couldn't be written to, and Err is the error writing it
+	ReasonJournal
+)
+
+func (r Reason) String() string {
+	switch r {
+	case ReasonRunning:
+		return "running"
+	case ReasonCompleted:
+		return "completed"
+	case ReasonStopped:
+		return "stopped"
+	case ReasonCancelled:
+		return "cancelled"
+	case ReasonMaxSolutions:
+		return "max solutions"
+	case ReasonDeadline:
+		return "solution deadline"
+	case ReasonJournal:
+		return "journal error"
+	default:
+		return "unknown"
+	}
+}
+
+// Reason returns why the search ended, or ReasonRunning if it hasn't.  together with Err, it tells an early end apart
+// from a search that ran to completion, which the closing of the output channel alone can't
+func (ctl *Control) Reason() Reason {
+	ctl.mu.Lock()
+	defer ctl.mu.Unlock()
+	return ctl.reason
+}
+
+// end records why the search is ending, and the error to report with it.  only the first reason is kept
+func (ctl *Control) end(reason Reason, err error) {
+	ctl.mu.Lock()
+	defer ctl.mu.Unlock()
+	if ctl.reason == ReasonRunning {
+		ctl.reason = reason
+		ctl.err = err
+	}
+}
+
+// cancelWith ends the search when ctx is done, without waiting for it to finish like Stop does.  the returned function
+// releases ctx once the search has ended
+func (ctl *Control) cancelWith(ctx context.Context) func() bool {
+	return context.AfterFunc(ctx, func() {
+		ctl.end(ReasonCancelled, context.Cause(ctx))
+		ctl.stopOnce.Do(func() {
+			close(ctl.stopIn)
+		})
+	})
+}
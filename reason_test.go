@@ -0,0 +1,65 @@
+package powerset
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReasonCompleted(t *testing.T) {
+	out, ctl, _ := Start(Config{LenItems: 4})
+	if ctl.Reason() != ReasonRunning {
+		t.Fatalf("expected a running search, got %v", ctl.Reason())
+	}
+	for range out {
+	}
+	if ctl.Reason() != ReasonCompleted || ctl.Err() != nil {
+		t.Fatalf("expected a completed search, got %v, %v", ctl.Reason(), ctl.Err())
+	}
+}
+
+func TestReasonStopped(t *testing.T) {
+	out, ctl, _ := Start(Config{LenItems: 40})
+	<-out
+	ctl.Stop()
+	if ctl.Reason() != ReasonStopped || ctl.Err() != nil {
+		t.Fatalf("expected a stopped search, got %v, %v", ctl.Reason(), ctl.Err())
+	}
+}
+
+func TestReasonMaxSolutions(t *testing.T) {
+	out, ctl, _ := Start(Config{LenItems: 10}, WithMaxSolutions(3))
+	for range out {
+	}
+	if ctl.Reason() != ReasonMaxSolutions {
+		t.Fatalf("expected the max solutions to end the search, got %v", ctl.Reason())
+	}
+}
+
+func TestReasonDeadline(t *testing.T) {
+	never := Constraint{Name: "never", Allow: func([]int) bool { return false }}
+	out, ctl, _ := Start(Config{LenItems: 40, Constraints: []Constraint{never}}, WithSolutionDeadline(time.Millisecond))
+	for range out {
+	}
+	if ctl.Reason() != ReasonDeadline || ctl.Err() != ErrSolutionDeadline {
+		t.Fatalf("expected the solution deadline to end the search, got %v, %v", ctl.Reason(), ctl.Err())
+	}
+}
+
+func TestReasonCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	out, ctl, _ := Start(Config{LenItems: 40}, WithContext(ctx))
+	<-out
+
+	// the search is blocked sending the next subset when it's cancelled
+	cancel()
+	for range out {
+	}
+	if ctl.Reason() != ReasonCancelled || !errors.Is(ctl.Err(), context.Canceled) {
+		t.Fatalf("expected a cancelled search, got %v, %v", ctl.Reason(), ctl.Err())
+	}
+	if ctl.Reason().String() != "cancelled" {
+		t.Fatalf("unexpected name %q", ctl.Reason().String())
+	}
+}
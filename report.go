@@ -0,0 +1,234 @@
+package powerset
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Report summarizes a search started with Start, for operators to attach to a ticket when a search behaves
+// unexpectedly.  it's best taken once the search has ended, but can be taken at any time
+type Report struct {
+	// the configuration the search was started with
+	Config Config
+
+	Started  time.Time
+	Duration time.Duration
+
+	// whether the search had ended when the report was taken
+	Finished bool
+
+	// the totals and the pruning breakdown, as of the end of the report
+	Stats Stats
+
+	// how far the search had got at regular intervals since it started, ending with the totals at the end of the
+	// report
+	Throughput []ThroughputSample
+
+	// how much of the Duration the search spent waiting on the consumer, a rate limit, or a pause, rather than
+	// searching.  Start searches on a single goroutine, so this is all that keeps it from being busy the whole time
+	Waiting time.Duration
+
+	// why the search ended, and the error that ended it early, if any
+	Reason Reason
+	Err    error
+}
+
+// ThroughputSample is how far a search had got at a point in time
+type ThroughputSample struct {
+	Elapsed time.Duration
+	Nodes   uint64
+	Emitted uint64
+}
+
+// the most throughput samples a report keeps.  once there are this many, every other one is dropped, and the interval
+// between samples doubles, so a long search has evenly spaced samples of its whole run
+const maxThroughputSamples = 256
+
+// the interval between throughput samples of a new search, and how many nodes are visited between looking at the clock
+const (
+	throughputInterval   = 100 * time.Millisecond
+	throughputCheckNodes = 1024
+)
+
+// recorder keeps what a Report needs beyond the Stats
+type recorder struct {
+	cfg     Config
+	started time.Time
+
+	// nanoseconds spent waiting, rather than searching
+	waiting atomic.Int64
+
+	mu       sync.Mutex
+	finished time.Time
+	interval time.Duration
+	samples  []ThroughputSample
+}
+
+func newRecorder(cfg Config) *recorder {
+	return &recorder{cfg: cfg, started: time.Now(), interval: throughputInterval}
+}
+
+// waited records time spent waiting since start
+func (r *recorder) waited(start time.Time) {
+	r.waiting.Add(int64(time.Since(start)))
+}
+
+// tick is called by the search at every node, and takes a throughput sample when one is due
+func (r *recorder) tick(stats *searchStats) {
+	nodes := stats.nodes.Load()
+	if nodes%throughputCheckNodes != 0 {
+		return
+	}
+
+	elapsed := time.Since(r.started)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.samples) > 0 && elapsed-r.samples[len(r.samples)-1].Elapsed < r.interval {
+		return
+	}
+	r.samples = append(r.samples, ThroughputSample{Elapsed: elapsed, Nodes: nodes, Emitted: stats.emitted.Load()})
+	if len(r.samples) == maxThroughputSamples {
+		for i := 0; i < len(r.samples)/2; i++ {
+			r.samples[i] = r.samples[2*i+1]
+		}
+		r.samples = r.samples[:len(r.samples)/2]
+		r.interval *= 2
+	}
+}
+
+// finish records the end of the search
+func (r *recorder) finish() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.finished = time.Now()
+}
+
+// Report returns a summary of the search so far
+func (ctl *Control) Report() Report {
+	r := ctl.recorder
+	stats := ctl.Stats()
+
+	r.mu.Lock()
+	end, finished := r.finished, !r.finished.IsZero()
+	samples := append([]ThroughputSample{}, r.samples...)
+	r.mu.Unlock()
+	if !finished {
+		end = time.Now()
+	}
+
+	report := Report{
+		Config:   r.cfg,
+		Started:  r.started,
+		Duration: end.Sub(r.started),
+		Finished: finished,
+		Stats:    stats,
+		Waiting:  time.Duration(r.waiting.Load()),
+		Reason:   ctl.Reason(),
+		Err:      ctl.Err(),
+	}
+	report.Throughput = append(samples, ThroughputSample{
+		Elapsed: report.Duration,
+		Nodes:   stats.Nodes,
+		Emitted: stats.Emitted,
+	})
+	return report
+}
+
+// String renders the report as text
+func (report Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "search %s after %v, started %v\n", report.Reason, report.Duration,
+		report.Started.Format(time.RFC3339))
+	if report.Err != nil {
+		fmt.Fprintf(&b, "ended early: %v\n", report.Err)
+	}
+
+	required, forbidden := report.Config.Required, report.Config.Forbidden
+	fmt.Fprintf(&b, "config: %d items, sizes %d to %d, required %v, forbidden %v, %d constraints\n",
+		report.Config.LenItems, report.Config.MinSize, report.Config.MaxSize, required, forbidden,
+		len(report.Config.Constraints))
+
+	stats := report.Stats
+	fmt.Fprintf(&b, "nodes %d, emitted %d, suppressed %d, pruned %d\n", stats.Nodes, stats.Emitted, stats.Suppressed,
+		stats.Pruned)
+	for _, rule := range sortedRules(stats.PrunedBy) {
+		fmt.Fprintf(&b, "  pruned by %s: %d\n", rule, stats.PrunedBy[rule])
+	}
+	fmt.Fprintf(&b, "waited %v\n", report.Waiting)
+
+	b.WriteString("throughput:\n")
+	var prev ThroughputSample
+	for _, sample := range report.Throughput {
+		rate := 0.0
+		if dt := (sample.Elapsed - prev.Elapsed).Seconds(); dt > 0 {
+			rate = float64(sample.Nodes-prev.Nodes) / dt
+		}
+		fmt.Fprintf(&b, "  %v: %d nodes, %d emitted, %.0f nodes/s\n", sample.Elapsed.Round(time.Millisecond),
+			sample.Nodes, sample.Emitted, rate)
+		prev = sample
+	}
+	return b.String()
+}
+
+// the rules in a pruning breakdown, with the ones that pruned the most first
+func sortedRules(prunedBy map[string]uint64) []string {
+	rules := make([]string, 0, len(prunedBy))
+	for rule := range prunedBy {
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if prunedBy[rules[i]] != prunedBy[rules[j]] {
+			return prunedBy[rules[i]] > prunedBy[rules[j]]
+		}
+		return rules[i] < rules[j]
+	})
+	return rules
+}
+
+// the JSON form of a Report.  the configuration's Go function constraints can't be encoded, so the configuration is
+// encoded without them, and every constraint is listed by name
+type reportJSON struct {
+	Config      json.RawMessage    `json:"config"`
+	Constraints []string           `json:"constraints,omitempty"`
+	Started     time.Time          `json:"started"`
+	DurationNS  int64              `json:"durationNs"`
+	Finished    bool               `json:"finished"`
+	Stats       Stats              `json:"stats"`
+	Throughput  []ThroughputSample `json:"throughput"`
+	WaitingNS   int64              `json:"waitingNs"`
+	Reason      string             `json:"reason"`
+	Err         string             `json:"error,omitempty"`
+}
+
+// MarshalJSON encodes the report
+func (report Report) MarshalJSON() ([]byte, error) {
+	cj := report.Config
+	cj.Constraints = nil
+	config, err := json.Marshal(cj)
+	if err != nil {
+		return nil, err
+	}
+
+	rj := reportJSON{
+		Config:     config,
+		Started:    report.Started,
+		DurationNS: int64(report.Duration),
+		Finished:   report.Finished,
+		Stats:      report.Stats,
+		Throughput: report.Throughput,
+		WaitingNS:  int64(report.Waiting),
+		Reason:     report.Reason.String(),
+	}
+	for _, c := range report.Config.Constraints {
+		rj.Constraints = append(rj.Constraints, c.Name)
+	}
+	if report.Err != nil {
+		rj.Err = report.Err.Error()
+	}
+	return json.Marshal(rj)
+}
@@ -0,0 +1,106 @@
+package powerset
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReport(t *testing.T) {
+	small := Constraint{Name: "small", Allow: func(indices []int) bool { return len(indices) < 3 }}
+	cfg := Config{LenItems: 12, Forbidden: []int{3}, Constraints: []Constraint{small}}
+	out, ctl, _ := Start(cfg)
+	for range out {
+	}
+	ctl.Stop()
+
+	report := ctl.Report()
+	if !report.Finished || report.Err != nil {
+		t.Fatalf("expected a finished search, got %+v", report)
+	}
+	if report.Config.LenItems != 12 {
+		t.Fatalf("expected the config, got %+v", report)
+	}
+	if report.Waiting < 0 || report.Waiting > report.Duration {
+		t.Fatalf("waited %v of %v", report.Waiting, report.Duration)
+	}
+	last := report.Throughput[len(report.Throughput)-1]
+	if last.Nodes != report.Stats.Nodes || last.Emitted != report.Stats.Emitted || last.Elapsed != report.Duration {
+		t.Fatalf("expected the last sample to be the totals, got %+v", last)
+	}
+
+	text := report.String()
+	lines := []string{"search completed", "12 items", "pruned by small", "pruned by Forbidden", "waited", "throughput:"}
+	for _, line := range lines {
+		if !strings.Contains(text, line) {
+			t.Fatalf("expected %q in\n%s", line, text)
+		}
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var decoded struct {
+		Config      Config
+		Constraints []string
+		Stats       Stats
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, data)
+	}
+	if decoded.Config.LenItems != 12 || len(decoded.Constraints) != 1 || decoded.Constraints[0] != "small" {
+		t.Fatalf("unexpected JSON\n%s", data)
+	}
+	if decoded.Stats.Emitted != report.Stats.Emitted {
+		t.Fatalf("emitted %d in JSON, expected %d", decoded.Stats.Emitted, report.Stats.Emitted)
+	}
+}
+
+func TestReportRunning(t *testing.T) {
+	out, ctl, _ := Start(Config{LenItems: 30})
+	<-out
+	report := ctl.Report()
+	ctl.Stop()
+
+	if report.Finished {
+		t.Fatalf("expected a running search")
+	}
+	if !strings.Contains(report.String(), "search running") {
+		t.Fatalf("expected a running search in\n%s", report)
+	}
+}
+
+func TestReportErr(t *testing.T) {
+	never := Constraint{Name: "never", Allow: func([]int) bool { return false }}
+	out, ctl, _ := Start(Config{LenItems: 40, Constraints: []Constraint{never}}, WithSolutionDeadline(time.Millisecond))
+	for range out {
+	}
+
+	report := ctl.Report()
+	if report.Err != ErrSolutionDeadline {
+		t.Fatalf("expected the deadline error, got %v", report.Err)
+	}
+	data, _ := json.Marshal(report)
+	if !strings.Contains(string(data), `"error":"`+ErrSolutionDeadline.Error()) {
+		t.Fatalf("expected the error in\n%s", data)
+	}
+}
+
+// the time spent blocked on a consumer that isn't reading is waiting
+func TestReportWaiting(t *testing.T) {
+	out, ctl, _ := Start(Config{LenItems: 4})
+	<-out
+	time.Sleep(50 * time.Millisecond)
+	for range out {
+	}
+
+	report := ctl.Report()
+	if report.Waiting < 40*time.Millisecond || report.Waiting > report.Duration {
+		t.Fatalf("waited %v of %v", report.Waiting, report.Duration)
+	}
+	if !strings.Contains(report.String(), "waited ") {
+		t.Fatalf("expected the waiting time in\n%s", report)
+	}
+}
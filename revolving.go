@@ -0,0 +1,98 @@
+package powerset
+
+import "sync"
+
+// CombinationStep is a subset in a minimal change order, with the single swap that led to it from the previous one.
+// the first subset has no previous one, so its Removed and Added are -1
+type CombinationStep struct {
+	Subset  []int
+	Removed int
+	Added   int
+}
+
+// CombinationsMinimalChange generates every subset of k of n items in the revolving door order, where each subset
+// differs from the previous one by swapping a single index out for another, so a score over a fixed size selection can
+// be updated from the swap instead of being recomputed.  each subset is a sorted slice of its indices.  the order is
+// defined recursively: the subsets without the last item in revolving door order, then the subsets with it, in the
+// reverse of the revolving door order of k-1 of the other n-1 items
+func CombinationsMinimalChange(n int, k int) (<-chan CombinationStep, func()) {
+	out := make(chan CombinationStep)
+	stopIn := make(chan bool)
+
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		defer close(out)
+		defer wg.Done()
+		if k < 0 || k > n {
+			return
+		}
+
+		// the items above the current sub-problem that every subset of it includes, from the largest
+		var above []int
+		var prev []int
+		emit := func(base int) bool {
+			subset := make([]int, 0, k)
+			for idx := 0; idx < base; idx++ {
+				subset = append(subset, idx)
+			}
+			for i := len(above) - 1; i >= 0; i-- {
+				subset = append(subset, above[i])
+			}
+
+			step := CombinationStep{Subset: subset, Removed: -1, Added: -1}
+			if prev != nil {
+				step.Removed, step.Added = swapBetween(prev, subset)
+			}
+			prev = subset
+			select {
+			case <-stopIn:
+				return false
+			case out <- step:
+				return true
+			}
+		}
+
+		var recurse func(n int, k int, reversed bool) bool
+		recurse = func(n int, k int, reversed bool) bool {
+			if k == 0 || k == n {
+				return emit(k)
+			}
+			without := func() bool { return recurse(n-1, k, reversed) }
+			with := func() bool {
+				above = append(above, n-1)
+				cont := recurse(n-1, k-1, !reversed)
+				above = above[:len(above)-1]
+				return cont
+			}
+			if reversed {
+				return with() && without()
+			}
+			return without() && with()
+		}
+		recurse(n, k, false)
+	}()
+
+	return out, makeStopper(stopIn, wg)
+}
+
+// swapBetween returns the index that is in a but not b, and the one that is in b but not a, for sorted subsets that
+// differ by a single swap
+func swapBetween(a []int, b []int) (removed int, added int) {
+	removed, added = -1, -1
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j == len(b) || (i < len(a) && a[i] < b[j]):
+			removed = a[i]
+			i++
+		case i == len(a) || b[j] < a[i]:
+			added = b[j]
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	return removed, added
+}
@@ -0,0 +1,69 @@
+package powerset
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func collectRevolving(n int, k int) []CombinationStep {
+	out, _ := CombinationsMinimalChange(n, k)
+	steps := []CombinationStep{}
+	for step := range out {
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+func TestCombinationsMinimalChange(t *testing.T) {
+	steps := collectRevolving(4, 2)
+	correct := []CombinationStep{
+		{[]int{0, 1}, -1, -1},
+		{[]int{1, 2}, 0, 2},
+		{[]int{0, 2}, 1, 0},
+		{[]int{2, 3}, 0, 3},
+		{[]int{1, 3}, 2, 1},
+		{[]int{0, 3}, 1, 0},
+	}
+	if !reflect.DeepEqual(steps, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", steps, correct)
+	}
+}
+
+func TestCombinationsMinimalChangeSwaps(t *testing.T) {
+	for n := 0; n <= 8; n++ {
+		for k := 0; k <= n; k++ {
+			steps := collectRevolving(n, k)
+			if c := binomial(n, k).Int64(); int64(len(steps)) != c {
+				t.Fatalf("C(%d, %d): expected %d subsets, got %d", n, k, c, len(steps))
+			}
+
+			seen := map[string]bool{}
+			for i, step := range steps {
+				key := fmt.Sprint(step.Subset)
+				if seen[key] || len(step.Subset) != k {
+					t.Fatalf("C(%d, %d): unexpected subset %v", n, k, step.Subset)
+				}
+				seen[key] = true
+				if i == 0 {
+					continue
+				}
+
+				// applying the swap to the previous subset gives this one
+				next := []int{}
+				for _, idx := range steps[i-1].Subset {
+					if idx != step.Removed {
+						next = append(next, idx)
+					}
+				}
+				next = append(next, step.Added)
+				if ToBigInt(next).Cmp(ToBigInt(step.Subset)) != 0 || step.Removed == step.Added {
+					t.Fatalf("C(%d, %d): %v is not one swap from %v", n, k, step.Subset, steps[i-1].Subset)
+				}
+			}
+		}
+	}
+	if steps := collectRevolving(3, 4); len(steps) != 0 {
+		t.Fatalf("expected nothing for k > n, got %v", steps)
+	}
+}
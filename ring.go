@@ -0,0 +1,113 @@
+package powerset
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// WithRingBuffer replaces the output channel of Start with a lock free single producer, single consumer
+// ring buffer holding size subsets, for users who have measured channel operations as the bottleneck at hundreds of
+// millions of subsets.  the search and the consumer only touch a pair of atomic counters on the hot path, and spin
+// briefly, then yield, while the ring is full or empty.  with a ring buffer, the channel returned by Start is nil, and
+// subsets are read with Control.Next from a single goroutine instead.  size is rounded up to a power of two
+func WithRingBuffer(size int) Option {
+	return func(o *options) {
+		o.ringSize = size
+	}
+}
+
+// Next returns the next subset of a search started with WithRingBuffer, blocking until there is one.  ok is false
+// once the search has ended and every subset has been read, and straight away if the search wasn't started with
+// WithRingBuffer.  Next must only be called from one goroutine at a time
+func (ctl *Control) Next() (subset []int, ok bool) {
+	if ctl.ring == nil {
+		return nil, false
+	}
+	return ctl.ring.pop()
+}
+
+// the size of a cache line, to keep the producer's and the consumer's counters from sharing one
+const cacheLine = 64
+
+// spscRing is a bounded queue for exactly one producer and one consumer.  head is only written by the consumer and
+// tail only by the producer, and a slot is handed over by publishing the counter after it's written
+type spscRing struct {
+	buf  [][]int
+	mask uint64
+
+	_    [cacheLine]byte
+	head atomic.Uint64
+	_    [cacheLine - 8]byte
+	tail atomic.Uint64
+	_    [cacheLine - 8]byte
+
+	closed atomic.Bool
+
+	// counts the subsets waiting in the ring, if not nil
+	memory *memoryMeter
+}
+
+func newSPSCRing(size int) *spscRing {
+	capacity := 1
+	for capacity < size {
+		capacity <<= 1
+	}
+	return &spscRing{buf: make([][]int, capacity), mask: uint64(capacity - 1)}
+}
+
+// backoff waits a little longer each time it's called in a row, spinning at first and then yielding to the scheduler
+type backoff int
+
+func (b *backoff) wait() {
+	if *b < 64 {
+		*b++
+		return
+	}
+	runtime.Gosched()
+}
+
+// push adds a subset, waiting while the ring is full.  returns false if stopIn was closed first
+func (r *spscRing) push(subset []int, stopIn <-chan bool) bool {
+	tail := r.tail.Load()
+	var b backoff
+	for tail-r.head.Load() == uint64(len(r.buf)) {
+		select {
+		case <-stopIn:
+			return false
+		default:
+		}
+		b.wait()
+	}
+	r.buf[tail&r.mask] = subset
+	if r.memory != nil {
+		r.memory.add(subsetBytes(subset))
+	}
+	r.tail.Store(tail + 1)
+	return true
+}
+
+// pop removes the oldest subset, waiting while the ring is empty.  returns false once the ring is closed and empty
+func (r *spscRing) pop() ([]int, bool) {
+	head := r.head.Load()
+	var b backoff
+	for head == r.tail.Load() {
+		// the producer closes the ring after its last push, so the ring is only finished if it's still empty after
+		// seeing it closed
+		if r.closed.Load() && head == r.tail.Load() {
+			return nil, false
+		}
+		b.wait()
+	}
+	subset := r.buf[head&r.mask]
+	r.buf[head&r.mask] = nil
+	if r.memory != nil {
+		r.memory.add(-subsetBytes(subset))
+	}
+	r.head.Store(head + 1)
+	return subset, true
+}
+
+// close tells the consumer that nothing more will be pushed
+func (r *spscRing) close() {
+	r.closed.Store(true)
+}
@@ -0,0 +1,55 @@
+package powerset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRingBuffer(t *testing.T) {
+	cfg := Config{LenItems: 10, MaxSize: 4, Forbidden: []int{2}}
+	correct := collectFamily(t, cfg)
+
+	for _, size := range []int{1, 3, 1024} {
+		out, ctl, err := Start(cfg, WithRingBuffer(size))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out != nil {
+			t.Fatalf("expected no output channel with a ring buffer")
+		}
+
+		allValues := [][]int{}
+		for subset, ok := ctl.Next(); ok; subset, ok = ctl.Next() {
+			allValues = append(allValues, subset)
+		}
+		if !reflect.DeepEqual(allValues, correct) {
+			t.Fatalf("ring of %d:\n%v\n\n!=\n\n%v", size, allValues, correct)
+		}
+		if ctl.Stats().Emitted != uint64(len(correct)) {
+			t.Fatalf("emitted %d, expected %d", ctl.Stats().Emitted, len(correct))
+		}
+	}
+}
+
+func TestRingBufferStop(t *testing.T) {
+	_, ctl, _ := Start(Config{LenItems: 40}, WithRingBuffer(4))
+	ctl.Next()
+	ctl.Stop()
+
+	// whatever was already in the ring is still delivered, then the ring is finished
+	for i := 0; i < 8; i++ {
+		if _, ok := ctl.Next(); !ok {
+			return
+		}
+	}
+	t.Fatalf("expected the ring to be finished after Stop")
+}
+
+func TestRingBufferWithoutOption(t *testing.T) {
+	out, ctl, _ := Start(Config{LenItems: 2})
+	if _, ok := ctl.Next(); ok {
+		t.Fatalf("expected Next to fail without a ring buffer")
+	}
+	for range out {
+	}
+}
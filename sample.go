@@ -0,0 +1,177 @@
+package powerset
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"sync"
+)
+
+// the most draws SampleFamily makes per sample before deciding that the Constraints reject nearly everything
+const sampleAttempts = 1000
+
+// SampleFamily draws count members of the family described by cfg uniformly at random, with replacement, without
+// generating the family.  a size is drawn with a probability proportional to how many members of the family have it,
+// which is an exact binomial coefficient, and then one of the combinations of free indices of that size is drawn by its
+// rank.  Constraints and shards can't be counted like that, so members that break one are drawn again, which keeps the
+// samples uniform, but returns an error if they reject almost everything.  an empty family returns ErrNoSolution
+func SampleFamily(cfg Config, count int, rng *rand.Rand) ([][]int, error) {
+	fam, err := cfg.compile()
+	if err != nil {
+		return nil, err
+	}
+	if fam.empty {
+		return nil, ErrNoSolution
+	}
+
+	// the free indices, and the number of members of each size from lo to hi
+	freeIndices := []int{}
+	for idx, m := range fam.members {
+		if m == free {
+			freeIndices = append(freeIndices, idx)
+		}
+	}
+	lo, hi := fam.sizeRange()
+	bySize := []*big.Int{}
+	total := new(big.Int)
+	for k := lo; k <= hi; k++ {
+		n := binomial(len(freeIndices), k-fam.numRequired)
+		bySize = append(bySize, n)
+		total.Add(total, n)
+	}
+	if total.Sign() == 0 {
+		return nil, ErrNoSolution
+	}
+
+	draw := func() []int {
+		r := new(big.Int).Rand(rng, total)
+		k := 0
+		for r.Cmp(bySize[k]) >= 0 {
+			r.Sub(r, bySize[k])
+			k++
+		}
+		return fam.withFree(freeIndices, unrankCombination(r, len(freeIndices), lo+k-fam.numRequired))
+	}
+
+	samples := make([][]int, 0, count)
+	for len(samples) < count {
+		found := false
+		for attempt := 0; attempt < sampleAttempts; attempt++ {
+			subset := draw()
+			if ok, _ := fam.contains(subset); ok {
+				samples = append(samples, subset)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return samples, fmt.Errorf("powerset: the constraints rejected %d random members in a row", sampleAttempts)
+		}
+	}
+	return samples, nil
+}
+
+// withFree returns the sorted subset of the required indices and the chosen positions of freeIndices
+func (fam *family) withFree(freeIndices []int, chosen []int) []int {
+	subset := make([]int, 0, fam.numRequired+len(chosen))
+	next := 0
+	for idx, m := range fam.members {
+		switch {
+		case m == required:
+			subset = append(subset, idx)
+		case next < len(chosen) && freeIndices[chosen[next]] == idx:
+			subset = append(subset, idx)
+			next++
+		}
+	}
+	return subset
+}
+
+// Sample draws count subsets of n items uniformly at random, with replacement, and sends them on the returned channel
+// as sorted included indices, closing it after the last one.  including each index with probability 1/2 makes every
+// one of the 2^n subsets equally likely, so nothing is enumerated and n can be as large as memory allows, for Monte
+// Carlo estimates over subset spaces.  the draws come from rng, which must not be used elsewhere until the channel is
+// closed, and with the same seed produce the same samples.  the returned function stops the sampling early
+func Sample(n int, count int, rng *rand.Rand) (<-chan []int, func()) {
+	out := make(chan []int)
+	stopIn := make(chan bool)
+
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		defer close(out)
+		defer wg.Done()
+		for i := 0; i < count; i++ {
+			subset := []int{}
+			var bits uint64
+			for idx := 0; idx < n; idx++ {
+				// one draw decides 64 indices
+				if idx%64 == 0 {
+					bits = rng.Uint64()
+				}
+				if bits&1 == 1 {
+					subset = append(subset, idx)
+				}
+				bits >>= 1
+			}
+			select {
+			case <-stopIn:
+				return
+			case out <- subset:
+			}
+		}
+	}()
+	return out, makeStopper(stopIn, wg)
+}
+
+// SampleBernoulli is Sample where each index i is included with probability p[i], independently of the others, for
+// random configurations in which some items are much more likely than others.  the subsets are of len(p) items, and
+// it's an error if a probability isn't from 0 to 1
+func SampleBernoulli(p []float64, count int, rng *rand.Rand) (<-chan []int, func(), error) {
+	for i, prob := range p {
+		if !(prob >= 0 && prob <= 1) {
+			return nil, nil, fmt.Errorf("powerset: probability %d is %v, not from 0 to 1", i, prob)
+		}
+	}
+
+	out := make(chan []int)
+	stopIn := make(chan bool)
+
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		defer close(out)
+		defer wg.Done()
+		for i := 0; i < count; i++ {
+			subset := []int{}
+			for idx, prob := range p {
+				if rng.Float64() < prob {
+					subset = append(subset, idx)
+				}
+			}
+			select {
+			case <-stopIn:
+				return
+			case out <- subset:
+			}
+		}
+	}()
+	return out, makeStopper(stopIn, wg), nil
+}
+
+// unrankCombination returns the k-subset of n items with the given rank in lexicographic order of their sorted
+// indices.  the subsets that include the first item come first, and there are C(n-1, k-1) of them
+func unrankCombination(rank *big.Int, n int, k int) []int {
+	r := new(big.Int).Set(rank)
+	chosen := make([]int, 0, k)
+	for i := 0; i < n && k > 0; i++ {
+		with := binomial(n-1-i, k-1)
+		if r.Cmp(with) < 0 {
+			chosen = append(chosen, i)
+			k--
+		} else {
+			r.Sub(r, with)
+		}
+	}
+	return chosen
+}
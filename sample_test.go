@@ -0,0 +1,175 @@
+package powerset
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSampleFamilyUniform(t *testing.T) {
+	noThree := Constraint{
+		Name:  "no three",
+		Allow: func(indices []int) bool { return len(indices) != 3 },
+	}
+	cfg := Config{LenItems: 5, MinSize: 2, Required: []int{1}, Forbidden: []int{4}, Constraints: []Constraint{noThree}}
+	members := map[string]bool{}
+	for _, subset := range collectFamily(t, cfg) {
+		members[fmt.Sprint(subset)] = true
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	perMember := 2000
+	samples, err := SampleFamily(cfg, perMember*len(members), rng)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	counts := map[string]int{}
+	for _, subset := range samples {
+		key := fmt.Sprint(subset)
+		if !members[key] {
+			t.Fatalf("%v isn't a member", subset)
+		}
+		counts[key]++
+	}
+	if len(counts) != len(members) {
+		t.Fatalf("expected every one of the %d members to be sampled, got %d", len(members), len(counts))
+	}
+	for key, count := range counts {
+		if count < perMember*8/10 || count > perMember*12/10 {
+			t.Fatalf("%s was sampled %d times, expected about %d", key, count, perMember)
+		}
+	}
+}
+
+func TestSampleFamilyHuge(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	samples, err := SampleFamily(Config{LenItems: 500, MinSize: 10, MaxSize: 20}, 10, rng)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, subset := range samples {
+		if len(subset) < 10 || len(subset) > 20 {
+			t.Fatalf("unexpected size %d", len(subset))
+		}
+	}
+}
+
+func TestSampleFamilyErrors(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	empty := Config{LenItems: 3, MinSize: 3, Forbidden: []int{0}}
+	if _, err := SampleFamily(empty, 1, rng); !errors.Is(err, ErrNoSolution) {
+		t.Fatalf("expected ErrNoSolution, got %v", err)
+	}
+	never := Constraint{Name: "never", Allow: func([]int) bool { return false }}
+	if _, err := SampleFamily(Config{LenItems: 3, Constraints: []Constraint{never}}, 1, rng); err == nil {
+		t.Fatalf("expected an error when every member is rejected")
+	}
+}
+
+func TestSampleUniform(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	counts := map[string]int{}
+	out, _ := Sample(4, 16000, rng)
+	for subset := range out {
+		counts[fmt.Sprint(subset)]++
+	}
+	if len(counts) != 16 {
+		t.Fatalf("sampled %d distinct subsets, expected 16", len(counts))
+	}
+	for key, count := range counts {
+		if count < 800 || count > 1200 {
+			t.Fatalf("%s was sampled %d times, expected about 1000", key, count)
+		}
+	}
+}
+
+func TestSampleLarge(t *testing.T) {
+	sizes := 0
+	out, _ := Sample(1000, 20, rand.New(rand.NewSource(2)))
+	for subset := range out {
+		if !sort.IntsAreSorted(subset) || (len(subset) > 0 && subset[len(subset)-1] >= 1000) {
+			t.Fatalf("unexpected subset %v", subset)
+		}
+		sizes += len(subset)
+	}
+	// about half of the items are included in each sample
+	if sizes < 20*450 || sizes > 20*550 {
+		t.Fatalf("the samples hold %d indices, expected about %d", sizes, 20*500)
+	}
+
+	first, second := [][]int{}, [][]int{}
+	out, _ = Sample(100, 3, rand.New(rand.NewSource(3)))
+	for subset := range out {
+		first = append(first, subset)
+	}
+	out, _ = Sample(100, 3, rand.New(rand.NewSource(3)))
+	for subset := range out {
+		second = append(second, subset)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("the same seed should draw the same samples")
+	}
+}
+
+func TestSampleStop(t *testing.T) {
+	out, stop := Sample(10, 1000, rand.New(rand.NewSource(4)))
+	<-out
+	stop()
+}
+
+func TestSampleBernoulli(t *testing.T) {
+	p := []float64{0, 0.1, 0.5, 0.9, 1}
+	out, _, err := SampleBernoulli(p, 10000, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	included := make([]int, len(p))
+	for subset := range out {
+		for _, idx := range subset {
+			included[idx]++
+		}
+	}
+	for idx, prob := range p {
+		expected := prob * 10000
+		if float64(included[idx]) < expected-300 || float64(included[idx]) > expected+300 {
+			t.Fatalf("index %d was included %d times, expected about %v", idx, included[idx], expected)
+		}
+	}
+	if included[0] != 0 || included[4] != 10000 {
+		t.Fatalf("indices of probability 0 and 1 were included %d and %d times", included[0], included[4])
+	}
+
+	for _, invalid := range [][]float64{{0.5, -0.1}, {1.5}, {math.NaN()}} {
+		if _, _, err := SampleBernoulli(invalid, 1, rand.New(rand.NewSource(1))); err == nil {
+			t.Fatalf("expected an error for %v", invalid)
+		}
+	}
+}
+
+func TestSampleBernoulliStop(t *testing.T) {
+	out, stop, err := SampleBernoulli([]float64{0.5, 0.5}, 1000, rand.New(rand.NewSource(4)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-out
+	stop()
+}
+
+func TestSampleFamilyShard(t *testing.T) {
+	cfg := Config{LenItems: 6, Shard: &ShardSpec{Count: 4, Index: 2}, Order: OrderGray}
+	samples, err := SampleFamily(cfg, 100, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the third of 4 shards of 6 items is every subset that includes 0 and not 1
+	for _, subset := range samples {
+		if len(subset) == 0 || subset[0] != 0 || (len(subset) > 1 && subset[1] == 1) {
+			t.Fatalf("%v isn't in the shard", subset)
+		}
+	}
+}
@@ -0,0 +1,381 @@
+package powerset
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// ErrUnsatisfiable is wrapped by the *UnsatisfiableError of a family that a feasibility check proved empty
+var ErrUnsatisfiable = errors.New("powerset: the constraints can't be satisfied")
+
+// UnsatisfiableError is returned by CheckFeasible, and by Start with WithFeasibilityCheck, for a family that has no
+// members.  Core is a set of rules that can't hold together, and that can each be satisfied once any other is dropped.
+// the rules are "MinSize", "MaxSize", "Required", "Forbidden", the Names of parsed constraints and "Shard"
+type UnsatisfiableError struct {
+	Core []string
+}
+
+func (err *UnsatisfiableError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrUnsatisfiable, strings.Join(err.Core, ", "))
+}
+
+func (err *UnsatisfiableError) Unwrap() error {
+	return ErrUnsatisfiable
+}
+
+// Solver decides whether a formula in conjunctive normal form can be satisfied.  the variables are numbered from 1 to
+// numVars, and each clause is a disjunction of literals, where v is variable v and -v is its negation, as in the DIMACS
+// format.  this lets a feasibility check use an external SAT solver in place of the built in DPLL
+type Solver interface {
+	Solve(numVars int, clauses [][]int) bool
+}
+
+// DPLL is a small Davis-Putnam-Logemann-Loveland solver, for families with up to a few dozen items
+type DPLL struct{}
+
+// WithFeasibilityCheck makes Start prove that the family has at least one member, with solver, before it starts
+// searching.  if it doesn't, Start returns an *UnsatisfiableError.  a nil solver is DPLL
+func WithFeasibilityCheck(solver Solver) Option {
+	return func(o *options) {
+		if solver == nil {
+			solver = DPLL{}
+		}
+		o.solver = solver
+	}
+}
+
+// CheckFeasible proves whether the family described by cfg is empty, without searching it, returning an
+// *UnsatisfiableError if it is.  only the size bounds, required and forbidden indices, the shard, and constraints made
+// by ParseConstraint are considered, so a family whose other Constraints reject every subset still passes.  a nil
+// solver is DPLL
+func CheckFeasible(cfg Config, solver Solver) error {
+	fam, err := cfg.compile()
+	if err != nil {
+		return err
+	}
+	if solver == nil {
+		solver = DPLL{}
+	}
+	return fam.feasible(solver)
+}
+
+// a rule of a family, as a set of clauses
+type satRule struct {
+	name    string
+	clauses [][]int
+}
+
+// feasible encodes the family's rules as clauses and solves them.  when they can't be satisfied, every rule is dropped
+// in turn, and kept out if the rest still can't be satisfied, which leaves a minimal core
+func (fam *family) feasible(solver Solver) error {
+	enc := newSATEncoder(fam.lenItems)
+	rules := []satRule{}
+	add := func(name string, clauses ...[]int) {
+		if len(clauses) > 0 {
+			rules = append(rules, satRule{name, clauses})
+		}
+	}
+
+	included, excluded := [][]int{}, [][]int{}
+	for idx, m := range fam.members {
+		switch m {
+		case required:
+			included = append(included, []int{enc.item(idx)})
+		case forbidden:
+			excluded = append(excluded, []int{-enc.item(idx)})
+		}
+	}
+	add("Required", included...)
+	add("Forbidden", excluded...)
+	if fam.minSize > 0 {
+		add("MinSize", []int{enc.atLeast(fam.minSize)})
+	}
+	if fam.maxSize < fam.lenItems {
+		add("MaxSize", []int{-enc.atLeast(fam.maxSize + 1)})
+	}
+	for _, c := range fam.exprs {
+		add(c.Name, []int{enc.lit(c.expr)})
+	}
+	if fam.shard != nil {
+		add(shardRule, enc.rankBetween(fam.shard.lo, fam.shard.hi)...)
+	}
+
+	solve := func(rules []satRule) bool {
+		clauses := append([][]int{}, enc.clauses...)
+		for _, r := range rules {
+			clauses = append(clauses, r.clauses...)
+		}
+		return solver.Solve(enc.numVars, clauses)
+	}
+	if solve(rules) {
+		return nil
+	}
+
+	core := rules
+	for i := 0; i < len(core); {
+		without := append(append([]satRule{}, core[:i]...), core[i+1:]...)
+		if !solve(without) {
+			core = without
+		} else {
+			i++
+		}
+	}
+	names := make([]string, len(core))
+	for i, r := range core {
+		names[i] = r.name
+	}
+	return &UnsatisfiableError{Core: names}
+}
+
+// satEncoder turns a family's rules into clauses.  variables 1 to lenItems are the items, and the rest are introduced
+// by the Tseitin encoding of expressions and by the counter that cardinalities are read from.  clauses holds the
+// definitions of the introduced variables, which can always be satisfied
+type satEncoder struct {
+	lenItems int
+	numVars  int
+	clauses  [][]int
+
+	// the variable that is always true
+	top int
+
+	// counter[i][j-1] is true when at least j of the first i+1 items are included
+	counter [][]int
+}
+
+func newSATEncoder(lenItems int) *satEncoder {
+	enc := &satEncoder{lenItems: lenItems, numVars: lenItems}
+	enc.top = enc.newVar()
+	enc.clauses = append(enc.clauses, []int{enc.top})
+	return enc
+}
+
+func (enc *satEncoder) newVar() int {
+	enc.numVars++
+	return enc.numVars
+}
+
+func (enc *satEncoder) item(idx int) int {
+	return idx + 1
+}
+
+// and returns a variable that is true exactly when every one of lits is
+func (enc *satEncoder) and(lits ...int) int {
+	v := enc.newVar()
+	all := []int{v}
+	for _, lit := range lits {
+		enc.clauses = append(enc.clauses, []int{-v, lit})
+		all = append(all, -lit)
+	}
+	enc.clauses = append(enc.clauses, all)
+	return v
+}
+
+// or returns a variable that is true exactly when any of lits is
+func (enc *satEncoder) or(lits ...int) int {
+	v := enc.newVar()
+	some := []int{-v}
+	for _, lit := range lits {
+		enc.clauses = append(enc.clauses, []int{v, -lit})
+		some = append(some, lit)
+	}
+	enc.clauses = append(enc.clauses, some)
+	return v
+}
+
+// atLeast returns a literal that is true when at least k items are included.  the counter behind it is built the
+// first time it's needed, as a sequential counter where at least j of the first i items are included when at least j of
+// the first i-1 are, or at least j-1 of them are and item i is too
+func (enc *satEncoder) atLeast(k int) int {
+	if k <= 0 {
+		return enc.top
+	}
+	if k > enc.lenItems {
+		return -enc.top
+	}
+
+	if enc.counter == nil {
+		enc.counter = make([][]int, enc.lenItems)
+		for i := range enc.counter {
+			x := enc.item(i)
+			enc.counter[i] = make([]int, i+1)
+			for j := 1; j <= i+1; j++ {
+				switch {
+				case i == 0:
+					enc.counter[i][j-1] = x
+				case j == 1:
+					enc.counter[i][j-1] = enc.or(enc.counter[i-1][0], x)
+				case j == i+1:
+					enc.counter[i][j-1] = enc.and(enc.counter[i-1][j-2], x)
+				default:
+					enc.counter[i][j-1] = enc.or(enc.counter[i-1][j-1], enc.and(enc.counter[i-1][j-2], x))
+				}
+			}
+		}
+	}
+	return enc.counter[enc.lenItems-1][k-1]
+}
+
+// rankBetween returns clauses that hold exactly when the rank of the included items is from lo to hi, inclusive.  the
+// rank is the binary number whose most significant bit is item 0, and it's at least lo unless, at some bit set in lo,
+// the item is excluded and so is every item before it that lo doesn't set.  at most hi is the same, with the bits and
+// items flipped
+func (enc *satEncoder) rankBetween(lo, hi *big.Int) [][]int {
+	if lo.Cmp(hi) > 0 {
+		return [][]int{{-enc.top}}
+	}
+	clauses := [][]int{}
+	atLeast, atMost := []int{}, []int{}
+	for i := 0; i < enc.lenItems; i++ {
+		x := enc.item(i)
+		bit := enc.lenItems - 1 - i
+		if lo.Bit(bit) == 1 {
+			clauses = append(clauses, append(append([]int{}, atLeast...), x))
+		} else {
+			atLeast = append(atLeast, x)
+		}
+		if hi.Bit(bit) == 0 {
+			clauses = append(clauses, append(append([]int{}, atMost...), -x))
+		} else {
+			atMost = append(atMost, -x)
+		}
+	}
+	return clauses
+}
+
+// lit returns a literal that is true exactly when the expression is
+func (enc *satEncoder) lit(n exprNode) int {
+	switch n := n.(type) {
+	case exprItem:
+		return enc.item(int(n))
+	case exprConst:
+		if n {
+			return enc.top
+		}
+		return -enc.top
+	case exprNot:
+		return -enc.lit(n.x)
+	case exprCount:
+		if n.hi < 0 {
+			return enc.atLeast(n.lo)
+		}
+		return enc.and(enc.atLeast(n.lo), -enc.atLeast(n.hi+1))
+	case exprBinary:
+		x, y := enc.lit(n.x), enc.lit(n.y)
+		switch n.op {
+		case "&":
+			return enc.and(x, y)
+		case "|":
+			return enc.or(x, y)
+		case "->":
+			return enc.or(-x, y)
+		default:
+			return enc.or(enc.and(x, y), enc.and(-x, -y))
+		}
+	}
+	panic(fmt.Sprintf("powerset: can't encode %T", n))
+}
+
+// Solve searches for an assignment by unit propagation, branching on the lowest unassigned variable, true first, and
+// backtracking on conflicts
+func (DPLL) Solve(numVars int, clauses [][]int) bool {
+	d := &dpll{
+		clauses: clauses,
+		value:   make([]int8, numVars+1),
+	}
+	return d.search()
+}
+
+type dpll struct {
+	clauses [][]int
+
+	// value[v] is 1 when variable v is true, -1 when it's false, and 0 when it's unassigned
+	value []int8
+
+	// the variables in the order they were assigned, for backtracking
+	trail []int
+}
+
+func (d *dpll) assign(lit int) {
+	if lit > 0 {
+		d.value[lit] = 1
+		d.trail = append(d.trail, lit)
+	} else {
+		d.value[-lit] = -1
+		d.trail = append(d.trail, -lit)
+	}
+}
+
+func (d *dpll) eval(lit int) int8 {
+	if lit > 0 {
+		return d.value[lit]
+	}
+	return -d.value[-lit]
+}
+
+func (d *dpll) undo(mark int) {
+	for _, v := range d.trail[mark:] {
+		d.value[v] = 0
+	}
+	d.trail = d.trail[:mark]
+}
+
+// propagate assigns the last literal of every clause whose other literals are false, until there are none left,
+// returning false if a clause has every literal false
+func (d *dpll) propagate() bool {
+	for changed := true; changed; {
+		changed = false
+		for _, clause := range d.clauses {
+			unassigned, last := 0, 0
+			satisfied := false
+			for _, lit := range clause {
+				switch d.eval(lit) {
+				case 1:
+					satisfied = true
+				case 0:
+					unassigned++
+					last = lit
+				}
+				if satisfied {
+					break
+				}
+			}
+			switch {
+			case satisfied:
+			case unassigned == 0:
+				return false
+			case unassigned == 1:
+				d.assign(last)
+				changed = true
+			}
+		}
+	}
+	return true
+}
+
+func (d *dpll) search() bool {
+	mark := len(d.trail)
+	if !d.propagate() {
+		d.undo(mark)
+		return false
+	}
+
+	v := 1
+	for v < len(d.value) && d.value[v] != 0 {
+		v++
+	}
+	if v == len(d.value) {
+		return true
+	}
+	for _, lit := range []int{v, -v} {
+		branch := len(d.trail)
+		d.assign(lit)
+		if d.search() {
+			return true
+		}
+		d.undo(branch)
+	}
+	d.undo(mark)
+	return false
+}
@@ -0,0 +1,184 @@
+package powerset
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestCheckFeasible(t *testing.T) {
+	items, _ := NewItems("a", "b", "c", "d", "e")
+	cfg := Config{
+		LenItems: 5,
+		MinSize:  2,
+		MaxSize:  3,
+		Required: []int{4},
+		Constraints: []Constraint{
+			mustParse(t, "a -> (b <-> !c)", items),
+			mustParse(t, "count(3) | d", items),
+			mustParse(t, "!(a & d)", items),
+		},
+	}
+	if err := CheckFeasible(cfg, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCheckFeasibleCore(t *testing.T) {
+	items, _ := NewItems("a", "b", "c", "d")
+	cfg := Config{
+		LenItems: 4,
+		MaxSize:  2,
+		Required: []int{0},
+		Constraints: []Constraint{
+			mustParse(t, "b | c", items),
+			mustParse(t, "a -> d", items),
+			mustParse(t, "!count(4)", items),
+		},
+	}
+
+	err := CheckFeasible(cfg, nil)
+	if !errors.Is(err, ErrUnsatisfiable) {
+		t.Fatalf("expected ErrUnsatisfiable, got %v", err)
+	}
+	var unsat *UnsatisfiableError
+	if !errors.As(err, &unsat) {
+		t.Fatalf("expected an *UnsatisfiableError, got %T", err)
+	}
+	// a is required, so d is too, and b or c would be one item too many
+	correct := []string{"Required", "MaxSize", "b | c", "a -> d"}
+	if !reflect.DeepEqual(unsat.Core, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", unsat.Core, correct)
+	}
+}
+
+func TestCheckFeasibleMinimalCore(t *testing.T) {
+	items, _ := NewItems("a", "b", "c")
+	cfg := Config{
+		LenItems:  3,
+		Forbidden: []int{2},
+		Constraints: []Constraint{
+			mustParse(t, "a | c", items),
+			mustParse(t, "b", items),
+			mustParse(t, "!a", items),
+		},
+	}
+
+	var unsat *UnsatisfiableError
+	if err := CheckFeasible(cfg, nil); !errors.As(err, &unsat) {
+		t.Fatalf("expected an *UnsatisfiableError, got %v", err)
+	}
+	correct := []string{"Forbidden", "a | c", "!a"}
+	if !reflect.DeepEqual(unsat.Core, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", unsat.Core, correct)
+	}
+}
+
+// every Config over a few items agrees with whether Family generates anything
+func TestCheckFeasibleAgreesWithFamily(t *testing.T) {
+	items, _ := NewItems("a", "b", "c", "d")
+	exprs := []string{"a & !b", "count(3..)", "b <-> c", "!d", "a | d", "count(..1)"}
+	for mask := 0; mask < 1<<len(exprs); mask++ {
+		for _, bounds := range [][2]int{{0, 0}, {2, 3}, {1, 1}} {
+			cfg := Config{LenItems: 4, MinSize: bounds[0], MaxSize: bounds[1]}
+			for i, expr := range exprs {
+				if mask&(1<<i) != 0 {
+					cfg.Constraints = append(cfg.Constraints, mustParse(t, expr, items))
+				}
+			}
+
+			empty := len(collectFamily(t, cfg)) == 0
+			err := CheckFeasible(cfg, nil)
+			if empty != (err != nil) {
+				t.Fatalf("%v: family is empty: %v, but got %v", cfg.Constraints, empty, err)
+			}
+		}
+	}
+}
+
+type countingSolver struct {
+	calls int
+}
+
+func (s *countingSolver) Solve(numVars int, clauses [][]int) bool {
+	s.calls++
+	return DPLL{}.Solve(numVars, clauses)
+}
+
+func TestWithFeasibilityCheck(t *testing.T) {
+	items, _ := NewItems("a", "b")
+	cfg := Config{
+		LenItems:    2,
+		Constraints: []Constraint{mustParse(t, "a & b", items), mustParse(t, "!a", items)},
+	}
+	solver := &countingSolver{}
+	_, _, err := Start(cfg, WithFeasibilityCheck(solver))
+	if !errors.Is(err, ErrUnsatisfiable) {
+		t.Fatalf("expected ErrUnsatisfiable, got %v", err)
+	}
+	if solver.calls == 0 {
+		t.Fatalf("expected the solver to be used")
+	}
+
+	cfg.Constraints = cfg.Constraints[:1]
+	out, _, err := Start(cfg, WithFeasibilityCheck(nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	subsets := [][]int{}
+	for subset := range out {
+		subsets = append(subsets, subset)
+	}
+	if !reflect.DeepEqual(subsets, [][]int{{0, 1}}) {
+		t.Fatalf("unexpected subsets %v", subsets)
+	}
+}
+
+func TestCheckFeasibleShard(t *testing.T) {
+	// the second of 4 shards of 4 items is every subset that includes 1 and not 0
+	cfg := Config{LenItems: 4, Shard: &ShardSpec{Count: 4, Index: 1}, Order: OrderGray}
+	if err := CheckFeasible(cfg, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg.Forbidden = []int{1}
+	var unsat *UnsatisfiableError
+	if err := CheckFeasible(cfg, nil); !errors.As(err, &unsat) {
+		t.Fatalf("expected an *UnsatisfiableError, got %v", err)
+	}
+	correct := []string{"Forbidden", "Shard"}
+	if !reflect.DeepEqual(unsat.Core, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", unsat.Core, correct)
+	}
+}
+
+// the clauses of a rank interval hold for exactly the subsets whose rank is in it
+func TestRankBetween(t *testing.T) {
+	n := 4
+	for lo := int64(0); lo < 1<<n; lo++ {
+		for hi := lo; hi < 1<<n; hi++ {
+			enc := newSATEncoder(n)
+			clauses := enc.rankBetween(big.NewInt(lo), big.NewInt(hi))
+			for rank := int64(0); rank < 1<<n; rank++ {
+				// item i is bit n-1-i of the rank
+				holds := true
+				for _, clause := range clauses {
+					satisfied := false
+					for _, lit := range clause {
+						idx := lit
+						if idx < 0 {
+							idx = -idx
+						}
+						in := rank>>(n-idx)&1 == 1
+						satisfied = satisfied || in == (lit > 0)
+					}
+					holds = holds && satisfied
+				}
+				if holds != (rank >= lo && rank <= hi) {
+					t.Fatalf("[%d, %d] at rank %d: %v", lo, hi, rank, holds)
+				}
+			}
+		}
+	}
+}
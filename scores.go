@@ -0,0 +1,142 @@
+package powerset
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// the number of scores WithScoreHistogram keeps when it isn't given a reservoir size
+const defaultScoreReservoir = 1024
+
+// WithScoreHistogram makes the Stats of a search describe the distribution of score over the subsets it emits, so you
+// can see the landscape of the space before committing to thresholds for pruning.  the count, minimum, maximum and mean
+// are exact, and quantiles and histograms are estimated from a uniform sample of reservoir of the scores, kept by
+// reservoir sampling, so memory stays bounded however many subsets are emitted.  a reservoir of zero or less is 1024.
+// score is called once for every subset that's emitted, and must not retain it
+func WithScoreHistogram(score func(subset []int) float64, reservoir int) Option {
+	return func(o *options) {
+		if reservoir <= 0 {
+			reservoir = defaultScoreReservoir
+		}
+		o.score = score
+		o.scoreReservoir = reservoir
+	}
+}
+
+// ScoreStats is the distribution of the scores of the subsets a search emitted
+type ScoreStats struct {
+	Count          uint64
+	Min, Max, Mean float64
+
+	// the sampled scores, sorted
+	sample []float64
+}
+
+// ScoreBucket is one bucket of a histogram of scores, with an estimate of how many scores were from Lo to Hi.  every
+// bucket but the last excludes Hi
+type ScoreBucket struct {
+	Lo, Hi float64
+	Count  uint64
+}
+
+// Quantile estimates the score that a fraction q of the emitted subsets score at most, so Quantile(0.5) is the
+// median.  it's zero if nothing was emitted
+func (s *ScoreStats) Quantile(q float64) float64 {
+	if len(s.sample) == 0 {
+		return 0
+	}
+	idx := int(q * float64(len(s.sample)))
+	switch {
+	case idx < 0:
+		idx = 0
+	case idx >= len(s.sample):
+		idx = len(s.sample) - 1
+	}
+	return s.sample[idx]
+}
+
+// Histogram estimates the number of emitted subsets in each of buckets buckets of equal width from Min to Max, by
+// scaling up the counts of the sample.  it's nil if nothing was emitted
+func (s *ScoreStats) Histogram(buckets int) []ScoreBucket {
+	if len(s.sample) == 0 || buckets < 1 {
+		return nil
+	}
+	if s.Min == s.Max {
+		buckets = 1
+	}
+	width := (s.Max - s.Min) / float64(buckets)
+	hist := make([]ScoreBucket, buckets)
+	for i := range hist {
+		hist[i].Lo = s.Min + float64(i)*width
+		hist[i].Hi = s.Min + float64(i+1)*width
+	}
+	hist[buckets-1].Hi = s.Max
+
+	sampled := make([]int, buckets)
+	for _, score := range s.sample {
+		i := buckets - 1
+		if width > 0 {
+			i = min(int((score-s.Min)/width), buckets-1)
+		}
+		sampled[i]++
+	}
+	scale := float64(s.Count) / float64(len(s.sample))
+	for i, count := range sampled {
+		hist[i].Count = uint64(math.Round(float64(count) * scale))
+	}
+	return hist
+}
+
+// scoreReservoir is the live, concurrently recordable version of ScoreStats.  it keeps a uniform sample of the scores
+// with algorithm R: the i-th score replaces a random one of the sample with probability size/i
+type scoreReservoir struct {
+	score func([]int) float64
+	size  int
+
+	mu       sync.Mutex
+	rng      *rand.Rand
+	count    uint64
+	min, max float64
+	sum      float64
+	sample   []float64
+}
+
+func newScoreReservoir(o *options) *scoreReservoir {
+	if o.score == nil {
+		return nil
+	}
+	return &scoreReservoir{score: o.score, size: o.scoreReservoir, rng: rand.New(rand.NewSource(1))}
+}
+
+func (r *scoreReservoir) record(score float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.count++
+	if r.count == 1 || score < r.min {
+		r.min = score
+	}
+	if r.count == 1 || score > r.max {
+		r.max = score
+	}
+	r.sum += score
+	if len(r.sample) < r.size {
+		r.sample = append(r.sample, score)
+	} else if slot := r.rng.Int63n(int64(r.count)); slot < int64(r.size) {
+		r.sample[slot] = score
+	}
+}
+
+func (r *scoreReservoir) snapshot() *ScoreStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := &ScoreStats{Count: r.count, Min: r.min, Max: r.max, sample: append([]float64{}, r.sample...)}
+	if r.count > 0 {
+		s.Mean = r.sum / float64(r.count)
+	}
+	sort.Float64s(s.sample)
+	return s
+}
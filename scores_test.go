@@ -0,0 +1,62 @@
+package powerset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func size(subset []int) float64 {
+	return float64(len(subset))
+}
+
+// with a reservoir large enough for every score, the quantiles and the histogram are exact
+func TestWithScoreHistogram(t *testing.T) {
+	_, stats := collectStart(t, Config{LenItems: 10}, WithScoreHistogram(size, 0))
+	scores := stats.Scores
+	if scores.Count != 1024 || scores.Min != 0 || scores.Max != 10 || scores.Mean != 5 {
+		t.Fatalf("unexpected scores %+v", *scores)
+	}
+	if median := scores.Quantile(0.5); median != 5 {
+		t.Fatalf("median %v != 5", median)
+	}
+
+	counts := []uint64{}
+	for _, bucket := range scores.Histogram(11) {
+		counts = append(counts, bucket.Count)
+	}
+	correct := []uint64{}
+	for k := 0; k <= 10; k++ {
+		correct = append(correct, binomial(10, k).Uint64())
+	}
+	if !reflect.DeepEqual(counts, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", counts, correct)
+	}
+}
+
+func TestScoreHistogramSampled(t *testing.T) {
+	_, stats := collectStart(t, Config{LenItems: 12, MinSize: 2}, WithScoreHistogram(size, 64))
+	scores := stats.Scores
+	if scores.Count != 4096-13 || scores.Min != 2 || scores.Max != 12 {
+		t.Fatalf("unexpected scores %+v", *scores)
+	}
+	if len(scores.sample) != 64 {
+		t.Fatalf("sampled %d scores, expected 64", len(scores.sample))
+	}
+	if median := scores.Quantile(0.5); median < 4 || median > 8 {
+		t.Fatalf("median %v is far from 6", median)
+	}
+
+	total := uint64(0)
+	for _, bucket := range scores.Histogram(5) {
+		total += bucket.Count
+	}
+	if total < scores.Count-5 || total > scores.Count+5 {
+		t.Fatalf("the histogram holds %d scores, expected about %d", total, scores.Count)
+	}
+}
+
+func TestScoreHistogramOff(t *testing.T) {
+	if _, stats := collectStart(t, Config{LenItems: 3}); stats.Scores != nil {
+		t.Fatalf("unexpected scores %+v", *stats.Scores)
+	}
+}
@@ -0,0 +1,66 @@
+package powerset
+
+import "math/big"
+
+// WithStartRank makes FixedSize and VariableSize start at the subset with the given rank, see Rank, so a job that
+// crashed can resume where it left off.  the subsets ranked before it are skipped without being generated, by
+// descending straight down the path to it, so seeking costs one step per item.  with size bounds, the first subset is
+// the first one within the bounds whose rank is at least rank.  OrderLexicographic, which isn't in rank order, ignores
+// it.  Callback starts at the leaf with the given rank, and its callback is called again at the nodes on the way down
+// to it, which rebuilds their state, but not at any node whose leaves all come before it
+func WithStartRank(rank *big.Int) Option {
+	return func(o *options) {
+		o.startRank = rank
+	}
+}
+
+// FixedSizeFrom is FixedSize, starting at the subset with rank start
+func FixedSizeFrom(lenItems int, start *big.Int, opts ...Option) (<-chan []bool, func()) {
+	return FixedSize(lenItems, append(opts, WithStartRank(start))...)
+}
+
+// VariableSizeFrom is VariableSize, starting at the subset with rank start
+func VariableSizeFrom(lenItems int, start *big.Int, opts ...Option) (<-chan []int, func()) {
+	return VariableSize(lenItems, append(opts, WithStartRank(start))...)
+}
+
+// seekRank follows the path from the root to the start rank.  a nil *seekRank is a subtree that is entirely at or after
+// the start rank, which needs no more checks, and skip is a subtree that is entirely before it
+type seekRank struct {
+	rank     *big.Int
+	lenItems int
+	skip     bool
+}
+
+var seekSkipped = &seekRank{skip: true}
+
+func (o *options) seek(lenItems int) *seekRank {
+	switch {
+	case o.startRank == nil || o.startRank.Sign() <= 0:
+		return nil
+	case o.startRank.BitLen() > lenItems:
+		return seekSkipped
+	}
+	return &seekRank{rank: o.startRank, lenItems: lenItems}
+}
+
+func (s *seekRank) reachable() bool {
+	return s == nil || !s.skip
+}
+
+// next returns the seek of the child of the node at depth n that excludes or includes index n.  index n is a bit of
+// the rank, so the child that agrees with it stays on the path, and the other one is before or after it
+func (s *seekRank) next(n int, included bool) *seekRank {
+	if s == nil {
+		return nil
+	}
+	bit := s.rank.Bit(s.lenItems-1-n) == 1
+	switch {
+	case included == bit:
+		return s
+	case bit:
+		return seekSkipped
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,76 @@
+package powerset
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+// starting at every rank should produce the tail of the full powerset from that rank on
+func TestVariableSizeFrom(t *testing.T) {
+	full := [][]int{}
+	out, _ := VariableSize(5)
+	for subset := range out {
+		full = append(full, subset)
+	}
+
+	for start := 0; start <= len(full); start++ {
+		got := [][]int{}
+		out, _ := VariableSizeFrom(5, big.NewInt(int64(start)))
+		for subset := range out {
+			got = append(got, subset)
+		}
+		if correct := full[start:]; !reflect.DeepEqual(got, correct) {
+			t.Fatalf("from %d:\n%v\n\n!=\n\n%v", start, got, correct)
+		}
+	}
+}
+
+func TestFixedSizeFrom(t *testing.T) {
+	// the last two subsets, at ranks past what fits in a uint64
+	last := new(big.Int).Lsh(bigOne, 100)
+	last.Sub(last, bigOne)
+	start := new(big.Int).Sub(last, bigOne)
+	out, _ := FixedSizeFrom(100, start)
+
+	ranks := []*big.Int{}
+	for included := range out {
+		subset := []int{}
+		for idx, isIn := range included {
+			if isIn {
+				subset = append(subset, idx)
+			}
+		}
+		ranks = append(ranks, Rank(subset, 100))
+	}
+	if correct := []*big.Int{start, last}; !reflect.DeepEqual(ranks, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", ranks, correct)
+	}
+
+	// past the last rank there is nothing left
+	out, _ = FixedSizeFrom(3, big.NewInt(8))
+	for included := range out {
+		t.Fatalf("unexpected subset %v", included)
+	}
+}
+
+// with size bounds, generation starts at the first subset within the bounds at or after the start rank
+func TestStartRankSizeBounds(t *testing.T) {
+	start := big.NewInt(5)
+	correct := [][]int{}
+	out, _ := VariableSize(4, WithMinSize(2), WithMaxSize(2))
+	for subset := range out {
+		if Rank(subset, 4).Cmp(start) >= 0 {
+			correct = append(correct, subset)
+		}
+	}
+
+	got := [][]int{}
+	out, _ = VariableSizeFrom(4, start, WithMinSize(2), WithMaxSize(2))
+	for subset := range out {
+		got = append(got, subset)
+	}
+	if !reflect.DeepEqual(got, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", got, correct)
+	}
+}
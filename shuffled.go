@@ -0,0 +1,75 @@
+package powerset
+
+import (
+	"math/big"
+	"sync"
+)
+
+// the rounds of the permutation behind Shuffled
+const shuffleRounds = 4
+
+// Shuffled generates every subset of lenItems items exactly once, in a pseudo-random order given by seed, so the first
+// subsets are representative of the whole powerset instead of being biased towards the left side of the tree.  the
+// order comes from a keyed permutation of the ranks, see Rank, so it's computed one subset at a time in constant
+// memory however many items there are.  each subset is a new sorted slice of its indices, and the same seed always
+// gives the same order.  stop works the same way as for VariableSize
+func Shuffled(lenItems int, seed uint64) (<-chan []int, func()) {
+	out := make(chan []int)
+	stopIn := make(chan bool)
+	perm := newRankPermutation(lenItems, seed)
+
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		defer close(out)
+		defer wg.Done()
+
+		end := new(big.Int).Lsh(bigOne, uint(lenItems))
+		for i := new(big.Int); i.Cmp(end) < 0; i.Add(i, bigOne) {
+			select {
+			case <-stopIn:
+				return
+			case out <- Unrank(perm.apply(i), lenItems):
+			}
+		}
+	}()
+
+	return out, makeStopper(stopIn, wg)
+}
+
+// rankPermutation is a bijection on the ranks from 0 to 2^n-1.  each round multiplies by an odd number and adds a
+// constant, modulo 2^n, which carries the low bits upwards, then xors in the high half shifted down, which carries them
+// back.  every step can be undone, so no two ranks map to the same one
+type rankPermutation struct {
+	n    int
+	mask *big.Int
+	mul  [shuffleRounds]*big.Int
+	add  [shuffleRounds]*big.Int
+}
+
+func newRankPermutation(n int, seed uint64) *rankPermutation {
+	p := &rankPermutation{n: n, mask: new(big.Int).Sub(new(big.Int).Lsh(bigOne, uint(n)), bigOne)}
+	key := seed
+	next := func() *big.Int {
+		key += 0x9e3779b97f4a7c15
+		return new(big.Int).SetUint64(mix64(key))
+	}
+	for r := range p.mul {
+		mul := next()
+		p.mul[r] = mul.SetBit(mul, 0, 1)
+		p.add[r] = next()
+	}
+	return p
+}
+
+func (p *rankPermutation) apply(rank *big.Int) *big.Int {
+	x := new(big.Int).Set(rank)
+	shifted := new(big.Int)
+	for r := range p.mul {
+		x.Mul(x, p.mul[r])
+		x.Add(x, p.add[r])
+		x.And(x, p.mask)
+		x.Xor(x, shifted.Rsh(x, uint(p.n+1)/2))
+	}
+	return x
+}
@@ -0,0 +1,72 @@
+package powerset
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// every subset comes out exactly once, in an order that depends on the seed
+func TestShuffled(t *testing.T) {
+	for _, n := range []int{0, 1, 5, 10} {
+		seen := map[string]bool{}
+		order := [][]int{}
+		out, _ := Shuffled(n, 1)
+		for subset := range out {
+			key := fmt.Sprint(subset)
+			if seen[key] || !sort.IntsAreSorted(subset) {
+				t.Fatalf("%d items: unexpected subset %v", n, subset)
+			}
+			seen[key] = true
+			order = append(order, subset)
+		}
+		if len(seen) != 1<<n {
+			t.Fatalf("%d items: generated %d subsets, expected %d", n, len(seen), 1<<n)
+		}
+
+		if n < 5 {
+			continue
+		}
+		again := [][]int{}
+		out, _ = Shuffled(n, 1)
+		for subset := range out {
+			again = append(again, subset)
+		}
+		if !reflect.DeepEqual(order, again) {
+			t.Fatalf("%d items: the same seed should give the same order", n)
+		}
+		other, stop := Shuffled(n, 2)
+		first := collectN(other, 8)
+		stop()
+		if reflect.DeepEqual(order[:8], first) {
+			t.Fatalf("%d items: different seeds gave the same order", n)
+		}
+	}
+}
+
+// the first subsets of a huge powerset are spread over the whole tree, rather than all excluding the first items
+func TestShuffledHuge(t *testing.T) {
+	out, stop := Shuffled(200, 7)
+	defer stop()
+	includesFirst := 0
+	for _, subset := range collectN(out, 100) {
+		if len(subset) > 0 && subset[0] == 0 {
+			includesFirst++
+		}
+	}
+	if includesFirst < 30 || includesFirst > 70 {
+		t.Fatalf("%d of the first 100 subsets include index 0, expected about 50", includesFirst)
+	}
+}
+
+func collectN(out <-chan []int, n int) [][]int {
+	subsets := [][]int{}
+	for subset := range out {
+		subsets = append(subsets, subset)
+		if len(subsets) == n {
+			break
+		}
+	}
+	return subsets
+}
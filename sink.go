@@ -0,0 +1,17 @@
+package powerset
+
+// Sink accumulates the subsets of a stream, typically to summarize or analyze the solutions of a search.  FamilyHasher
+// is a Sink, as are the analytics types in this package
+type Sink interface {
+	Add(subset []int)
+}
+
+// Drain adds every subset received on in to each of the sinks, in order, until in is closed.  it lets several analyses
+// share a single pass over an enumeration
+func Drain(in <-chan []int, sinks ...Sink) {
+	for subset := range in {
+		for _, sink := range sinks {
+			sink.Add(subset)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package powerset
+
+// WithMinSize makes FixedSize and VariableSize generate only the subsets with at least k items.  branches that can't
+// reach k items with the indices left to decide are pruned rather than generated and discarded
+func WithMinSize(k int) Option {
+	return func(o *options) {
+		o.minSize = k
+	}
+}
+
+// WithMaxSize makes FixedSize and VariableSize generate only the subsets with at most k items, pruning the branches
+// that already include more.  like Config.MaxSize, zero means there is no upper bound
+func WithMaxSize(k int) Option {
+	return func(o *options) {
+		o.maxSize = k
+	}
+}
+
+// sizeBounds are the size bounds of FixedSize and VariableSize, with hi filled in when there is no upper bound
+type sizeBounds struct {
+	lo, hi int
+}
+
+func (o *options) sizeBounds(lenItems int) sizeBounds {
+	b := sizeBounds{lo: o.minSize, hi: o.maxSize}
+	if b.hi <= 0 || b.hi > lenItems {
+		b.hi = lenItems
+	}
+	return b
+}
+
+// reachable reports whether a subtree at depth n, whose subset already includes numIncluded of the k items, can
+// still produce a subset within the bounds
+func (b sizeBounds) reachable(n int, k int, numIncluded int) bool {
+	return numIncluded <= b.hi && numIncluded+k-n >= b.lo
+}
@@ -0,0 +1,60 @@
+package powerset
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVariableSizeBounds(t *testing.T) {
+	for _, bounds := range [][2]int{{0, 0}, {2, 0}, {0, 2}, {1, 3}, {3, 3}, {4, 2}, {0, 9}} {
+		all, _ := VariableSize(5)
+		correct := [][]int{}
+		for subset := range all {
+			if len(subset) >= bounds[0] && (bounds[1] == 0 || len(subset) <= bounds[1]) {
+				correct = append(correct, subset)
+			}
+		}
+
+		out, _ := VariableSize(5, WithMinSize(bounds[0]), WithMaxSize(bounds[1]))
+		got := [][]int{}
+		for subset := range out {
+			got = append(got, subset)
+		}
+		if !reflect.DeepEqual(got, correct) {
+			t.Fatalf("%v: \n%v\n\n!=\n\n%v", bounds, got, correct)
+		}
+	}
+}
+
+func TestFixedSizeBounds(t *testing.T) {
+	out, _ := FixedSize(3, WithMinSize(2))
+	got := [][]bool{}
+	for subset := range out {
+		got = append(got, subset)
+	}
+	correct := [][]bool{
+		{false, true, true},
+		{true, false, true},
+		{true, true, false},
+		{true, true, true},
+	}
+	if !reflect.DeepEqual(got, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", got, correct)
+	}
+}
+
+// the bounds prune the recursion, so a narrow band of a large powerset is quick to generate
+func TestSizeBoundsPrune(t *testing.T) {
+	out, _ := VariableSize(60, WithMaxSize(2))
+	count := 0
+	for range out {
+		count++
+	}
+	if count != 1+60+1770 {
+		t.Fatalf("expected %d subsets, got %d", 1+60+1770, count)
+	}
+
+	out, stop := VariableSize(60, WithMinSize(58))
+	<-out
+	stop()
+}
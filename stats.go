@@ -0,0 +1,259 @@
+package powerset
+
+import (
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a snapshot of the progress of a search started with Start.  every subset of the powerset is eventually
+// either emitted, suppressed, or inside a pruned subtree
+type Stats struct {
+	// the number of nodes of the powerset tree that have been visited, including leaves
+	Nodes uint64
+
+	// the number of subsets sent on the output channel
+	Emitted uint64
+
+	// the number of subsets that were members of the family but weren't emitted, like duplicates dropped by
+	// WithBloomDedup
+	Suppressed uint64
+
+	// the number of subtrees that were skipped, including leaves rejected by Constraints
+	Pruned uint64
+
+	// the pruned subtrees broken down by the rule that pruned them: MinSize, MaxSize, Required, Forbidden, the name of
+	// a constraint, WithQuotaPerSize, WithTargetSum, WithInterchangeable, Shard, the name of a BestEffort pruner, or
+	// "empty family"
+	PrunedBy map[string]uint64
+
+	// the engine that walked the family, which is EngineBranchAndBound unless WithAutoEngine or WithTargetSum picked
+	// another
+	Engine Engine
+
+	// the strength WithAdaptivePruning is running its BestEffort pruners at, or 0 without it
+	PruningStrength float64
+
+	// the approximate number of bytes held by the search's buffered results, filters and caches, which WithMemoryBudget
+	// keeps within its budget
+	Memory uint64
+
+	// the emitted and pruned counts broken down by subset size, where BySize[k] covers the subsets of size k.  nil
+	// unless the search was started with WithSizeStats
+	BySize []SizeStats
+
+	// the distribution of the scores of the emitted subsets.  nil unless the search was started with
+	// WithScoreHistogram
+	Scores *ScoreStats
+
+	// how the search's time splits between the Config's constraints and the engine.  nil unless the search was
+	// started with WithTiming
+	Timing *ConstraintTiming
+}
+
+// ConstraintTiming splits the time of a search started with Start between the checks the user wrote, which are the
+// Allow functions of the Config's Constraints that weren't made by ParseConstraint and the canonicalizer, and the
+// engine, which is everything else.  it doesn't cover the NodeCallbacks of Callback, which the Timing middleware times
+type ConstraintTiming struct {
+	// the latency of each call to a check
+	Calls Histogram
+
+	// the time spent in checks, and the time the search spent working at all, rather than waiting on the consumer, a
+	// rate limit or a pause
+	InCalls time.Duration
+	Busy    time.Duration
+}
+
+// CallShare returns the fraction of the busy time spent in checks.  when it's close to one, speeding up the
+// constraints is what speeds up the search
+func (t *ConstraintTiming) CallShare() float64 {
+	if t.Busy <= 0 {
+		return 0
+	}
+	share := float64(t.InCalls) / float64(t.Busy)
+	if share > 1 {
+		share = 1
+	}
+	return share
+}
+
+// SizeStats counts the subsets of a single size
+type SizeStats struct {
+	Emitted uint64
+
+	// the number of subsets of this size inside pruned subtrees.  a single pruned subtree can hide an astronomical
+	// number of subsets, so this count is exact
+	Pruned *big.Int
+}
+
+// WithSizeStats makes the Stats of a search break its counts down by subset size, so you can see which sizes dominate
+// the search and tune MinSize and MaxSize accordingly.  counting the subsets of every size inside each pruned subtree
+// costs a few binomial coefficients per pruned subtree, so it's off by default
+func WithSizeStats() Option {
+	return func(o *options) {
+		o.sizeStats = true
+	}
+}
+
+// WithTiming makes the Stats of a search started with Start time every call to the constraints and the canonicalizer
+// the user wrote, to show whether they or the engine dominate its runtime.  reading the clock twice per call is cheap
+// next to most constraints, but not free, so it's off by default
+func WithTiming() Option {
+	return func(o *options) {
+		o.timing = true
+	}
+}
+
+// searchStats is the live, concurrently readable version of Stats
+type searchStats struct {
+	nodes      atomic.Uint64
+	emitted    atomic.Uint64
+	suppressed atomic.Uint64
+	pruned     atomic.Uint64
+
+	// guards bySize, which is nil unless size stats are enabled, and prunedBy
+	mu       sync.Mutex
+	bySize   []SizeStats
+	prunedBy map[string]uint64
+
+	// the search's memory estimate, if it has one
+	memory *memoryMeter
+
+	// the latencies of the user's constraints, if they're timed
+	calls *latencyHistogram
+
+	engine Engine
+
+	// the state of WithAdaptivePruning, if the search has it
+	adaptive *adaptiveController
+
+	// the scores of the emitted subsets, if they're scored
+	scores *scoreReservoir
+}
+
+func newSearchStats(lenItems int, o *options) *searchStats {
+	stats := &searchStats{prunedBy: map[string]uint64{}}
+	if o.sizeStats {
+		stats.bySize = make([]SizeStats, lenItems+1)
+		for k := range stats.bySize {
+			stats.bySize[k].Pruned = new(big.Int)
+		}
+	}
+	if o.timing {
+		stats.calls = &latencyHistogram{}
+	}
+	stats.scores = newScoreReservoir(o)
+	return stats
+}
+
+// timeConstraints wraps the first numUser constraints of the family, which are the Config's own, and the canonicalizer
+// of the options so their latencies are recorded.  the constraints the package adds after them aren't timed
+func (stats *searchStats) timeConstraints(fam *family, o *options, numUser int) {
+	h := stats.calls
+	constraints := make([]Constraint, len(fam.constraints))
+	for i, c := range fam.constraints {
+		if c.expr == nil && i < numUser {
+			allow := c.Allow
+			c.Allow = func(indices []int) bool {
+				start := time.Now()
+				defer func() { h.record(time.Since(start)) }()
+				return allow(indices)
+			}
+		}
+		constraints[i] = c
+	}
+	fam.constraints = constraints
+
+	if canonicalize := o.canonicalize; canonicalize != nil {
+		o.canonicalize = func(subset []int) []int {
+			start := time.Now()
+			defer func() { h.record(time.Since(start)) }()
+			return canonicalize(subset)
+		}
+	}
+}
+
+func (stats *searchStats) addEmitted(size int) {
+	stats.emitted.Add(1)
+	if stats.bySize != nil {
+		stats.mu.Lock()
+		stats.bySize[size].Emitted++
+		stats.mu.Unlock()
+	}
+}
+
+// addPruned counts a pruned subtree, whose root included numIncluded indices and which left numUndecided indices
+// undecided.  it hides C(numUndecided, j) subsets of size numIncluded+j, for every j.  if only is not negative, the
+// subtree was pruned by a walk that only looks for subsets of that size, so the other sizes aren't counted.  rule is
+// what pruned it
+func (stats *searchStats) addPruned(numIncluded int, numUndecided int, only int, rule string) {
+	stats.pruned.Add(1)
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+
+	stats.prunedBy[rule]++
+	if stats.bySize != nil {
+		for j := 0; j <= numUndecided; j++ {
+			if only >= 0 && numIncluded+j != only {
+				continue
+			}
+			size := stats.bySize[numIncluded+j].Pruned
+			size.Add(size, binomial(numUndecided, j))
+		}
+	}
+}
+
+func (stats *searchStats) snapshot() Stats {
+	snap := Stats{
+		Nodes:      stats.nodes.Load(),
+		Emitted:    stats.emitted.Load(),
+		Suppressed: stats.suppressed.Load(),
+		Pruned:     stats.pruned.Load(),
+		Engine:     stats.engine,
+	}
+	if stats.adaptive != nil {
+		snap.PruningStrength = stats.adaptive.currentStrength()
+	}
+	if stats.scores != nil {
+		snap.Scores = stats.scores.snapshot()
+	}
+	if stats.memory != nil {
+		if used := stats.memory.used.Load(); used > 0 {
+			snap.Memory = uint64(used)
+		}
+	}
+
+	stats.mu.Lock()
+	defer stats.mu.Unlock()
+	snap.PrunedBy = make(map[string]uint64, len(stats.prunedBy))
+	for rule, count := range stats.prunedBy {
+		snap.PrunedBy[rule] = count
+	}
+	if stats.bySize != nil {
+		snap.BySize = make([]SizeStats, len(stats.bySize))
+		for k, size := range stats.bySize {
+			snap.BySize[k] = SizeStats{Emitted: size.Emitted, Pruned: new(big.Int).Set(size.Pruned)}
+		}
+	}
+	return snap
+}
+
+// Stats returns a snapshot of the search's progress.  it can be called while the search is running, or after it has
+// finished for the final totals
+func (ctl *Control) Stats() Stats {
+	snap := ctl.stats.snapshot()
+	if h := ctl.stats.calls; h != nil {
+		snap.Timing = &ConstraintTiming{Calls: h.snapshot(), InCalls: time.Duration(h.sum.Load())}
+		if r := ctl.recorder; r != nil {
+			r.mu.Lock()
+			end := r.finished
+			r.mu.Unlock()
+			if end.IsZero() {
+				end = time.Now()
+			}
+			snap.Timing.Busy = end.Sub(r.started) - time.Duration(r.waiting.Load())
+		}
+	}
+	return snap
+}
@@ -0,0 +1,141 @@
+package powerset
+
+import (
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	out, ctl, _ := Start(Config{LenItems: 4, MaxSize: 2, Forbidden: []int{0}})
+	for range out {
+	}
+
+	stats := ctl.Stats()
+	if stats.Emitted != 7 {
+		t.Fatalf("emitted %d, expected 7", stats.Emitted)
+	}
+	if stats.Pruned == 0 || stats.Nodes == 0 {
+		t.Fatalf("expected some nodes and pruning, got %+v", stats)
+	}
+	if stats.BySize != nil {
+		t.Fatalf("expected no size breakdown without WithSizeStats")
+	}
+}
+
+func TestSizeStats(t *testing.T) {
+	notBoth := Constraint{
+		Name:  "not 1 and 2",
+		Allow: func(indices []int) bool { return !(len(indices) == 2 && indices[0] == 1 && indices[1] == 2) },
+	}
+	cfg := Config{LenItems: 5, MaxSize: 3, Required: []int{4}, Constraints: []Constraint{notBoth}}
+	out, ctl, _ := Start(cfg, WithSizeStats())
+
+	emitted := make([]uint64, 6)
+	for indices := range out {
+		emitted[len(indices)]++
+	}
+
+	stats := ctl.Stats()
+	for k, size := range stats.BySize {
+		if size.Emitted != emitted[k] {
+			t.Fatalf("size %d: emitted %d != %d", k, size.Emitted, emitted[k])
+		}
+
+		// every subset of size k is either emitted or pruned
+		total := new(big.Int).SetUint64(size.Emitted)
+		total.Add(total, size.Pruned)
+		if total.Cmp(binomial(5, k)) != 0 {
+			t.Fatalf("size %d: %v emitted and pruned subsets, expected C(5, %d)", k, total, k)
+		}
+	}
+	if stats.BySize[4].Emitted != 0 || stats.BySize[5].Emitted != 0 {
+		t.Fatalf("subsets above MaxSize were emitted: %+v", stats.BySize)
+	}
+}
+
+func TestSizeStatsEmptyFamily(t *testing.T) {
+	out, ctl, _ := Start(Config{LenItems: 3, MinSize: 4}, WithSizeStats())
+	for range out {
+	}
+
+	stats := ctl.Stats()
+	for k, size := range stats.BySize {
+		if size.Pruned.Cmp(binomial(3, k)) != 0 {
+			t.Fatalf("size %d: pruned %v, expected everything", k, size.Pruned)
+		}
+	}
+}
+
+func TestStatsPrunedBy(t *testing.T) {
+	odd, _ := ParseConstraint("0 | 1", nil)
+	odd.Name = "odd"
+	cfg := Config{LenItems: 6, MaxSize: 2, Required: []int{5}, Forbidden: []int{4}, Constraints: []Constraint{odd}}
+	out, ctl, _ := Start(cfg)
+	for range out {
+	}
+
+	stats := ctl.Stats()
+	total := uint64(0)
+	for _, rule := range []string{"MaxSize", "Required", "Forbidden", "odd"} {
+		if stats.PrunedBy[rule] == 0 {
+			t.Fatalf("expected %s to prune something, got %v", rule, stats.PrunedBy)
+		}
+	}
+	for _, count := range stats.PrunedBy {
+		total += count
+	}
+	if total != stats.Pruned {
+		t.Fatalf("breakdown adds up to %d, expected %d", total, stats.Pruned)
+	}
+}
+
+func TestStatsTiming(t *testing.T) {
+	slow := Constraint{
+		Name: "slow",
+		Allow: func(indices []int) bool {
+			time.Sleep(100 * time.Microsecond)
+			return true
+		},
+	}
+	parsed, _ := ParseConstraint("count(..3)", nil)
+	cfg := Config{LenItems: 4, Constraints: []Constraint{slow, parsed}}
+	out, ctl, err := Start(cfg, WithTiming())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for range out {
+	}
+
+	timing := ctl.Stats().Timing
+	if timing == nil {
+		t.Fatalf("expected timing stats")
+	}
+	// only the Allow function that isn't parsed is timed, once per leaf
+	if timing.Calls.Count() != 16 {
+		t.Fatalf("expected 16 timed calls, got %d", timing.Calls.Count())
+	}
+	if timing.Calls.Quantile(0.5) < 100*time.Microsecond {
+		t.Fatalf("median latency %v is faster than the constraint", timing.Calls.Quantile(0.5))
+	}
+	if timing.InCalls > timing.Busy || timing.CallShare() <= 0 {
+		t.Fatalf("unexpected split of %v in constraints out of %v", timing.InCalls, timing.Busy)
+	}
+
+	// the constraint of a shard is the package's own, so only the 4 calls to slow in the shard are timed
+	sharded := cfg
+	sharded.Shard = &ShardSpec{Count: 4, Index: 1}
+	out, ctl, _ = Start(sharded, WithTiming())
+	for range out {
+	}
+	if count := ctl.Stats().Timing.Calls.Count(); count != 4 {
+		t.Fatalf("expected 4 timed calls, got %d", count)
+	}
+
+	out, ctl, _ = Start(cfg)
+	for range out {
+	}
+	if ctl.Stats().Timing != nil {
+		t.Fatalf("expected no timing stats without WithTiming")
+	}
+}
@@ -0,0 +1,75 @@
+package powerset
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+)
+
+// OfStructOptions yields a copy of v for every combination of its optional fields being set, for testing a
+// configuration struct exhaustively.  v is a struct or a pointer to one, and the copies are of the same type.  the
+// optional fields are the exported pointer and bool fields, and any other exported field tagged `powerset:"optional"`.
+// a field that is set keeps its value from v, except that a nil pointer is set to a new zero value and a bool is set to
+// true, and a field that isn't set is its zero value.  the other fields are copied from v unchanged.  the combinations
+// come out in the same order as FixedSize, where the first optional field is the first index.  v's type is checked up
+// front, and anything that isn't a struct or a pointer to one panics
+func OfStructOptions(v interface{}) iter.Seq[interface{}] {
+	template := reflect.ValueOf(v)
+	isPtr := template.Kind() == reflect.Ptr
+	if isPtr {
+		template = template.Elem()
+	}
+	if template.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("powerset: OfStructOptions needs a struct or a pointer to one, got %T", v))
+	}
+
+	typ := template.Type()
+	fields := []int{}
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if f.Type.Kind() == reflect.Ptr || f.Type.Kind() == reflect.Bool || f.Tag.Get("powerset") == "optional" {
+			fields = append(fields, i)
+		}
+	}
+
+	return func(yield func(interface{}) bool) {
+		value := reflect.New(typ).Elem()
+		value.Set(template)
+
+		// decide each optional field in turn, unset before set, like the powerset tree
+		var recurse func(depth int) bool
+		recurse = func(depth int) bool {
+			if depth == len(fields) {
+				out := reflect.New(typ)
+				out.Elem().Set(value)
+				if isPtr {
+					return yield(out.Interface())
+				}
+				return yield(out.Elem().Interface())
+			}
+
+			field := value.Field(fields[depth])
+			field.Set(reflect.Zero(field.Type()))
+			if !recurse(depth + 1) {
+				return false
+			}
+			field.Set(optionSet(template.Field(fields[depth])))
+			return recurse(depth + 1)
+		}
+		recurse(0)
+	}
+}
+
+// optionSet is the value an optional field has when it's set, given its value in the template
+func optionSet(field reflect.Value) reflect.Value {
+	switch {
+	case field.Kind() == reflect.Bool:
+		return reflect.ValueOf(true).Convert(field.Type())
+	case field.Kind() == reflect.Ptr && field.IsNil():
+		return reflect.New(field.Type().Elem())
+	}
+	return field
+}
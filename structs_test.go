@@ -0,0 +1,87 @@
+package powerset
+
+import (
+	"reflect"
+	"testing"
+)
+
+type structOptions struct {
+	Name    string
+	Verbose bool
+	Retries *int
+	Mode    string `powerset:"optional"`
+	Limit   *float64
+	hidden  bool
+}
+
+func TestOfStructOptions(t *testing.T) {
+	retries := 3
+	template := structOptions{Name: "app", Retries: &retries, Mode: "fast", hidden: true}
+
+	all := []structOptions{}
+	for v := range OfStructOptions(template) {
+		all = append(all, v.(structOptions))
+	}
+	if len(all) != 16 {
+		t.Fatalf("expected 16 combinations, got %d", len(all))
+	}
+
+	// the first and last combinations have none and all of the optional fields set
+	first, last := all[0], all[len(all)-1]
+	if first != (structOptions{Name: "app", hidden: true}) {
+		t.Fatalf("unexpected first combination %+v", first)
+	}
+	if !last.Verbose || last.Retries != &retries || last.Mode != "fast" || last.Limit == nil || *last.Limit != 0 {
+		t.Fatalf("unexpected last combination %+v", last)
+	}
+
+	// the combinations are the subsets of the optional fields in the order of FixedSize
+	out, _, _ := Family(Config{LenItems: 4})
+	i := 0
+	for indices := range out {
+		set := []int{}
+		v := all[i]
+		for idx, isSet := range []bool{v.Verbose, v.Retries != nil, v.Mode != "", v.Limit != nil} {
+			if isSet {
+				set = append(set, idx)
+			}
+		}
+		if !reflect.DeepEqual(set, indices) {
+			t.Fatalf("combination %d: \n%v\n\n!=\n\n%v", i, set, indices)
+		}
+		if v.Name != "app" || !v.hidden {
+			t.Fatalf("combination %d changed a field that isn't optional: %+v", i, v)
+		}
+		i++
+	}
+}
+
+func TestOfStructOptionsPointer(t *testing.T) {
+	template := &structOptions{Name: "app"}
+	seen := 0
+	for v := range OfStructOptions(template) {
+		opts := v.(*structOptions)
+		if opts == template {
+			t.Fatalf("expected a copy of the template")
+		}
+		seen++
+		if seen == 3 {
+			break
+		}
+	}
+	if seen != 3 {
+		t.Fatalf("expected to stop after 3 combinations, got %d", seen)
+	}
+	if *template != (structOptions{Name: "app"}) {
+		t.Fatalf("the template was changed: %+v", template)
+	}
+}
+
+func TestOfStructOptionsNotStruct(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected a panic")
+		}
+	}()
+	OfStructOptions(3)
+}
@@ -0,0 +1,155 @@
+package powerset
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// the version of the format ExportSubtree writes
+const subtreeVersion = 1
+
+// Subtree is a pending unit of work of a Callback traversal, decoded by ImportSubtree
+type Subtree struct {
+	LenItems int
+
+	// the path to the root of the subtree, deepest first
+	Path Path
+
+	// the rank of the first leaf below Path that's still pending, or nil if the whole subtree is
+	Position *big.Int
+
+	// the JSON encoding of the state the subtree's children start from, which Resume leaves to the caller to decode
+	State json.RawMessage
+}
+
+type subtreeJSON struct {
+	Version  int             `json:"version"`
+	LenItems int             `json:"lenItems"`
+	Path     []pathNodeJSON  `json:"path"`
+	Position string          `json:"position,omitempty"`
+	State    json.RawMessage `json:"state,omitempty"`
+}
+
+type pathNodeJSON struct {
+	Index    int  `json:"index"`
+	Included bool `json:"included"`
+}
+
+// ExportSubtree packages the pending subtree below path, of a Callback traversal of lenItems items, into a portable
+// blob that ImportSubtree can unpack on another machine, so a work unit can be migrated in the middle of a search.
+// position is the rank of the first leaf below path that hasn't been explored yet, or nil if none have, and state,
+// which is encoded as JSON, is what the subtree's children start from
+func ExportSubtree(path Path, lenItems int, position *big.Int, state interface{}) ([]byte, error) {
+	sub := Subtree{LenItems: lenItems, Path: path, Position: position}
+	if err := sub.validate(); err != nil {
+		return nil, err
+	}
+
+	sj := subtreeJSON{Version: subtreeVersion, LenItems: lenItems, Path: make([]pathNodeJSON, len(path))}
+	for i, node := range path {
+		sj.Path[i] = pathNodeJSON{Index: node.Index, Included: node.Included}
+	}
+	if position != nil {
+		sj.Position = position.String()
+	}
+	if state != nil {
+		encoded, err := json.Marshal(state)
+		if err != nil {
+			return nil, fmt.Errorf("powerset: can't encode the subtree's state: %w", err)
+		}
+		sj.State = encoded
+	}
+	return json.Marshal(sj)
+}
+
+// ImportSubtree unpacks a subtree packaged by ExportSubtree
+func ImportSubtree(data []byte) (Subtree, error) {
+	var sj subtreeJSON
+	if err := strictUnmarshal(data, &sj); err != nil {
+		return Subtree{}, err
+	}
+	if sj.Version != subtreeVersion {
+		return Subtree{}, fmt.Errorf("powerset: unknown subtree version %d", sj.Version)
+	}
+
+	sub := Subtree{LenItems: sj.LenItems, Path: make(Path, len(sj.Path)), State: sj.State}
+	for i, node := range sj.Path {
+		sub.Path[i] = &PathNode{Index: node.Index, Included: node.Included}
+	}
+	if sj.Position != "" {
+		position, ok := new(big.Int).SetString(sj.Position, 10)
+		if !ok {
+			return Subtree{}, fmt.Errorf("powerset: invalid subtree position %q", sj.Position)
+		}
+		sub.Position = position
+	}
+	if err := sub.validate(); err != nil {
+		return Subtree{}, err
+	}
+	return sub, nil
+}
+
+// validate checks that the path decides the indices from 0 down, and that the position is a leaf below it
+func (sub Subtree) validate() error {
+	depth := len(sub.Path)
+	if depth > sub.LenItems {
+		return fmt.Errorf("powerset: a path of %d nodes is too deep for %d items", depth, sub.LenItems)
+	}
+	for i, node := range sub.Path {
+		if node.Index != depth-1-i {
+			return fmt.Errorf("powerset: path node %d decides index %d, expected %d", i, node.Index, depth-1-i)
+		}
+	}
+	if sub.Position != nil {
+		lo, hi := sub.Path.RankInterval(sub.LenItems)
+		if sub.Position.Cmp(lo) < 0 || sub.Position.Cmp(hi) > 0 {
+			return fmt.Errorf("powerset: position %v isn't below the path, whose leaves are %v to %v", sub.Position, lo, hi)
+		}
+	}
+	return nil
+}
+
+// Resume carries on a Callback traversal below the subtree's path, starting from its position, with children that
+// start from state.  like FromFrontier, the callback isn't called for the subtree's root, and it is called again for
+// the nodes on the way down to the position, unless they're remembered by WithPrefixCache, but not for anything before
+// it.  Resume understands WithPrefixCache
+func (sub Subtree) Resume(cb NodeCallback, state interface{}, opts ...Option) <-chan interface{} {
+	o := buildOptions(opts)
+	out := make(chan interface{})
+	userCb := internalCallback(func(path *list.List, isLeaf bool, state interface{}) (bool, int, interface{}) {
+		return cb(llToPath(path), isLeaf, state, out)
+	})
+	if o.prefixCache != nil {
+		userCb = o.prefixCache.wrap(userCb)
+	}
+	wrappedCb := func(path *list.List, isLeaf bool, state interface{}) (bool, int, interface{}) {
+		if sub.Position != nil {
+			// a node whose last leaf comes before the position was explored before the subtree was exported, so it's
+			// pruned by stopping back to its parent
+			if _, hi := Path(llToPath(path)).RankInterval(sub.LenItems); hi.Cmp(sub.Position) < 0 {
+				return true, path.Len() - 1, nil
+			}
+		}
+		return userCb(path, isLeaf, state)
+	}
+
+	go func() {
+		defer close(out)
+
+		path := list.New()
+		indices := list.New()
+		for j := len(sub.Path) - 1; j >= 0; j-- {
+			node := sub.Path[j]
+			path.PushFront(node)
+			if node.Included {
+				indices.PushFront(node.Index)
+			}
+		}
+		if depth := len(sub.Path); depth < sub.LenItems {
+			powerSetChildren(depth, sub.LenItems, indices, wrappedCb, path, state, buildOptions(nil))
+		}
+	}()
+	return out
+}
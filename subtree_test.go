@@ -0,0 +1,91 @@
+package powerset
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+// leafCallback sends the rank of every leaf of a traversal of lenItems items
+func leafCallback(lenItems int) NodeCallback {
+	return func(path Path, isLeaf bool, state interface{}, out chan<- interface{}) (bool, int, interface{}) {
+		if isLeaf {
+			lo, _ := path.RankInterval(lenItems)
+			out <- lo.Int64()
+		}
+		return false, 0, state
+	}
+}
+
+func TestSubtreeRoundTrip(t *testing.T) {
+	type progress struct {
+		Best  int
+		Names []string
+	}
+	path := Path{{Index: 1, Included: true}, {Index: 0, Included: false}}
+	data, err := ExportSubtree(path, 6, big.NewInt(21), progress{Best: 3, Names: []string{"a"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sub, err := ImportSubtree(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sub.LenItems != 6 || !ValidatePath(sub.Path, path) || sub.Position.Int64() != 21 {
+		t.Fatalf("unexpected subtree %+v", sub)
+	}
+	var state progress
+	if err := json.Unmarshal(sub.State, &state); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(state, progress{Best: 3, Names: []string{"a"}}) {
+		t.Fatalf("unexpected state %+v", state)
+	}
+}
+
+func TestSubtreeResume(t *testing.T) {
+	path := Path{{Index: 1, Included: true}, {Index: 0, Included: false}}
+	for _, position := range []*big.Int{nil, big.NewInt(16), big.NewInt(21), big.NewInt(31)} {
+		data, _ := ExportSubtree(path, 6, position, nil)
+		sub, err := ImportSubtree(data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// the leaves of index 0 excluded and index 1 included have ranks 16 to 31
+		first := int64(16)
+		if position != nil {
+			first = position.Int64()
+		}
+		correct := []interface{}{}
+		for rank := first; rank <= 31; rank++ {
+			correct = append(correct, rank)
+		}
+
+		got := []interface{}{}
+		for rank := range sub.Resume(leafCallback(6), nil) {
+			got = append(got, rank)
+		}
+		if !reflect.DeepEqual(got, correct) {
+			t.Fatalf("%v: \n%v\n\n!=\n\n%v", position, got, correct)
+		}
+	}
+}
+
+func TestSubtreeInvalid(t *testing.T) {
+	path := Path{{Index: 1, Included: true}, {Index: 0, Included: false}}
+	if _, err := ExportSubtree(path, 6, big.NewInt(32), nil); err == nil {
+		t.Fatalf("expected an error for a position outside the subtree")
+	}
+	if _, err := ExportSubtree(Path{{Index: 1, Included: true}}, 6, nil, nil); err == nil {
+		t.Fatalf("expected an error for a path that doesn't start from index 0")
+	}
+	if _, err := ImportSubtree([]byte(`{"version":2,"lenItems":3,"path":[]}`)); err == nil {
+		t.Fatalf("expected an error for an unknown version")
+	}
+	if _, err := ImportSubtree([]byte(`{"version":1,"lenItems":3,"path":[],"position":"x"}`)); err == nil {
+		t.Fatalf("expected an error for an invalid position")
+	}
+}
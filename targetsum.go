@@ -0,0 +1,115 @@
+package powerset
+
+import (
+	"fmt"
+	"sort"
+)
+
+// WithTargetSum makes Start and Family emit only the members of a family whose values add up to target, where
+// values[i] is the value of index i.  the search meets in the middle: the sums that every suffix of the upper half of
+// the indices can reach are computed up front, which takes memory for up to 2^(n/2) sums, and any subtree whose sum so
+// far can't be completed to target is pruned.  the lower half is walked as usual, pruned by the smallest and largest
+// sums still reachable, and the upper half only along paths that lead to a hit, so a family of around 40 items is
+// searched in about 2^20 steps instead of 2^40.  the subtrees it skips are reported under the rule "WithTargetSum"
+func WithTargetSum(values []int64, target int64) Option {
+	return func(o *options) {
+		o.targetSum = &targetSum{values: values, target: target}
+	}
+}
+
+// the rule reported for subtrees skipped by WithTargetSum
+const targetSumRule = "WithTargetSum"
+
+type targetSum struct {
+	values []int64
+	target int64
+
+	// the first index of the upper half
+	half int
+
+	// reachable[d-half] holds the distinct sums of the subsets of indices d and above, sorted, for every d in the upper
+	// half, and one more set, {0}, for the empty suffix
+	reachable [][]int64
+
+	// lo[d] and hi[d] are the smallest and largest sums of the subsets of indices d and above
+	lo, hi []int64
+}
+
+// prepare computes the reachable sums for lenItems items, or returns an error if there aren't as many values
+func (ts *targetSum) prepare(lenItems int) error {
+	if len(ts.values) != lenItems {
+		return fmt.Errorf("powerset: WithTargetSum has %d values for %d items", len(ts.values), lenItems)
+	}
+
+	n := lenItems
+	ts.half = n / 2
+	ts.lo, ts.hi = make([]int64, n+1), make([]int64, n+1)
+	for d := n - 1; d >= 0; d-- {
+		ts.lo[d], ts.hi[d] = ts.lo[d+1], ts.hi[d+1]
+		if v := ts.values[d]; v < 0 {
+			ts.lo[d] += v
+		} else {
+			ts.hi[d] += v
+		}
+	}
+
+	ts.reachable = make([][]int64, n-ts.half+1)
+	ts.reachable[n-ts.half] = []int64{0}
+	for d := n - 1; d >= ts.half; d-- {
+		ts.reachable[d-ts.half] = mergeSums(ts.reachable[d-ts.half+1], ts.values[d])
+	}
+	return nil
+}
+
+// mergeSums returns the sorted, distinct union of sums and every one of sums plus v
+func mergeSums(sums []int64, v int64) []int64 {
+	merged := make([]int64, 0, 2*len(sums))
+	i, j := 0, 0
+	for i < len(sums) || j < len(sums) {
+		var next int64
+		switch {
+		case j == len(sums) || (i < len(sums) && sums[i] <= sums[j]+v):
+			next = sums[i]
+			i++
+		default:
+			next = sums[j] + v
+			j++
+		}
+		if len(merged) == 0 || merged[len(merged)-1] != next {
+			merged = append(merged, next)
+		}
+	}
+	return merged
+}
+
+func (ts *targetSum) sum(indices []int) int64 {
+	var sum int64
+	for _, idx := range indices {
+		sum += ts.values[idx]
+	}
+	return sum
+}
+
+// completable reports whether the indices from next onwards can add up to what the included indices are missing
+func (ts *targetSum) completable(included []int, next int) bool {
+	need := ts.target - ts.sum(included)
+	if need < ts.lo[next] || need > ts.hi[next] {
+		return false
+	}
+	if next < ts.half {
+		return true
+	}
+	sums := ts.reachable[next-ts.half]
+	i := sort.Search(len(sums), func(i int) bool { return sums[i] >= need })
+	return i < len(sums) && sums[i] == need
+}
+
+// constraint checks the sum of a whole subset at the leaves of the walk
+func (ts *targetSum) constraint() Constraint {
+	return Constraint{
+		Name: targetSumRule,
+		Allow: func(indices []int) bool {
+			return ts.sum(indices) == ts.target
+		},
+	}
+}
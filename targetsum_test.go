@@ -0,0 +1,77 @@
+package powerset
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestWithTargetSum(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	for trial := 0; trial < 30; trial++ {
+		n := rng.Intn(10)
+		values := make([]int64, n)
+		for i := range values {
+			values[i] = rng.Int63n(21) - 8
+		}
+		target := rng.Int63n(21) - 5
+		cfg := Config{LenItems: n, MaxSize: rng.Intn(n + 1)}
+
+		correct := [][]int{}
+		for _, subset := range collectFamily(t, cfg) {
+			var sum int64
+			for _, idx := range subset {
+				sum += values[idx]
+			}
+			if sum == target {
+				correct = append(correct, subset)
+			}
+		}
+		out, _, err := Family(cfg, WithTargetSum(values, target))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got := [][]int{}
+		for subset := range out {
+			got = append(got, subset)
+		}
+		if !reflect.DeepEqual(got, correct) {
+			t.Fatalf("%v = %d: \n%v\n\n!=\n\n%v", values, target, got, correct)
+		}
+	}
+}
+
+func TestWithTargetSumPrunes(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	values := make([]int64, 36)
+	for i := range values {
+		values[i] = rng.Int63n(2000001) - 1000000
+	}
+	chosen := []int{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31}
+	var target int64
+	for _, idx := range chosen {
+		target += values[idx]
+	}
+
+	out, ctl, err := Start(Config{LenItems: 36}, WithTargetSum(values, target))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	found := false
+	for subset := range out {
+		found = found || reflect.DeepEqual(subset, chosen)
+	}
+	if !found {
+		t.Fatalf("expected %v to be found", chosen)
+	}
+	// the lower half has 2^18 subsets, and the whole powerset 2^36
+	if nodes := ctl.Stats().Nodes; nodes > 1<<21 {
+		t.Fatalf("expected the search to meet in the middle, but it visited %d nodes", nodes)
+	}
+}
+
+func TestWithTargetSumInvalid(t *testing.T) {
+	if _, _, err := Family(Config{LenItems: 3}, WithTargetSum([]int64{1, 2}, 3)); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
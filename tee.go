@@ -0,0 +1,91 @@
+package powerset
+
+// SlowConsumerPolicy decides what Tee does when a branch's buffer is full
+type SlowConsumerPolicy int
+
+const (
+	// Block waits for the slow branch, so every branch sees every subset, but all of them go at the pace of the
+	// slowest.  this is the default
+	Block SlowConsumerPolicy = iota
+
+	// Drop skips the subset for a branch whose buffer is full, so a slow branch sees a sample of the enumeration
+	Drop
+
+	// Disconnect closes a branch whose buffer is full and stops feeding it, so a stalled or abandoned branch can't hold
+	// up the others
+	Disconnect
+)
+
+// WithBranchBuffer gives each branch of Tee a buffer of size subsets, to absorb differences in the pace of the branches
+func WithBranchBuffer(size int) Option {
+	return func(o *options) {
+		o.branchBuffer = size
+	}
+}
+
+// WithSlowConsumer sets what Tee does when a branch's buffer is full
+func WithSlowConsumer(policy SlowConsumerPolicy) Option {
+	return func(o *options) {
+		o.slowConsumer = policy
+	}
+}
+
+// Tee broadcasts the subsets received on in to k branches, so that several analysis stages can consume one
+// enumeration without running the generator again.  the first branch receives the original slices and every other
+// branch its own copies, so the branches can't interfere with each other.  Tee understands WithBranchBuffer and
+// WithSlowConsumer.  every branch is closed once in is closed
+func Tee(in <-chan []int, k int, opts ...Option) []<-chan []int {
+	o := buildOptions(opts)
+
+	branches := make([]chan []int, k)
+	outs := make([]<-chan []int, k)
+	for i := range branches {
+		branches[i] = make(chan []int, o.branchBuffer)
+		outs[i] = branches[i]
+	}
+
+	go func() {
+		connected := make([]bool, k)
+		for i := range connected {
+			connected[i] = true
+		}
+		defer func() {
+			for i, branch := range branches {
+				if connected[i] {
+					close(branch)
+				}
+			}
+		}()
+
+		for subset := range in {
+			for i, branch := range branches {
+				if !connected[i] {
+					continue
+				}
+				value := subset
+				if i > 0 {
+					value = append([]int{}, subset...)
+				}
+
+				switch o.slowConsumer {
+				case Drop:
+					select {
+					case branch <- value:
+					default:
+					}
+				case Disconnect:
+					select {
+					case branch <- value:
+					default:
+						connected[i] = false
+						close(branch)
+					}
+				default:
+					branch <- value
+				}
+			}
+		}
+	}()
+
+	return outs
+}
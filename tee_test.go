@@ -0,0 +1,89 @@
+package powerset
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestTee(t *testing.T) {
+	cfg := Config{LenItems: 5}
+	out, _, _ := Family(cfg)
+	branches := Tee(out, 3)
+
+	results := make([][][]int, len(branches))
+	wg := sync.WaitGroup{}
+	for i, branch := range branches {
+		wg.Add(1)
+		go func(i int, branch <-chan []int) {
+			defer wg.Done()
+			for subset := range branch {
+				results[i] = append(results[i], subset)
+			}
+		}(i, branch)
+	}
+	wg.Wait()
+
+	correct := collectFamily(t, cfg)
+	for i, allValues := range results {
+		if !reflect.DeepEqual(allValues, correct) {
+			t.Fatalf("branch %d:\n%v\n\n!=\n\n%v", i, allValues, correct)
+		}
+	}
+	if &results[0][1][0] == &results[1][1][0] {
+		t.Fatalf("expected the branches to get their own slices")
+	}
+}
+
+// sends subsets {0} to {n-1} on an unbuffered channel, closing sent once the last has been received, by which time Tee
+// has finished broadcasting every one before it
+func teeInput(n int) (in <-chan []int, sent <-chan bool) {
+	unbuffered := make(chan []int)
+	done := make(chan bool)
+	go func() {
+		defer close(unbuffered)
+		for i := 0; i < n; i++ {
+			unbuffered <- []int{i}
+		}
+		close(done)
+	}()
+	return unbuffered, done
+}
+
+func TestTeeDrop(t *testing.T) {
+	in, sent := teeInput(10)
+	branches := Tee(in, 2, WithBranchBuffer(4), WithSlowConsumer(Drop))
+	<-sent
+
+	// the branches aren't read until every subset has been broadcast, so they keep the first 4 subsets and drop the
+	// rest, except for the last, which may have been broadcast after the reading started
+	for i := len(branches) - 1; i >= 0; i-- {
+		allValues := [][]int{}
+		for subset := range branches[i] {
+			allValues = append(allValues, subset)
+		}
+		if len(allValues) == 5 && reflect.DeepEqual(allValues[4], []int{9}) {
+			allValues = allValues[:4]
+		}
+		if correct := [][]int{{0}, {1}, {2}, {3}}; !reflect.DeepEqual(allValues, correct) {
+			t.Fatalf("branch %d:\n%v\n\n!=\n\n%v", i, allValues, correct)
+		}
+	}
+}
+
+func TestTeeDisconnect(t *testing.T) {
+	in, sent := teeInput(5)
+	branches := Tee(in, 2, WithBranchBuffer(2), WithSlowConsumer(Disconnect))
+	<-sent
+
+	// a branch that isn't read is disconnected once its buffer fills, instead of blocking the enumeration
+	for i, branch := range branches {
+		got := 0
+		for range branch {
+			got++
+		}
+		if got != 2 {
+			t.Fatalf("branch %d: expected the buffered 2 subsets, got %d", i, got)
+		}
+	}
+}
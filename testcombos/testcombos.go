@@ -0,0 +1,263 @@
+// Package testcombos runs a table driven test over combinations of boolean flags, as one subtest per combination.  it
+// can run every combination, every combination of at most a few flags, or a small set of combinations that still has
+// every pair of flags in all four of their states somewhere
+package testcombos
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/amoffat/powerset"
+)
+
+// Option configures which combinations Each runs
+type Option func(*options)
+
+type options struct {
+	maxSize  int
+	pairwise bool
+}
+
+// MaxSize only runs the combinations with at most k flags enabled
+func MaxSize(k int) Option {
+	return func(o *options) {
+		o.maxSize = k
+	}
+}
+
+// Pairwise runs just enough combinations that every two flags are seen enabled together, disabled together, and each
+// enabled without the other, instead of every combination.  the number of combinations grows with the logarithm of the
+// number of flags, for suites where the interactions between more than two flags are rare
+func Pairwise() Option {
+	return func(o *options) {
+		o.pairwise = true
+	}
+}
+
+// Each runs fn as a subtest of t for each combination of flags, with enabled saying which of the flags are on.  the
+// subtests are named after their enabled flags, joined with "+", or "none".  without Pairwise, the combinations come
+// out in the same order as powerset.Family, where the first flag is the first index
+func Each(t *testing.T, flags []string, fn func(t *testing.T, enabled map[string]bool), opts ...Option) {
+	t.Helper()
+	o := &options{maxSize: -1}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	seen := map[string]bool{}
+	for _, flag := range flags {
+		if seen[flag] {
+			t.Fatalf("testcombos: flag %q appears more than once", flag)
+		}
+		seen[flag] = true
+	}
+
+	var combos [][]int
+	if o.pairwise {
+		combos = pairwise(len(flags), o.maxSize)
+	} else {
+		combos = exhaustive(len(flags), o.maxSize)
+	}
+
+	for _, combo := range combos {
+		enabled := make(map[string]bool, len(flags))
+		names := []string{}
+		for _, flag := range flags {
+			enabled[flag] = false
+		}
+		for _, idx := range combo {
+			enabled[flags[idx]] = true
+			names = append(names, flags[idx])
+		}
+
+		name := "none"
+		if len(names) > 0 {
+			name = strings.Join(names, "+")
+		}
+		t.Run(name, func(t *testing.T) {
+			fn(t, enabled)
+		})
+	}
+}
+
+// exhaustive returns every combination of n flags with at most maxSize enabled, or any number if maxSize is negative
+func exhaustive(n int, maxSize int) [][]int {
+	cfg := powerset.Config{LenItems: n}
+	switch {
+	case maxSize == 0:
+		for idx := 0; idx < n; idx++ {
+			cfg.Forbidden = append(cfg.Forbidden, idx)
+		}
+	case maxSize > 0:
+		cfg.MaxSize = maxSize
+	}
+
+	out, _, err := powerset.Family(cfg)
+	if err != nil {
+		panic(err)
+	}
+	combos := [][]int{}
+	for combo := range out {
+		combos = append(combos, combo)
+	}
+	return combos
+}
+
+// pairwise returns the coveringArray of n flags when maxSize doesn't limit them, and otherwise greedily builds
+// combinations until every pair of flags has been seen in each of their four states, except the states with more flags
+// enabled than maxSize allows.  each combination starts from the first pair state that hasn't been seen yet, and then
+// decides the rest of the flags in order, enabling a flag when that sees more new pair states with the flags decided so
+// far than disabling it does
+func pairwise(n int, maxSize int) [][]int {
+	if maxSize < 0 || maxSize >= n {
+		if n >= 2 {
+			return coveringArray(n)
+		}
+		maxSize = n
+	}
+
+	// seen[i][j][a][b] for i < j is whether flag i has been a while flag j was b
+	type states [2][2]bool
+	seen := make([][]states, n)
+	unseen := 0
+	for i := range seen {
+		seen[i] = make([]states, n)
+		for j := i + 1; j < n; j++ {
+			if maxSize < 2 {
+				seen[i][j][1][1] = true
+			}
+			if maxSize < 1 {
+				seen[i][j][0][1], seen[i][j][1][0] = true, true
+			}
+			for _, s := range seen[i][j] {
+				for _, ok := range s {
+					if !ok {
+						unseen++
+					}
+				}
+			}
+		}
+	}
+	value := func(b bool) int {
+		if b {
+			return 1
+		}
+		return 0
+	}
+
+	combos := [][]int{}
+	if n < 2 {
+		combos = exhaustive(n, maxSize)
+	}
+	for unseen > 0 {
+		decided := make([]bool, n)
+		on := make([]bool, n)
+		size := 0
+		set := func(idx int, enable bool) {
+			decided[idx] = true
+			on[idx] = enable
+			if enable {
+				size++
+			}
+		}
+
+	seed:
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				for a := 0; a < 2; a++ {
+					for b := 0; b < 2; b++ {
+						if !seen[i][j][a][b] {
+							set(i, a == 1)
+							set(j, b == 1)
+							break seed
+						}
+					}
+				}
+			}
+		}
+
+		// how many unseen pair states deciding idx would see
+		gain := func(idx int, enable bool) int {
+			count := 0
+			for other := 0; other < n; other++ {
+				if other == idx || !decided[other] {
+					continue
+				}
+				i, j, a, b := idx, other, value(enable), value(on[other])
+				if other < idx {
+					i, j, a, b = other, idx, b, a
+				}
+				if !seen[i][j][a][b] {
+					count++
+				}
+			}
+			return count
+		}
+		for idx := 0; idx < n; idx++ {
+			if !decided[idx] {
+				set(idx, size < maxSize && gain(idx, true) > gain(idx, false))
+			}
+		}
+
+		combo := []int{}
+		for i := 0; i < n; i++ {
+			if on[i] {
+				combo = append(combo, i)
+			}
+			for j := i + 1; j < n; j++ {
+				s := &seen[i][j][value(on[i])][value(on[j])]
+				if !*s {
+					*s = true
+					unseen--
+				}
+			}
+		}
+		combos = append(combos, combo)
+	}
+	return combos
+}
+
+// coveringArray returns the fewest combinations of n flags that see every pair of flags in each of their four states.
+// in the first combination every flag is disabled, and in the rest each flag follows the included indices of its own
+// subset of size ceil(N/2) of N-1 items, where N is the number of combinations.  subsets of the same size never contain
+// each other, so every two flags are seen in both of the states where one is enabled, and two subsets of more than half
+// of the items always overlap, so they're seen enabled together.  N is the smallest number with enough of those subsets
+// for every flag, which is the optimum found by Kleitman and Spencer
+func coveringArray(n int) [][]int {
+	rows := 2
+	for binomial(rows-1, (rows+1)/2) < n {
+		rows++
+	}
+
+	cfg := powerset.Config{LenItems: rows - 1, MinSize: (rows + 1) / 2, MaxSize: (rows + 1) / 2}
+	out, stop, err := powerset.Family(cfg)
+	if err != nil {
+		panic(err)
+	}
+	combos := make([][]int, rows)
+	combos[0] = []int{}
+	flag := 0
+	for column := range out {
+		for _, row := range column {
+			combos[row+1] = append(combos[row+1], flag)
+		}
+		if flag++; flag == n {
+			break
+		}
+	}
+	stop()
+	for i := range combos {
+		if combos[i] == nil {
+			combos[i] = []int{}
+		}
+	}
+	return combos
+}
+
+func binomial(n, k int) int {
+	result := 1
+	for i := 0; i < k; i++ {
+		result = result * (n - i) / (i + 1)
+	}
+	return result
+}
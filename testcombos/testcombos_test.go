@@ -0,0 +1,105 @@
+package testcombos
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEach(t *testing.T) {
+	names := []string{}
+	Each(t, []string{"cache", "gzip", "tls"}, func(t *testing.T, enabled map[string]bool) {
+		if len(enabled) != 3 {
+			t.Fatalf("expected every flag in enabled, got %v", enabled)
+		}
+		names = append(names, t.Name())
+	})
+
+	correct := []string{
+		"TestEach/none",
+		"TestEach/tls",
+		"TestEach/gzip",
+		"TestEach/gzip+tls",
+		"TestEach/cache",
+		"TestEach/cache+tls",
+		"TestEach/cache+gzip",
+		"TestEach/cache+gzip+tls",
+	}
+	if !reflect.DeepEqual(names, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", names, correct)
+	}
+}
+
+func TestEachMaxSize(t *testing.T) {
+	for maxSize, correct := range []int{1, 5, 11} {
+		runs := 0
+		Each(t, []string{"a", "b", "c", "d"}, func(t *testing.T, enabled map[string]bool) {
+			on := 0
+			for _, isOn := range enabled {
+				if isOn {
+					on++
+				}
+			}
+			if on > maxSize {
+				t.Fatalf("%d flags enabled, expected at most %d", on, maxSize)
+			}
+			runs++
+		}, MaxSize(maxSize))
+		if runs != correct {
+			t.Fatalf("expected %d runs with a MaxSize of %d, got %d", correct, maxSize, runs)
+		}
+	}
+}
+
+// checks that every pair of flags is seen in every state the size limit allows
+func checkPairwise(t *testing.T, n int, maxSize int, combos [][]int) {
+	on := func(combo []int, idx int) int {
+		for _, i := range combo {
+			if i == idx {
+				return 1
+			}
+		}
+		return 0
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			seen := [2][2]bool{}
+			for _, combo := range combos {
+				if len(combo) > maxSize {
+					t.Fatalf("%v has more than %d flags enabled", combo, maxSize)
+				}
+				seen[on(combo, i)][on(combo, j)] = true
+			}
+			for a := 0; a < 2; a++ {
+				for b := 0; b < 2; b++ {
+					if !seen[a][b] && a+b <= maxSize {
+						t.Fatalf("flags %d and %d were never %d and %d", i, j, a, b)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestPairwise(t *testing.T) {
+	for _, n := range []int{2, 3, 10, 40} {
+		combos := pairwise(n, -1)
+		checkPairwise(t, n, n, combos)
+		if n == 40 && len(combos) != 9 {
+			t.Fatalf("expected 9 combinations for %d flags, got %d", n, len(combos))
+		}
+	}
+	for _, maxSize := range []int{0, 1, 2, 3} {
+		checkPairwise(t, 8, maxSize, pairwise(8, maxSize))
+	}
+}
+
+func TestEachPairwise(t *testing.T) {
+	flags := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+	runs := 0
+	Each(t, flags, func(t *testing.T, enabled map[string]bool) {
+		runs++
+	}, Pairwise())
+	if runs == 0 || runs >= 1<<len(flags) {
+		t.Fatalf("unexpected number of pairwise runs %d", runs)
+	}
+}
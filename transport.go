@@ -0,0 +1,147 @@
+package powerset
+
+import "time"
+
+// Transport delivers the subsets of a search to its consumer.  Start sends on a channel by default, and on a ring
+// buffer with WithRingBuffer, and WithTransport plugs in any other strategy, like a direct callback, batches, or shared
+// memory for a co-process, without touching the traversal.  the search calls Send from a single goroutine, and Close
+// once after the last Send, before Stop returns
+type Transport interface {
+	// Send delivers a subset, blocking while the consumer can't take it.  it returns false if stop is closed first, or
+	// if the consumer doesn't want any more subsets, either of which stops the search
+	Send(subset []int, stop <-chan bool) bool
+
+	// Close tells the consumer that nothing more will be sent
+	Close()
+}
+
+// WithTransport makes Start deliver subsets through t instead of its output channel, which is nil.  t takes the place
+// of WithRingBuffer.  with a borrowing Ownership, a subset may be recycled as soon as Send returns, or for a
+// BatchTransport, as soon as its batch has been delivered
+func WithTransport(t Transport) Option {
+	return func(o *options) {
+		o.transport = t
+	}
+}
+
+// CallbackTransport calls fn with each subset in the search's goroutine, which skips the handoff to another goroutine
+// altogether.  the search stops if fn returns false
+func CallbackTransport(fn func(subset []int) bool) Transport {
+	return callbackTransport(fn)
+}
+
+// BatchTransport collects subsets into batches of size, and calls fn with each full batch in the search's goroutine,
+// then with the last partial batch when the search ends, which amortizes the cost of a delivery over many subsets.
+// the search stops if fn returns false.  fn may keep each batch it's given, but not borrowed subsets in it
+func BatchTransport(size int, fn func(batch [][]int) bool) Transport {
+	if size < 1 {
+		size = 1
+	}
+	return &batchTransport{size: size, fn: fn, batch: make([][]int, 0, size)}
+}
+
+type callbackTransport func(subset []int) bool
+
+func (fn callbackTransport) Send(subset []int, stop <-chan bool) bool {
+	select {
+	case <-stop:
+		return false
+	default:
+	}
+	return fn(subset)
+}
+
+func (fn callbackTransport) Close() {}
+
+type batchTransport struct {
+	size  int
+	fn    func(batch [][]int) bool
+	batch [][]int
+}
+
+func (t *batchTransport) Send(subset []int, stop <-chan bool) bool {
+	select {
+	case <-stop:
+		return false
+	default:
+	}
+	t.batch = append(t.batch, subset)
+	if len(t.batch) < t.size {
+		return true
+	}
+	return t.flush()
+}
+
+func (t *batchTransport) flush() bool {
+	batch := t.batch
+	t.batch = make([][]int, 0, t.size)
+	return t.fn(batch)
+}
+
+func (t *batchTransport) Close() {
+	if len(t.batch) > 0 {
+		t.flush()
+	}
+}
+
+// channelTransport is the default Transport, which sends on the output channel of Start.  only a send that has to wait
+// is timed, so the consumer keeping up costs nothing
+type channelTransport struct {
+	out    chan<- []int
+	waited func(start time.Time)
+}
+
+func (t *channelTransport) Send(subset []int, stop <-chan bool) bool {
+	select {
+	case t.out <- subset:
+		return true
+	default:
+	}
+	start := time.Now()
+	defer t.waited(start)
+	select {
+	case <-stop:
+		return false
+	case t.out <- subset:
+		return true
+	}
+}
+
+func (t *channelTransport) Close() {
+	close(t.out)
+}
+
+// borrowedTransport sends on the output channel of StartBorrowed
+type borrowedTransport struct {
+	out    chan<- Borrowed[[]int]
+	waited func(start time.Time)
+}
+
+func (t *borrowedTransport) Send(subset []int, stop <-chan bool) bool {
+	borrowed := Borrowed[[]int]{value: subset, clone: cloneInts}
+	select {
+	case t.out <- borrowed:
+		return true
+	default:
+	}
+	start := time.Now()
+	defer t.waited(start)
+	select {
+	case <-stop:
+		return false
+	case t.out <- borrowed:
+		return true
+	}
+}
+
+func (t *borrowedTransport) Close() {
+	close(t.out)
+}
+
+func (r *spscRing) Send(subset []int, stop <-chan bool) bool {
+	return r.push(subset, stop)
+}
+
+func (r *spscRing) Close() {
+	r.close()
+}
@@ -0,0 +1,76 @@
+package powerset
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// startTransport starts a search with a transport, and waits for it to end
+func startTransport(t *testing.T, cfg Config, tr Transport, opts ...Option) *Control {
+	out, ctl, err := Start(cfg, append(opts, WithTransport(tr))...)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != nil {
+		t.Fatalf("expected a nil channel with a transport")
+	}
+	for ctl.Reason() == ReasonRunning {
+		time.Sleep(time.Millisecond)
+	}
+	// the transport is closed before Stop returns
+	ctl.Stop()
+	return ctl
+}
+
+func TestCallbackTransport(t *testing.T) {
+	cfg := Config{LenItems: 5, MinSize: 2}
+	correct := collectFamily(t, cfg)
+
+	got := [][]int{}
+	ctl := startTransport(t, cfg, CallbackTransport(func(subset []int) bool {
+		got = append(got, subset)
+		return true
+	}))
+	if !reflect.DeepEqual(got, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", got, correct)
+	}
+	if ctl.Reason() != ReasonCompleted {
+		t.Fatalf("expected the search to complete, got %v", ctl.Reason())
+	}
+}
+
+func TestCallbackTransportStops(t *testing.T) {
+	count := 0
+	ctl := startTransport(t, Config{LenItems: 20}, CallbackTransport(func([]int) bool {
+		count++
+		return count < 10
+	}))
+	if count != 10 || ctl.Reason() != ReasonStopped {
+		t.Fatalf("expected the search to stop after 10 subsets, got %d and %v", count, ctl.Reason())
+	}
+}
+
+func TestBatchTransport(t *testing.T) {
+	cfg := Config{LenItems: 6}
+	correct := collectFamily(t, cfg)
+
+	for _, own := range []Ownership{Copy, Borrow} {
+		got := [][]int{}
+		sizes := []int{}
+		startTransport(t, cfg, BatchTransport(10, func(batch [][]int) bool {
+			sizes = append(sizes, len(batch))
+			for _, subset := range batch {
+				got = append(got, cloneInts(subset))
+			}
+			return true
+		}), WithOwnership(own))
+
+		if !reflect.DeepEqual(got, correct) {
+			t.Fatalf("\n%v\n\n!=\n\n%v", got, correct)
+		}
+		if len(sizes) != 7 || sizes[0] != 10 || sizes[6] != 4 {
+			t.Fatalf("unexpected batch sizes %v", sizes)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+// Package tui renders a live terminal view of a running search: its throughput, the sizes of the subsets it has
+// emitted, the best score found so far, how much of the powerset it has skipped, and an estimate of the time left.  it
+// only reads the search's Stats, so it can be attached to any search started with powerset.Start, and only uses ANSI
+// escape codes, so it works in any terminal without extra dependencies
+package tui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/amoffat/powerset"
+)
+
+// Source is the search a View watches.  a *powerset.Control is a Source
+type Source interface {
+	Stats() powerset.Stats
+}
+
+// Option configures a View
+type Option func(*View)
+
+// WithIncumbent shows the best score found so far, as reported by score, which returns false while there is none
+func WithIncumbent(score func() (float64, bool)) Option {
+	return func(v *View) {
+		v.incumbent = score
+	}
+}
+
+// WithInterval sets how often Run redraws the view
+func WithInterval(d time.Duration) Option {
+	return func(v *View) {
+		v.interval = d
+	}
+}
+
+// the widest bar of the size histogram
+const barWidth = 40
+
+// View is a live view of a search over the powerset of a number of items.  the size histogram, the skip ratio and the
+// time left need the exact subset counts of the search's Stats, so they're only shown for searches started with
+// powerset.WithSizeStats
+type View struct {
+	src       Source
+	w         io.Writer
+	total     *big.Int
+	incumbent func() (float64, bool)
+	interval  time.Duration
+
+	started time.Time
+
+	// the previous snapshot, which throughput is measured against
+	last     powerset.Stats
+	lastTime time.Time
+}
+
+// New creates a view of src, a search over the powerset of lenItems items, that draws to w
+func New(w io.Writer, src Source, lenItems int, opts ...Option) *View {
+	now := time.Now()
+	v := &View{
+		src:      src,
+		w:        w,
+		total:    new(big.Int).Lsh(big.NewInt(1), uint(lenItems)),
+		interval: time.Second,
+		started:  now,
+		lastTime: now,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Run redraws the view every interval until ctx is done, and draws it a last time before returning
+func (v *View) Run(ctx context.Context) {
+	ticker := time.NewTicker(v.interval)
+	defer ticker.Stop()
+	for {
+		v.draw()
+		select {
+		case <-ctx.Done():
+			v.draw()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// draw writes a frame of the view.  it moves the cursor to the top left and clears the screen first, so each frame
+// replaces the last
+func (v *View) draw() {
+	fmt.Fprint(v.w, "\x1b[H\x1b[2J"+v.Render())
+}
+
+// Render returns the view of the search as it is now, as plain text
+func (v *View) Render() string {
+	now := time.Now()
+	stats := v.src.Stats()
+	elapsed := now.Sub(v.started)
+
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "elapsed     %v\n", elapsed.Round(time.Second))
+	if dt := now.Sub(v.lastTime).Seconds(); dt > 0 {
+		fmt.Fprintf(b, "throughput  %.0f subsets/s, %.0f nodes/s\n",
+			float64(stats.Emitted-v.last.Emitted)/dt, float64(stats.Nodes-v.last.Nodes)/dt)
+	}
+	fmt.Fprintf(b, "emitted     %d\n", stats.Emitted)
+	if v.incumbent != nil {
+		if score, ok := v.incumbent(); ok {
+			fmt.Fprintf(b, "incumbent   %g\n", score)
+		} else {
+			fmt.Fprintf(b, "incumbent   none yet\n")
+		}
+	}
+
+	if stats.BySize != nil {
+		skipped, covered := new(big.Int), new(big.Int).SetUint64(stats.Emitted+stats.Suppressed)
+		for _, size := range stats.BySize {
+			skipped.Add(skipped, size.Pruned)
+		}
+		covered.Add(covered, skipped)
+		fmt.Fprintf(b, "skipped     %s of the powerset\n", percent(skipped, v.total))
+		fmt.Fprintf(b, "done        %s\n", percent(covered, v.total))
+		if eta, ok := remaining(elapsed, covered, v.total); ok {
+			fmt.Fprintf(b, "eta         %v\n", eta.Round(time.Second))
+		}
+		b.WriteString("\nemitted by size\n")
+		b.WriteString(histogram(stats.BySize))
+	} else {
+		fmt.Fprintf(b, "pruned      %d subtrees\n", stats.Pruned)
+	}
+
+	v.last, v.lastTime = stats, now
+	return b.String()
+}
+
+// percent formats part as a percentage of whole, with more decimals for small fractions
+func percent(part *big.Int, whole *big.Int) string {
+	f, _ := new(big.Rat).SetFrac(part, whole).Float64()
+	return fmt.Sprintf("%.4g%%", 100*f)
+}
+
+// remaining estimates the time left from how long it took to cover the subsets covered so far
+func remaining(elapsed time.Duration, covered *big.Int, total *big.Int) (time.Duration, bool) {
+	if covered.Sign() == 0 {
+		return 0, false
+	}
+	left := new(big.Rat).SetFrac(new(big.Int).Sub(total, covered), covered)
+	ratio, _ := left.Float64()
+	eta := float64(elapsed) * ratio
+	if eta > float64(1<<62) {
+		return 0, false
+	}
+	return time.Duration(eta), true
+}
+
+// histogram draws a bar for each subset size that has been emitted, scaled to the largest
+func histogram(sizes []powerset.SizeStats) string {
+	most := uint64(0)
+	for _, size := range sizes {
+		if size.Emitted > most {
+			most = size.Emitted
+		}
+	}
+
+	b := &strings.Builder{}
+	for k, size := range sizes {
+		if size.Emitted == 0 {
+			continue
+		}
+		width := int(size.Emitted * barWidth / most)
+		if width == 0 {
+			width = 1
+		}
+		fmt.Fprintf(b, "%4d %s %d\n", k, strings.Repeat("#", width), size.Emitted)
+	}
+	return b.String()
+}
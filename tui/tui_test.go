@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amoffat/powerset"
+)
+
+type fixedSource struct {
+	stats powerset.Stats
+}
+
+func (s *fixedSource) Stats() powerset.Stats {
+	return s.stats
+}
+
+func TestRender(t *testing.T) {
+	src := &fixedSource{powerset.Stats{
+		Emitted: 4,
+		Nodes:   20,
+		BySize: []powerset.SizeStats{
+			{Emitted: 0, Pruned: big.NewInt(1)},
+			{Emitted: 1, Pruned: big.NewInt(3)},
+			{Emitted: 3, Pruned: big.NewInt(4)},
+			{Emitted: 0, Pruned: big.NewInt(0)},
+		},
+	}}
+	v := New(&bytes.Buffer{}, src, 4, WithIncumbent(func() (float64, bool) { return 2.5, true }))
+	frame := v.Render()
+
+	for _, line := range []string{
+		"emitted     4\n",
+		"incumbent   2.5\n",
+		"skipped     50% of the powerset\n",
+		"done        75%\n",
+		"   1 ############# 1\n",
+		"   2 ######################################## 3\n",
+	} {
+		if !strings.Contains(frame, line) {
+			t.Fatalf("expected %q in\n%s", line, frame)
+		}
+	}
+	if strings.Contains(frame, "   3 ") {
+		t.Fatalf("sizes with nothing emitted shouldn't be drawn:\n%s", frame)
+	}
+}
+
+func TestRenderWithoutSizeStats(t *testing.T) {
+	v := New(&bytes.Buffer{}, &fixedSource{powerset.Stats{Pruned: 7}}, 10)
+	frame := v.Render()
+	if !strings.Contains(frame, "pruned      7 subtrees") || strings.Contains(frame, "eta") {
+		t.Fatalf("unexpected frame\n%s", frame)
+	}
+}
+
+func TestRun(t *testing.T) {
+	out, ctl, err := powerset.Start(powerset.Config{LenItems: 10}, powerset.WithSizeStats())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for range out {
+	}
+
+	w := &bytes.Buffer{}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	New(w, ctl, 10, WithInterval(10*time.Millisecond)).Run(ctx)
+	if !strings.Contains(w.String(), "done        100%") {
+		t.Fatalf("unexpected output\n%s", w.String())
+	}
+}
+
+func TestRunDrawsLastFrame(t *testing.T) {
+	out, ctl, err := powerset.Start(powerset.Config{LenItems: 10})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for range out {
+	}
+
+	w := &bytes.Buffer{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	New(w, ctl, 10, WithInterval(time.Hour)).Run(ctx)
+	if got := strings.Count(w.String(), "\x1b[H\x1b[2J"); got != 2 {
+		t.Fatalf("\n%v\n\n!=\n\n%v", got, 2)
+	}
+}
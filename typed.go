@@ -0,0 +1,52 @@
+package powerset
+
+// TypedOut is what a TypedCallback sends its results to, in place of the untyped output channel of a NodeCallback
+type TypedOut[S any, D any] struct {
+	solutions   chan<- interface{}
+	diagnostics chan<- D
+}
+
+// Solution sends a solution.  solutions are what WithMaxSolutions counts
+func (out TypedOut[S, D]) Solution(solution S) {
+	out.solutions <- solution
+}
+
+// Diagnostic sends something about the search that isn't a solution, like a near miss or the value of a bound
+func (out TypedOut[S, D]) Diagnostic(diagnostic D) {
+	out.diagnostics <- diagnostic
+}
+
+// TypedCallback is a NodeCallback that sends solutions of type S and diagnostics of type D
+type TypedCallback[S any, D any] func(path Path, isLeaf bool, state interface{}, out TypedOut[S, D]) (bool, int,
+	interface{})
+
+// Typed are the outputs of CallbackTyped
+type Typed[S any, D any] struct {
+	Solutions   <-chan S
+	Diagnostics <-chan D
+}
+
+// CallbackTyped is Callback with separate, typed channels for solutions and diagnostics, so consumers don't have to
+// type switch every value they receive.  the traversal blocks on whichever channel the callback sends to, so unless the
+// callback never sends diagnostics, both channels must be read at once, like from a single select loop.  they're both
+// closed once the traversal is done.  CallbackTyped understands the same options as Callback
+func CallbackTyped[S any, D any](lenItems int, cb TypedCallback[S, D], state interface{}, opts ...Option) Typed[S, D] {
+	solutions := make(chan S)
+	diagnostics := make(chan D)
+
+	// solutions go through Callback's own channel, so that a solution limit counts them and nothing else
+	untyped := func(path Path, isLeaf bool, state interface{}, out chan<- interface{}) (bool, int, interface{}) {
+		return cb(path, isLeaf, state, TypedOut[S, D]{out, diagnostics})
+	}
+	out := Callback(lenItems, untyped, state, opts...)
+
+	go func() {
+		defer close(solutions)
+		defer close(diagnostics)
+		for value := range out {
+			solution, _ := value.(S)
+			solutions <- solution
+		}
+	}()
+	return Typed[S, D]{Solutions: solutions, Diagnostics: diagnostics}
+}
@@ -0,0 +1,71 @@
+package powerset
+
+import (
+	"reflect"
+	"testing"
+)
+
+// collectTyped reads both channels of a CallbackTyped until they're closed
+func collectTyped[S any, D any](typed Typed[S, D]) ([]S, []D) {
+	solutions, diagnostics := []S{}, []D{}
+	for typed.Solutions != nil || typed.Diagnostics != nil {
+		select {
+		case solution, ok := <-typed.Solutions:
+			if !ok {
+				typed.Solutions = nil
+				continue
+			}
+			solutions = append(solutions, solution)
+		case diagnostic, ok := <-typed.Diagnostics:
+			if !ok {
+				typed.Diagnostics = nil
+				continue
+			}
+			diagnostics = append(diagnostics, diagnostic)
+		}
+	}
+	return solutions, diagnostics
+}
+
+// the subsets of exactly two items are solutions, and the ones of three are near misses, given as their size
+func pairs(path Path, isLeaf bool, state interface{}, out TypedOut[[]int, int]) (bool, int, interface{}) {
+	if !isLeaf {
+		return false, 0, nil
+	}
+	subset := []int{}
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i].Included {
+			subset = append(subset, path[i].Index)
+		}
+	}
+	switch len(subset) {
+	case 2:
+		out.Solution(subset)
+	case 3:
+		out.Diagnostic(len(subset))
+	}
+	return false, 0, nil
+}
+
+func TestCallbackTyped(t *testing.T) {
+	solutions, diagnostics := collectTyped(CallbackTyped(4, pairs, nil))
+	correct := [][]int{{2, 3}, {1, 3}, {1, 2}, {0, 3}, {0, 2}, {0, 1}}
+	if !reflect.DeepEqual(solutions, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", solutions, correct)
+	}
+	if !reflect.DeepEqual(diagnostics, []int{3, 3, 3, 3}) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", diagnostics, []int{3, 3, 3, 3})
+	}
+}
+
+// diagnostics don't count towards a solution limit
+func TestCallbackTypedMaxSolutions(t *testing.T) {
+	solutions, diagnostics := collectTyped(CallbackTyped(4, pairs, nil, WithMaxSolutions(4)))
+	correct := [][]int{{2, 3}, {1, 3}, {1, 2}, {0, 3}}
+	if !reflect.DeepEqual(solutions, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", solutions, correct)
+	}
+	if !reflect.DeepEqual(diagnostics, []int{3}) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", diagnostics, []int{3})
+	}
+}
@@ -0,0 +1,8 @@
+// Package powerset is version 2 of github.com/amoffat/powerset.  it gathers the features of version 1 behind one
+// consistent surface: every search takes a context, results are typed with generics and consumed as range-over-func
+// iterators, optional behavior is set with the same functional options everywhere, breaking out of a loop stops the
+// search without leaking its goroutine, and callbacks return a typed Decision instead of a (bool, int) pair.
+//
+// version 2 runs on the version 1 engine, and its Config, Constraint, Option and Stats types are the version 1 types,
+// so every version 1 option and constraint works here unchanged, and downstreams can move one call site at a time
+package powerset
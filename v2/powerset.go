@@ -0,0 +1,145 @@
+package powerset
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	v1 "github.com/amoffat/powerset"
+)
+
+// Config describes a family of subsets, exactly as in version 1
+type Config = v1.Config
+
+// Constraint is a named rule over the sorted included indices of a subset
+type Constraint = v1.Constraint
+
+// Option configures the optional behavior of a search.  every version 1 Option is accepted
+type Option = v1.Option
+
+// Stats is a snapshot of the progress of a search
+type Stats = v1.Stats
+
+// Order is the order in which a search emits its subsets
+type Order = v1.Order
+
+// Path is the pathway from the root of the powerset tree to a node
+type Path = v1.Path
+
+const (
+	OrderCanonical = v1.OrderCanonical
+	OrderZigZag    = v1.OrderZigZag
+	OrderGray      = v1.OrderGray
+	OrderLocality  = v1.OrderLocality
+	OrderBySize    = v1.OrderBySize
+)
+
+// WithOrder makes a search emit its subsets in the given order
+func WithOrder(order Order) Option {
+	return v1.WithOrder(order)
+}
+
+// WithMaxSolutions ends a search cleanly after k results
+func WithMaxSolutions(k int) Option {
+	return v1.WithMaxSolutions(k)
+}
+
+// WithRateLimit limits a search to perSecond results per second
+func WithRateLimit(perSecond float64) Option {
+	return v1.WithRateLimit(perSecond)
+}
+
+// WithMemoryBudget bounds the memory held by a search's buffers, filters and caches
+func WithMemoryBudget(bytes uint64) Option {
+	return v1.WithMemoryBudget(bytes)
+}
+
+// WithSizeStats breaks the Stats of a search down by subset size
+func WithSizeStats() Option {
+	return v1.WithSizeStats()
+}
+
+// Search is a running search over the subsets of a slice of items
+type Search[T any] struct {
+	items []T
+	out   <-chan []int
+	ctl   *v1.Control
+}
+
+// Subsets searches every subset of items, in the canonical order.  it's Family with no rules
+func Subsets[T any](ctx context.Context, items []T, opts ...Option) (*Search[T], error) {
+	return Family(ctx, items, Config{LenItems: len(items)}, opts...)
+}
+
+// Family searches the members of the family described by cfg over items, where index i is items[i].  a cfg with no
+// LenItems is over every item, and otherwise LenItems must be len(items).  the search ends when ctx is done, with the
+// context's error from Err
+func Family[T any](ctx context.Context, items []T, cfg Config, opts ...Option) (*Search[T], error) {
+	if cfg.LenItems == 0 {
+		cfg.LenItems = len(items)
+	}
+	if cfg.LenItems != len(items) {
+		return nil, fmt.Errorf("powerset: config has %d items, but there are %d", cfg.LenItems, len(items))
+	}
+
+	opts = append([]Option{v1.WithContext(ctx)}, opts...)
+	out, ctl, err := v1.Start(cfg, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Search[T]{items: items, out: out, ctl: ctl}, nil
+}
+
+// All yields every subset of the search as the items it includes, in order.  breaking out of the loop stops the
+// search.  a search can only be iterated once, by All or Indices
+func (s *Search[T]) All() iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		for indices := range s.Indices() {
+			subset := make([]T, len(indices))
+			for i, idx := range indices {
+				subset[i] = s.items[idx]
+			}
+			if !yield(subset) {
+				return
+			}
+		}
+	}
+}
+
+// Indices yields every subset of the search as its sorted included indices.  breaking out of the loop stops the
+// search
+func (s *Search[T]) Indices() iter.Seq[[]int] {
+	return func(yield func([]int) bool) {
+		defer s.ctl.Stop()
+		for indices := range s.out {
+			if !yield(indices) {
+				return
+			}
+		}
+	}
+}
+
+// Stop ends the search and waits for it to finish.  breaking out of a loop over the search already does this
+func (s *Search[T]) Stop() {
+	s.ctl.Stop()
+}
+
+// Pause suspends the search until Resume
+func (s *Search[T]) Pause() {
+	s.ctl.Pause()
+}
+
+// Resume continues a paused search
+func (s *Search[T]) Resume() {
+	s.ctl.Resume()
+}
+
+// Stats returns a snapshot of the search's progress
+func (s *Search[T]) Stats() Stats {
+	return s.ctl.Stats()
+}
+
+// Err returns why the search ended early, like the context's error if it was cancelled, or nil
+func (s *Search[T]) Err() error {
+	return s.ctl.Err()
+}
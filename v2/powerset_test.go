@@ -0,0 +1,207 @@
+package powerset
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestSubsets(t *testing.T) {
+	s, err := Subsets(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := [][]string{}
+	for subset := range s.All() {
+		got = append(got, subset)
+	}
+	correct := [][]string{{}, {"b"}, {"a"}, {"a", "b"}}
+	if !reflect.DeepEqual(got, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", got, correct)
+	}
+}
+
+func TestFamily(t *testing.T) {
+	items := []int{10, 20, 30, 40}
+	s, err := Family(context.Background(), items, Config{MinSize: 3}, WithOrder(OrderGray))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := [][]int{}
+	for subset := range s.All() {
+		got = append(got, subset)
+	}
+	if len(got) != 5 {
+		t.Fatalf("expected 5 subsets, got %v", got)
+	}
+	if s.Err() != nil || s.Stats().Emitted != 5 {
+		t.Fatalf("unexpected end: %v, %+v", s.Err(), s.Stats())
+	}
+
+	if _, err := Family(context.Background(), items, Config{LenItems: 3}); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestFamilyBreakDoesntLeak(t *testing.T) {
+	before := runtime.NumGoroutine()
+	for i := 0; i < 20; i++ {
+		s, _ := Subsets(context.Background(), make([]int, 40))
+		for range s.Indices() {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	if after := runtime.NumGoroutine(); after > before+2 {
+		t.Fatalf("goroutines leaked: %d before, %d after", before, after)
+	}
+}
+
+func TestFamilyContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s, _ := Subsets(ctx, make([]int, 40))
+	count := 0
+	for range s.Indices() {
+		count++
+		if count == 3 {
+			cancel()
+		}
+	}
+	if s.Err() != context.Canceled {
+		t.Fatalf("expected context.Canceled, got %v", s.Err())
+	}
+}
+
+func TestWalk(t *testing.T) {
+	// the leaves with at most one index, pruning as soon as a second is included
+	visit := func(node Node[int], emit func([]int) bool) (Decision, int) {
+		count := node.State
+		if len(node.Path) > 0 && node.Path[0].Included {
+			count++
+		}
+		if count > 1 {
+			return Prune(), count
+		}
+		if node.Leaf {
+			indices := []int{}
+			for _, seg := range node.Path {
+				if seg.Included {
+					indices = append([]int{seg.Index}, indices...)
+				}
+			}
+			emit(indices)
+		}
+		return Continue(), count
+	}
+
+	got := [][]int{}
+	for leaf := range Walk(context.Background(), 3, 0, visit) {
+		got = append(got, leaf)
+	}
+	correct := [][]int{{}, {2}, {1}, {0}}
+	if !reflect.DeepEqual(got, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", got, correct)
+	}
+}
+
+func TestWalkStop(t *testing.T) {
+	visit := func(node Node[struct{}], emit func(int) bool) (Decision, struct{}) {
+		if len(node.Path) == 2 {
+			return Stop(), node.State
+		}
+		emit(len(node.Path))
+		return Continue(), node.State
+	}
+	got := []int{}
+	for depth := range Walk(context.Background(), 5, struct{}{}, visit) {
+		got = append(got, depth)
+	}
+	if !reflect.DeepEqual(got, []int{0, 1}) {
+		t.Fatalf("unexpected depths %v", got)
+	}
+}
+
+// a visitor that keeps emitting after its consumer has gone doesn't block forever
+func TestWalkBreak(t *testing.T) {
+	visit := func(node Node[int], emit func(int) bool) (Decision, int) {
+		for i := 0; emit(i); i++ {
+		}
+		return Stop(), 0
+	}
+	done := make(chan bool)
+	go func() {
+		for range Walk(context.Background(), 3, 0, visit) {
+			break
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("the walk didn't end")
+	}
+}
+
+func leafIndices(path Path) []int {
+	indices := []int{}
+	for _, seg := range path {
+		if seg.Included {
+			indices = append([]int{seg.Index}, indices...)
+		}
+	}
+	return indices
+}
+
+func TestWalkDefer(t *testing.T) {
+	// including index 0 or index 1 looks unpromising, so those subtrees are deferred
+	visit := func(node Node[int], emit func([]int) bool) (Decision, int) {
+		if node.Leaf {
+			emit(leafIndices(node.Path))
+			return Continue(), node.State
+		}
+		if len(node.Path) > 0 && node.Path[0].Included && node.Path[0].Index < 2 {
+			return Defer(), node.State + 1
+		}
+		return Continue(), node.State
+	}
+
+	got := [][]int{}
+	for leaf := range Walk(context.Background(), 3, 0, visit) {
+		got = append(got, leaf)
+	}
+	// the subtree below {1} is deferred in the main traversal, as is {0}, and {0 1} is deferred again while {0} is
+	// explored, so it comes last
+	correct := [][]int{{}, {2}, {1}, {1, 2}, {0}, {0, 2}, {0, 1}, {0, 1, 2}}
+	if !reflect.DeepEqual(got, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", got, correct)
+	}
+}
+
+func TestWalkDeferCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	visit := func(node Node[int], emit func(string) bool) (Decision, int) {
+		if len(node.Path) == 1 && node.Path[0].Included {
+			return Defer(), 0
+		}
+		if node.Leaf {
+			emit("leaf")
+		}
+		return Continue(), 0
+	}
+
+	count := 0
+	for range Walk(ctx, 4, 0, visit) {
+		count++
+		if count == 8 {
+			// the deferred half of the tree is never explored
+			cancel()
+		}
+	}
+	if count != 8 {
+		t.Fatalf("expected only the main traversal's 8 leaves, got %d", count)
+	}
+}
@@ -0,0 +1,146 @@
+package powerset
+
+import (
+	"context"
+	"iter"
+	"sync"
+
+	v1 "github.com/amoffat/powerset"
+)
+
+type decisionKind int
+
+const (
+	decideContinue decisionKind = iota
+	decidePrune
+	decideBacktrack
+	decideStop
+	decideDefer
+)
+
+// Decision is what a Visitor decides to do after visiting a node
+type Decision struct {
+	kind  decisionKind
+	depth int
+}
+
+// Continue explores the node's children
+func Continue() Decision {
+	return Decision{kind: decideContinue}
+}
+
+// Prune skips the node's subtree, and carries on with its next sibling, or the next sibling of its closest ancestor
+// that has one
+func Prune() Decision {
+	return Decision{kind: decidePrune}
+}
+
+// BacktrackTo abandons every subtree below the ancestor at depth, where the root is at depth 0, and carries on with
+// that ancestor's unexplored children
+func BacktrackTo(depth int) Decision {
+	return Decision{kind: decideBacktrack, depth: depth}
+}
+
+// Stop ends the walk
+func Stop() Decision {
+	return Decision{kind: decideStop}
+}
+
+// Defer postpones the node's subtree instead of discarding it, for heuristics that aren't sure whether a branch is
+// worthless.  the walk carries on as if the subtree was pruned, and deferred subtrees are explored once the rest of
+// the tree is done, in the order they were deferred, with the state returned alongside Defer.  a leaf has no subtree to
+// defer, so deferring it is the same as continuing
+func Defer() Decision {
+	return Decision{kind: decideDefer}
+}
+
+// Node is a node of the powerset tree visited by Walk
+type Node[S any] struct {
+	Path  Path
+	Leaf  bool
+	State S
+}
+
+// Visitor is called by Walk at every node of the powerset tree.  it sends results with emit, which returns false once
+// the walk's consumer is gone and nothing more should be sent, and returns what to do next with the state that the
+// node's children receive
+type Visitor[S any, R any] func(node Node[S], emit func(R) bool) (Decision, S)
+
+// Walk is the typed version of Callback: it calls visit at every node of the powerset tree of lenItems items, starting
+// with state at the root, and yields whatever visit emits.  breaking out of the loop, or ctx being done, ends the walk
+// and unblocks any emit in progress.  Walk understands the options that Callback does, which apply to the main
+// traversal.  the subtrees deferred with Defer are then explored in rounds, where the subtrees deferred during one
+// round are explored in the next, for as long as the loop keeps going and ctx isn't done, so a deadline on ctx is the
+// budget for deferred work
+func Walk[S any, R any](ctx context.Context, lenItems int, state S, visit Visitor[S, R], opts ...Option) iter.Seq[R] {
+	return func(yield func(R) bool) {
+		done := make(chan struct{})
+		var once sync.Once
+		quit := func() { once.Do(func() { close(done) }) }
+
+		// the subtrees deferred in the current round, and the states their children start from.  they're only touched
+		// by the traversal's goroutine until its output is closed
+		var deferred []Path
+		var deferredStates []interface{}
+		stopped := false
+
+		cb := func(path v1.Path, isLeaf bool, st interface{}, out chan<- interface{}) (bool, int, interface{}) {
+			select {
+			case <-done:
+				return true, -1, nil
+			case <-ctx.Done():
+				return true, -1, nil
+			default:
+			}
+
+			emit := func(r R) bool {
+				select {
+				case out <- r:
+					return true
+				case <-done:
+				case <-ctx.Done():
+				}
+				return false
+			}
+			s, _ := st.(S)
+			decision, next := visit(Node[S]{Path: path, Leaf: isLeaf, State: s}, emit)
+			switch decision.kind {
+			case decidePrune:
+				return true, len(path) - 1, next
+			case decideBacktrack:
+				return true, decision.depth, next
+			case decideStop:
+				stopped = true
+				return true, -1, next
+			case decideDefer:
+				if isLeaf {
+					break
+				}
+				deferred = append(deferred, append(Path{}, path...))
+				deferredStates = append(deferredStates, next)
+				return true, len(path) - 1, next
+			}
+			return false, 0, next
+		}
+
+		out := v1.Callback(lenItems, cb, state, opts...)
+		defer func() {
+			quit()
+			for range out {
+			}
+		}()
+		for {
+			for value := range out {
+				if !yield(value.(R)) {
+					return
+				}
+			}
+			if len(deferred) == 0 || stopped || ctx.Err() != nil {
+				return
+			}
+			partials, states := deferred, deferredStates
+			deferred, deferredStates = nil, nil
+			out = v1.FromFrontier(lenItems, partials, cb, states...)
+		}
+	}
+}
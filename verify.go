@@ -0,0 +1,58 @@
+package powerset
+
+import (
+	"fmt"
+	"sort"
+)
+
+// VerificationError describes why a claimed solution isn't a member of a family.  Rule is "MinSize", "MaxSize",
+// "Required", "Forbidden", the Name of the Constraint that rejected the subset, or "Shard" for a subset outside of the
+// Config's Shard
+type VerificationError struct {
+	Subset []int
+	Rule   string
+}
+
+func (err *VerificationError) Error() string {
+	return fmt.Sprintf("powerset: subset %v violates %s", err.Subset, err.Rule)
+}
+
+// Verify re-checks that subset is a member of the family described by cfg, over a universe of n items.  it's for
+// cheaply validating solutions that were found somewhere else, like on a distributed worker, before accepting them.
+// the subset may be in any order.  a subset that is well formed but isn't in the family is reported with a
+// *VerificationError
+func Verify(n int, subset []int, cfg Config) error {
+	if n != cfg.LenItems {
+		return fmt.Errorf("powerset: subset is over %d items but the Config is over %d", n, cfg.LenItems)
+	}
+	fam, err := cfg.compile()
+	if err != nil {
+		return err
+	}
+
+	sorted, err := sortSubset(n, subset)
+	if err != nil {
+		return err
+	}
+
+	if ok, rule := fam.contains(sorted); !ok {
+		return &VerificationError{Subset: sorted, Rule: rule}
+	}
+	return nil
+}
+
+// sortSubset returns a sorted copy of a subset of n items, checking that each of its indices is in range and appears
+// only once
+func sortSubset(n int, subset []int) ([]int, error) {
+	sorted := append([]int{}, subset...)
+	sort.Ints(sorted)
+	for i, idx := range sorted {
+		if idx < 0 || idx >= n {
+			return nil, fmt.Errorf("powerset: index %d out of range [0, %d)", idx, n)
+		}
+		if i > 0 && idx == sorted[i-1] {
+			return nil, fmt.Errorf("powerset: index %d appears more than once", idx)
+		}
+	}
+	return sorted, nil
+}
@@ -0,0 +1,94 @@
+package powerset
+
+import "testing"
+
+func TestVerify(t *testing.T) {
+	evenSum := Constraint{
+		Name: "even sum",
+		Allow: func(indices []int) bool {
+			sum := 0
+			for _, idx := range indices {
+				sum += idx
+			}
+			return sum%2 == 0
+		},
+	}
+	cfg := Config{
+		LenItems:    6,
+		MinSize:     2,
+		MaxSize:     4,
+		Required:    []int{1},
+		Forbidden:   []int{5},
+		Constraints: []Constraint{evenSum},
+	}
+
+	if err := Verify(6, []int{3, 1}, cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	violations := []struct {
+		subset []int
+		rule   string
+	}{
+		{[]int{1}, "MinSize"},
+		{[]int{0, 1, 2, 3, 4}, "MaxSize"},
+		{[]int{0, 2}, "Required"},
+		{[]int{1, 5}, "Forbidden"},
+		{[]int{1, 2}, "even sum"},
+	}
+	for _, v := range violations {
+		err := Verify(6, v.subset, cfg)
+		verr, ok := err.(*VerificationError)
+		if !ok {
+			t.Fatalf("%v: expected a *VerificationError, got %v", v.subset, err)
+		}
+		if verr.Rule != v.rule {
+			t.Fatalf("%v: rule %q != %q", v.subset, verr.Rule, v.rule)
+		}
+	}
+}
+
+func TestVerifyMalformed(t *testing.T) {
+	cfg := Config{LenItems: 3}
+	malformed := [][]int{
+		{3},
+		{-1},
+		{1, 1},
+	}
+	for _, subset := range malformed {
+		err := Verify(3, subset, cfg)
+		if err == nil {
+			t.Fatalf("%v: expected an error", subset)
+		}
+		if _, ok := err.(*VerificationError); ok {
+			t.Fatalf("%v: malformed subsets shouldn't be reported as violations", subset)
+		}
+	}
+
+	if err := Verify(4, []int{0}, cfg); err == nil {
+		t.Fatalf("expected a universe size mismatch to be an error")
+	}
+}
+
+func TestVerifyFamilyMembers(t *testing.T) {
+	cfg := Config{LenItems: 5, MinSize: 1, MaxSize: 3, Forbidden: []int{2}}
+	out, _, _ := Family(cfg)
+	for indices := range out {
+		if err := Verify(5, indices, cfg); err != nil {
+			t.Fatalf("%v: unexpected error: %v", indices, err)
+		}
+	}
+}
+
+func TestVerifyShard(t *testing.T) {
+	// the second of 4 shards of 4 items is every subset that includes 1 and not 0
+	cfg := Config{LenItems: 4, Shard: &ShardSpec{Count: 4, Index: 1}, Order: OrderGray}
+	if err := Verify(4, []int{3, 1}, cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := Verify(4, []int{0, 1}, cfg)
+	verr, ok := err.(*VerificationError)
+	if !ok || verr.Rule != "Shard" {
+		t.Fatalf("expected a Shard VerificationError, got %v", err)
+	}
+}
@@ -0,0 +1,186 @@
+package powerset
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// WeightedSubset is a subset with its total weight, the sum of the weights of its indices
+type WeightedSubset struct {
+	Subset []int
+	Weight float64
+}
+
+// ByWeight generates every subset of len(weights) items in nondecreasing total weight, where weights[i] is the weight
+// of index i, so a consumer with a budget can stop as soon as the weight goes over it.  subsets of equal weight come
+// out in no particular order.  the search is best-first: a heap holds the frontier of subsets that haven't been emitted
+// yet, keyed by their weight, and each emitted subset pushes at most two successors, so the frontier is never larger
+// than the number of subsets emitted so far.  negative weights are allowed, in which case the lightest subset is the
+// one of every negative index, not the empty subset
+func ByWeight(weights []float64) (<-chan WeightedSubset, func(), error) {
+	for i, w := range weights {
+		if math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, nil, fmt.Errorf("powerset: weight %d is %v", i, w)
+		}
+	}
+	ws := newWeightSearch(weights)
+
+	out := make(chan WeightedSubset)
+	stopIn := make(chan bool)
+
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		defer close(out)
+		defer wg.Done()
+
+		for {
+			subset, ok := ws.next()
+			if !ok {
+				return
+			}
+			select {
+			case <-stopIn:
+				return
+			case out <- subset:
+			}
+		}
+	}()
+
+	stop := func() {
+		close(stopIn)
+		wg.Wait()
+	}
+	return out, stop, nil
+}
+
+// KSmallest returns the k subsets of len(weights) items with the smallest total weights, lightest first, without
+// looking at the other 2^n-k subsets.  like ByWeight, each subset found is a deviation from one found before it, by
+// adding or swapping in the next lightest index, and the lightest deviation not yet taken is the next subset, so the
+// work is O(k log k) heap operations.  ties are broken arbitrarily, and fewer than k subsets are returned when there
+// aren't that many
+func KSmallest(weights []float64, k int) ([]WeightedSubset, error) {
+	for i, w := range weights {
+		if math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, fmt.Errorf("powerset: weight %d is %v", i, w)
+		}
+	}
+	ws := newWeightSearch(weights)
+	subsets := []WeightedSubset{}
+	for len(subsets) < k {
+		subset, ok := ws.next()
+		if !ok {
+			break
+		}
+		subsets = append(subsets, subset)
+	}
+	return subsets, nil
+}
+
+// weightSearch enumerates subsets by weight.  negative weights are flipped, so every weight is nonnegative, and ranks
+// are positions in the order of increasing flipped weight.  the subsets of ranks are then generated from the empty one
+// by two moves on the highest rank j of a subset: adding rank j+1, and replacing j with j+1.  neither move makes a
+// subset lighter, and every nonempty subset is reached from exactly one parent, so popping the lightest subset off the
+// heap and pushing its successors emits every subset once, in order.  a subset of ranks is mapped back to indices by
+// toggling them in the subset of every negative index
+type weightSearch struct {
+	// byRank[r] is the index of rank r, and abs[r] is its flipped weight
+	byRank []int
+	abs    []float64
+
+	negative []bool
+	base     float64
+
+	frontier weightHeap
+	started  bool
+}
+
+func newWeightSearch(weights []float64) *weightSearch {
+	ws := &weightSearch{
+		byRank:   make([]int, len(weights)),
+		abs:      make([]float64, len(weights)),
+		negative: make([]bool, len(weights)),
+	}
+	for idx, w := range weights {
+		ws.byRank[idx] = idx
+		if w < 0 {
+			ws.negative[idx] = true
+			ws.base += w
+		}
+	}
+	sort.SliceStable(ws.byRank, func(a, b int) bool {
+		return math.Abs(weights[ws.byRank[a]]) < math.Abs(weights[ws.byRank[b]])
+	})
+	for r, idx := range ws.byRank {
+		ws.abs[r] = math.Abs(weights[idx])
+	}
+	return ws
+}
+
+func (ws *weightSearch) next() (WeightedSubset, bool) {
+	if !ws.started {
+		ws.started = true
+		if len(ws.abs) > 0 {
+			heap.Push(&ws.frontier, weightNode{ranks: []int{0}, weight: ws.abs[0]})
+		}
+		return ws.subset(nil, 0), true
+	}
+	if ws.frontier.Len() == 0 {
+		return WeightedSubset{}, false
+	}
+
+	node := heap.Pop(&ws.frontier).(weightNode)
+	last := len(node.ranks) - 1
+	if j := node.ranks[last]; j+1 < len(ws.abs) {
+		added := append(append(make([]int, 0, len(node.ranks)+1), node.ranks...), j+1)
+		heap.Push(&ws.frontier, weightNode{ranks: added, weight: node.weight + ws.abs[j+1]})
+
+		replaced := append(make([]int, 0, len(node.ranks)), node.ranks...)
+		replaced[last] = j + 1
+		heap.Push(&ws.frontier, weightNode{ranks: replaced, weight: node.weight - ws.abs[j] + ws.abs[j+1]})
+	}
+	return ws.subset(node.ranks, node.weight), true
+}
+
+// subset maps a subset of ranks, and its flipped weight, back to indices and their total weight
+func (ws *weightSearch) subset(ranks []int, weight float64) WeightedSubset {
+	in := make([]bool, len(ws.negative))
+	copy(in, ws.negative)
+	for _, r := range ranks {
+		in[ws.byRank[r]] = !in[ws.byRank[r]]
+	}
+
+	subset := []int{}
+	for idx, included := range in {
+		if included {
+			subset = append(subset, idx)
+		}
+	}
+	return WeightedSubset{Subset: subset, Weight: ws.base + weight}
+}
+
+type weightNode struct {
+	ranks  []int
+	weight float64
+}
+
+// weightHeap is a min-heap of subsets of ranks by their flipped weight
+type weightHeap []weightNode
+
+func (h weightHeap) Len() int           { return len(h) }
+func (h weightHeap) Less(i, j int) bool { return h[i].weight < h[j].weight }
+func (h weightHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *weightHeap) Push(x interface{}) {
+	*h = append(*h, x.(weightNode))
+}
+
+func (h *weightHeap) Pop() interface{} {
+	old := *h
+	node := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return node
+}
@@ -0,0 +1,139 @@
+package powerset
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func collectByWeight(t *testing.T, weights []float64) []WeightedSubset {
+	out, stop, err := ByWeight(weights)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer stop()
+
+	subsets := []WeightedSubset{}
+	for s := range out {
+		subsets = append(subsets, s)
+	}
+	return subsets
+}
+
+func TestByWeight(t *testing.T) {
+	subsets := collectByWeight(t, []float64{3, 1, 2})
+	weights := []float64{}
+	for _, s := range subsets {
+		weights = append(weights, s.Weight)
+	}
+	correct := []float64{0, 1, 2, 3, 3, 4, 5, 6}
+	if !reflect.DeepEqual(weights, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", weights, correct)
+	}
+	if !reflect.DeepEqual(subsets[len(subsets)-1].Subset, []int{0, 1, 2}) {
+		t.Fatalf("unexpected heaviest subset %v", subsets[len(subsets)-1])
+	}
+}
+
+// every subset comes out once, with its own weight, in nondecreasing order
+func TestByWeightRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for trial := 0; trial < 20; trial++ {
+		weights := make([]float64, rng.Intn(8))
+		for i := range weights {
+			weights[i] = math.Round(rng.Float64()*20-5) / 2
+		}
+
+		subsets := collectByWeight(t, weights)
+		if len(subsets) != 1<<len(weights) {
+			t.Fatalf("%v: expected %d subsets, got %d", weights, 1<<len(weights), len(subsets))
+		}
+		seen := map[uint64]bool{}
+		for i, s := range subsets {
+			if !sort.IntsAreSorted(s.Subset) {
+				t.Fatalf("unsorted subset %v", s.Subset)
+			}
+			mask, sum := uint64(0), 0.0
+			for _, idx := range s.Subset {
+				mask |= 1 << uint(idx)
+				sum += weights[idx]
+			}
+			if seen[mask] {
+				t.Fatalf("%v: duplicate subset %v", weights, s.Subset)
+			}
+			seen[mask] = true
+			if math.Abs(sum-s.Weight) > 1e-9 {
+				t.Fatalf("%v: subset %v weighs %v, not %v", weights, s.Subset, sum, s.Weight)
+			}
+			if i > 0 && s.Weight < subsets[i-1].Weight-1e-9 {
+				t.Fatalf("%v: %v comes after the heavier %v", weights, s, subsets[i-1])
+			}
+		}
+	}
+}
+
+func TestByWeightStop(t *testing.T) {
+	out, stop, err := ByWeight(make([]float64, 40))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-out
+	<-out
+	stop()
+}
+
+func TestByWeightInvalid(t *testing.T) {
+	if _, _, err := ByWeight([]float64{1, math.NaN()}); err == nil {
+		t.Fatalf("expected an error")
+	}
+}
+
+func TestKSmallest(t *testing.T) {
+	weights := []float64{4, -1, 2.5, 0.5, 3}
+	all := collectByWeight(t, weights)
+	for _, k := range []int{0, 1, 5, 32, 40} {
+		smallest, err := KSmallest(weights, k)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		correct := all
+		if k < len(all) {
+			correct = all[:k]
+		}
+		if len(smallest) != len(correct) {
+			t.Fatalf("k=%d: expected %d subsets, got %d", k, len(correct), len(smallest))
+		}
+		for i := range smallest {
+			if smallest[i].Weight != correct[i].Weight {
+				t.Fatalf("k=%d: \n%v\n\n!=\n\n%v", k, smallest, correct)
+			}
+		}
+	}
+
+	smallest, _ := KSmallest(weights, 2)
+	correct := []WeightedSubset{{[]int{1}, -1}, {[]int{1, 3}, -0.5}}
+	if !reflect.DeepEqual(smallest, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", smallest, correct)
+	}
+}
+
+func TestKSmallestLarge(t *testing.T) {
+	weights := make([]float64, 200)
+	for i := range weights {
+		weights[i] = float64(i + 1)
+	}
+	smallest, err := KSmallest(weights, 4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := []float64{}
+	for _, s := range smallest {
+		got = append(got, s.Weight)
+	}
+	correct := []float64{0, 1, 2, 3}
+	if !reflect.DeepEqual(got, correct) {
+		t.Fatalf("\n%v\n\n!=\n\n%v", got, correct)
+	}
+}